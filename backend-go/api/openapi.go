@@ -0,0 +1,14 @@
+// Package api holds the public contract of the HTTP API: the request and
+// response types under v1 and the OpenAPI document generated from them.
+package api
+
+import _ "embed"
+
+//go:generate go run ../cmd/openapi -root .. -o openapi.json
+//go:generate go run ../cmd/openapi -root .. -format typescript -o ../../frontend-vue/src/services/api.gen.ts
+
+// OpenAPISpec is the OpenAPI 3 document of the API, generated from the
+// annotations of the HTTP handlers by go generate.
+//
+//go:embed openapi.json
+var OpenAPISpec []byte
@@ -0,0 +1,5 @@
+// Package proto holds the contract of the gRPC API: the protobuf definitions
+// of its services and the Go code generated from them.
+package proto
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative auth/v1/auth.proto
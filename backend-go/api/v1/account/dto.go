@@ -0,0 +1,18 @@
+package account
+
+type DeleteAccountResponse struct {
+	// PurgeAt is the Unix time after which the account and all its content are removed.
+	// Logging in before then cancels the deletion.
+	PurgeAt int64 `json:"purge_at"`
+}
+
+type ExportJobResponse struct {
+	ID          string `json:"id"`
+	Status      string `json:"status"`
+	CreatedAt   int64  `json:"created_at"`
+	CompletedAt int64  `json:"completed_at,omitempty"`
+	ExpiresAt   int64  `json:"expires_at,omitempty"`
+	Size        int64  `json:"size,omitempty"`
+	Error       string `json:"error,omitempty"`
+	DownloadURL string `json:"download_url,omitempty"`
+}
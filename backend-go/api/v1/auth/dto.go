@@ -2,15 +2,91 @@ package auth
 
 type RegisterRequest struct {
 	Username string `json:"username" validate:"required,min=3,max=32"`
-	Password string `json:"password" validate:"required,min=8"`
+	Password string `json:"password" validate:"required,max=256"` // strength is checked by the password policy
 	Email    string `json:"email" validate:"required,email"`
+	// InviteCode is required while registration is invite-only.
+	InviteCode string `json:"invite_code,omitempty" validate:"omitempty,max=64"`
 }
 
 type LoginRequest struct {
 	Username string `json:"username" validate:"required"`
 	Password string `json:"password" validate:"required"`
+	// DeviceName labels the session; derived from the user agent when empty.
+	DeviceName string `json:"device_name,omitempty" validate:"omitempty,max=64"`
 }
 
 type LoginResponse struct {
 	Token string `json:"token"`
 }
+
+type IdentityProvidersResponse struct {
+	Providers []string `json:"providers"`
+}
+
+type OIDCStartResponse struct {
+	AuthorizationURL string `json:"authorization_url"`
+}
+
+// OIDCCallbackRequest carries the parameters the identity provider appended
+// to the redirect URL.
+type OIDCCallbackRequest struct {
+	State string `json:"state" validate:"required"`
+	Code  string `json:"code" validate:"required"`
+}
+
+// ListUsersRequest holds the query parameters of the admin user listing.
+// CreatedAfter and CreatedBefore are Unix timestamps in seconds.
+type ListUsersRequest struct {
+	Query         string `form:"q" json:"q" validate:"omitempty,max=64"`
+	Role          string `form:"role" json:"role" validate:"omitempty,oneof=author admin"`
+	Verified      *bool  `form:"verified" json:"verified"`
+	CreatedAfter  int64  `form:"created_after" json:"created_after" validate:"omitempty,min=0"`
+	CreatedBefore int64  `form:"created_before" json:"created_before" validate:"omitempty,min=0"`
+	Cursor        string `form:"cursor" json:"cursor"`
+	Limit         int    `form:"limit" json:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+type CreatePersonalTokenRequest struct {
+	Name          string   `json:"name" validate:"required,max=64"`
+	Scopes        []string `json:"scopes" validate:"required,min=1,dive,required"`
+	ExpiresInDays int      `json:"expires_in_days" validate:"omitempty,min=1,max=365"` // omit for a token that never expires
+}
+
+// CreatePersonalTokenResponse is the only response that contains the token secret.
+type CreatePersonalTokenResponse struct {
+	ID        string   `json:"id"`
+	Name      string   `json:"name"`
+	Scopes    []string `json:"scopes"`
+	Token     string   `json:"token"`
+	CreatedAt int64    `json:"created_at"`
+	ExpiresAt int64    `json:"expires_at,omitempty"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required,max=256"`
+}
+
+// ListAuditEventsRequest holds the query parameters of the audit event listings.
+// UserID is only honoured on the admin listing.
+type ListAuditEventsRequest struct {
+	UserID  string `form:"user_id" json:"user_id"`
+	Action  string `form:"action" json:"action" validate:"omitempty,max=32"`
+	Outcome string `form:"outcome" json:"outcome" validate:"omitempty,oneof=success failure"`
+	Cursor  string `form:"cursor" json:"cursor"`
+	Limit   int    `form:"limit" json:"limit" validate:"omitempty,min=1,max=200"`
+}
+
+type CreateInviteRequest struct {
+	MaxUses       int `json:"max_uses" validate:"omitempty,min=1,max=1000"`      // defaults to a single use
+	ExpiresInDays int `json:"expires_in_days" validate:"omitempty,min=1,max=90"` // defaults to 7 days
+}
+
+// CreateInviteResponse is the only response that contains the invite code.
+type CreateInviteResponse struct {
+	ID        string `json:"id"`
+	Code      string `json:"code"`
+	MaxUses   int    `json:"max_uses"`
+	CreatedAt int64  `json:"created_at"`
+	ExpiresAt int64  `json:"expires_at"`
+}
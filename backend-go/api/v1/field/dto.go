@@ -1 +1,44 @@
-package dto
+package field
+
+// FieldOptions are the validation rules of a field. Which of them apply
+// depends on the kind of the field; setting one that does not is an error.
+type FieldOptions struct {
+	// Required rejects empty values. Applies to every kind.
+	Required bool `json:"required,omitempty"`
+	// MinLength and MaxLength bound text values, in characters.
+	MinLength int `json:"min_length,omitempty" validate:"omitempty,min=0"`
+	MaxLength int `json:"max_length,omitempty" validate:"omitempty,min=0"`
+	// Pattern is a regular expression short text values must match in full.
+	Pattern string `json:"pattern,omitempty" validate:"omitempty,max=500"`
+	// Min and Max bound number values; Integer rejects fractions.
+	Min     *float64 `json:"min,omitempty"`
+	Max     *float64 `json:"max,omitempty"`
+	Integer bool     `json:"integer,omitempty"`
+	// MinDate and MaxDate bound date values, as YYYY-MM-DD.
+	MinDate string `json:"min_date,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	MaxDate string `json:"max_date,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	// Choices are the values of a single or multi select field.
+	Choices []string `json:"choices,omitempty" validate:"omitempty,max=100,dive,required,max=100"`
+	// MinSelected and MaxSelected bound how many choices a multi select value has.
+	MinSelected int `json:"min_selected,omitempty" validate:"omitempty,min=0"`
+	MaxSelected int `json:"max_selected,omitempty" validate:"omitempty,min=0"`
+	// Schemes restricts URL values; http and https when empty.
+	Schemes []string `json:"schemes,omitempty" validate:"omitempty,max=10,dive,required,max=32"`
+	// TargetTemplateID restricts reference values to notes of this template.
+	TargetTemplateID string `json:"target_template_id,omitempty" validate:"omitempty,max=64"`
+}
+
+type CreateFieldRequest struct {
+	Name        string       `json:"name" validate:"required,max=64"`
+	Description string       `json:"description,omitempty" validate:"omitempty,max=500"`
+	Kind        string       `json:"kind" validate:"required,oneof=short_text long_text number date boolean single_select multi_select url reference"`
+	Options     FieldOptions `json:"options"`
+}
+
+// UpdateFieldRequest replaces the name, description and options of a field.
+// The kind of a field cannot change.
+type UpdateFieldRequest struct {
+	Name        string       `json:"name" validate:"required,max=64"`
+	Description string       `json:"description,omitempty" validate:"omitempty,max=500"`
+	Options     FieldOptions `json:"options"`
+}
@@ -0,0 +1,38 @@
+package note
+
+// CreateNoteRequest fills in a template. Values are keyed by field ID; fields
+// left out start with the default of their template slot.
+type CreateNoteRequest struct {
+	TemplateID string         `json:"template_id" validate:"required,max=64"`
+	Title      string         `json:"title" validate:"required,max=200"`
+	Values     map[string]any `json:"values,omitempty" validate:"omitempty,max=300"`
+}
+
+// UpdateNoteRequest replaces the title and values of a note. Values are
+// checked against the template version the note is pinned to.
+type UpdateNoteRequest struct {
+	Title  string         `json:"title" validate:"required,max=200"`
+	Values map[string]any `json:"values,omitempty" validate:"omitempty,max=300"`
+}
+
+// UpgradeNotesRequest moves notes of a template to its latest version.
+// Notes whose values do not fit the latest version are reported and left
+// alone, unless Force is set: then the offending values are archived.
+type UpgradeNotesRequest struct {
+	TemplateID string   `json:"template_id" validate:"required,max=64"`
+	NoteIDs    []string `json:"note_ids,omitempty" validate:"omitempty,max=1000,dive,required"` // all outdated notes when empty
+	DryRun     bool     `json:"dry_run,omitempty"`
+	Force      bool     `json:"force,omitempty"`
+}
+
+// ListNotesRequest holds the query parameters of the note listing. Value
+// matches the value of the field FieldID, or one of its choices for multi
+// select fields.
+type ListNotesRequest struct {
+	Query      string `form:"q" json:"q" validate:"omitempty,max=200"`
+	TemplateID string `form:"template_id" json:"template_id" validate:"omitempty,max=64"`
+	FieldID    string `form:"field_id" json:"field_id" validate:"required_with=Value,max=64"`
+	Value      string `form:"value" json:"value" validate:"required_with=FieldID,max=255"`
+	Cursor     string `form:"cursor" json:"cursor"`
+	Limit      int    `form:"limit" json:"limit" validate:"omitempty,min=1,max=100"`
+}
@@ -0,0 +1,21 @@
+package project
+
+// CreateProjectRequest starts a writing project.
+type CreateProjectRequest struct {
+	Title           string `json:"title" validate:"required,max=200"`
+	Genre           string `json:"genre,omitempty" validate:"omitempty,max=100"`
+	Synopsis        string `json:"synopsis,omitempty" validate:"omitempty,max=5000"`
+	TargetWordCount int    `json:"target_word_count,omitempty" validate:"omitempty,min=0,max=10000000"`
+	CoverImageURL   string `json:"cover_image_url,omitempty" validate:"omitempty,http_url,max=2048"`
+	Status          string `json:"status,omitempty" validate:"omitempty,oneof=planning drafting revising completed on_hold"` // planning when omitted
+}
+
+// UpdateProjectRequest replaces the details of a project.
+type UpdateProjectRequest struct {
+	Title           string `json:"title" validate:"required,max=200"`
+	Genre           string `json:"genre,omitempty" validate:"omitempty,max=100"`
+	Synopsis        string `json:"synopsis,omitempty" validate:"omitempty,max=5000"`
+	TargetWordCount int    `json:"target_word_count,omitempty" validate:"omitempty,min=0,max=10000000"`
+	CoverImageURL   string `json:"cover_image_url,omitempty" validate:"omitempty,http_url,max=2048"`
+	Status          string `json:"status" validate:"required,oneof=planning drafting revising completed on_hold"`
+}
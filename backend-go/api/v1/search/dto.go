@@ -0,0 +1,11 @@
+package search
+
+// SearchRequest holds the query parameters of a search. Query is a list of
+// words and "quoted phrases" that results must all contain.
+type SearchRequest struct {
+	Query      string `form:"q" json:"q" validate:"required,max=500"`
+	ProjectID  string `form:"project_id" json:"project_id" validate:"omitempty,max=64"`
+	TemplateID string `form:"template_id" json:"template_id" validate:"omitempty,max=64"`
+	Offset     int    `form:"offset" json:"offset" validate:"min=0,max=10000"`
+	Limit      int    `form:"limit" json:"limit" validate:"omitempty,min=1,max=50"`
+}
@@ -0,0 +1,78 @@
+package template
+
+import fieldv1 "github.com/AldiandyaIrsyad/author-notes/api/v1/field"
+
+// TemplateSlot places a field in a row of a template.
+type TemplateSlot struct {
+	FieldID string `json:"field_id" validate:"required,max=64"`
+	// Width is the number of columns the slot spans, 1 when omitted. The
+	// widths of a row add up to at most three.
+	Width int `json:"width,omitempty" validate:"omitempty,min=1,max=3"`
+	// Required makes the field required in notes of this template, even if
+	// the field definition itself is optional.
+	Required bool `json:"required,omitempty"`
+	// Default is the initial value of the field in new notes. It must be a
+	// valid value of the field.
+	Default any `json:"default,omitempty"`
+}
+
+// TemplateRow is a row of up to three field slots.
+type TemplateRow struct {
+	Slots []TemplateSlot `json:"slots" validate:"required,min=1,max=3,dive"`
+}
+
+type CreateTemplateRequest struct {
+	Name        string        `json:"name" validate:"required,max=100"`
+	Description string        `json:"description,omitempty" validate:"omitempty,max=1000"`
+	Rows        []TemplateRow `json:"rows" validate:"max=100,dive"`
+}
+
+// UpdateTemplateRequest replaces the name, description and layout of a
+// template, creating a new version of it.
+type UpdateTemplateRequest struct {
+	Name        string        `json:"name" validate:"required,max=100"`
+	Description string        `json:"description,omitempty" validate:"omitempty,max=1000"`
+	Rows        []TemplateRow `json:"rows" validate:"max=100,dive"`
+	// Renames maps the ID of a field removed by this version to the ID of the
+	// field added in its place. Upgrading notes moves their values accordingly.
+	Renames map[string]string `json:"renames,omitempty" validate:"omitempty,max=100"`
+}
+
+// ImportStartersRequest picks the built-in starter templates to import into a
+// project.
+type ImportStartersRequest struct {
+	Starters []string `json:"starters,omitempty" validate:"omitempty,max=50,unique,dive,required,max=64"` // keys of the starters, all when empty
+}
+
+// ImportTemplateRequest is a template in the portable interchange format, as
+// returned by the template export.
+type ImportTemplateRequest struct {
+	Format        string      `json:"format" validate:"required,max=64"`
+	SchemaVersion int         `json:"schema_version" validate:"required,min=1"`
+	Name          string      `json:"name" validate:"required,max=100"`
+	Description   string      `json:"description,omitempty" validate:"omitempty,max=1000"`
+	Rows          []ImportRow `json:"rows" validate:"max=100,dive"`
+}
+
+// ImportRow is a row of up to three slots of an imported template.
+type ImportRow struct {
+	Slots []ImportSlot `json:"slots" validate:"required,min=1,max=3,dive"`
+}
+
+// ImportSlot places a field definition in a row of an imported template.
+// Width, Required and Default work as in TemplateSlot.
+type ImportSlot struct {
+	Field    ImportField `json:"field"`
+	Width    int         `json:"width,omitempty" validate:"omitempty,min=1,max=3"`
+	Required bool        `json:"required,omitempty"`
+	Default  any         `json:"default,omitempty"`
+}
+
+// ImportField defines a field by name. Importing reuses the importer's field
+// with this name, which must be of the same kind, or creates it.
+type ImportField struct {
+	Name        string               `json:"name" validate:"required,max=64"`
+	Description string               `json:"description,omitempty" validate:"omitempty,max=500"`
+	Kind        string               `json:"kind" validate:"required,oneof=short_text long_text number date boolean single_select multi_select url reference"`
+	Options     fieldv1.FieldOptions `json:"options"`
+}
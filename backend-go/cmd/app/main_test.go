@@ -0,0 +1,92 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/AldiandyaIrsyad/author-notes/api"
+	account_adapter "github.com/AldiandyaIrsyad/author-notes/internal/account/adapter"
+	auth_adapter "github.com/AldiandyaIrsyad/author-notes/internal/auth/adapter"
+	field_adapter "github.com/AldiandyaIrsyad/author-notes/internal/field/adapter"
+	note_adapter "github.com/AldiandyaIrsyad/author-notes/internal/note/adapter"
+	"github.com/AldiandyaIrsyad/author-notes/internal/openapi"
+	project_adapter "github.com/AldiandyaIrsyad/author-notes/internal/project/adapter"
+	search_adapter "github.com/AldiandyaIrsyad/author-notes/internal/search/adapter"
+	template_adapter "github.com/AldiandyaIrsyad/author-notes/internal/template/adapter"
+)
+
+var ginParam = regexp.MustCompile(`:(\w+)`)
+
+// TestRoutesMatchSpec fails when the routes registered by the handlers, the
+// embedded OpenAPI document and the generated frontend client drift apart.
+// Run go generate ./api after changing a route, its annotations or its DTOs.
+func TestRoutesMatchSpec(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	router := gin.New()
+	registerAPIRoutes(router.Group("/v1"), apiHandlers{
+		auth:           auth_adapter.NewAuthHTTPHandler(nil, logger),
+		authMiddleware: auth_adapter.NewAuthMiddleware(nil, logger),
+		account:        account_adapter.NewAccountHTTPHandler(nil, logger),
+		project:        project_adapter.NewProjectHTTPHandler(nil, logger),
+		field:          field_adapter.NewFieldHTTPHandler(nil, logger),
+		template:       template_adapter.NewTemplateHTTPHandler(nil, logger),
+		note:           note_adapter.NewNoteHTTPHandler(nil, logger),
+		search:         search_adapter.NewSearchHTTPHandler(nil, logger),
+	})
+	var registered []string
+	for _, route := range router.Routes() {
+		registered = append(registered, route.Method+" "+ginParam.ReplaceAllString(route.Path, "{$1}"))
+	}
+	sort.Strings(registered)
+
+	var spec openapi.Document
+	require.NoError(t, json.Unmarshal(api.OpenAPISpec, &spec))
+	var documented []string
+	for path, item := range spec.Paths {
+		for method := range item {
+			documented = append(documented, strings.ToUpper(method)+" "+path)
+		}
+	}
+	sort.Strings(documented)
+
+	t.Run("every registered route is documented", func(t *testing.T) {
+		assert.Subset(t, documented, registered)
+	})
+
+	t.Run("every documented route is registered", func(t *testing.T) {
+		assert.Subset(t, registered, documented)
+	})
+
+	t.Run("embedded spec is up to date", func(t *testing.T) {
+		doc, err := openapi.Generate("../..")
+		require.NoError(t, err)
+		generated, err := openapi.Marshal(doc)
+		require.NoError(t, err)
+		assert.JSONEq(t, string(generated), string(api.OpenAPISpec), "api/openapi.json is stale, run go generate ./api")
+	})
+
+	t.Run("frontend client is up to date", func(t *testing.T) {
+		client, err := os.ReadFile("../../../frontend-vue/src/services/api.gen.ts")
+		if errors.Is(err, fs.ErrNotExist) {
+			t.Skip("frontend not checked out")
+		}
+		require.NoError(t, err)
+		generated, err := openapi.TypeScript(&spec)
+		require.NoError(t, err)
+		assert.Equal(t, string(generated), string(client), "frontend-vue/src/services/api.gen.ts is stale, run go generate ./api")
+	})
+}
@@ -0,0 +1,47 @@
+// Command openapi generates the OpenAPI document of the API from the swag
+// style annotations of its HTTP handlers, or a TypeScript client built from it.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/AldiandyaIrsyad/author-notes/internal/openapi"
+)
+
+func main() {
+	root := flag.String("root", ".", "directory of the go.mod of the module to document")
+	out := flag.String("o", "", "output file (default standard output)")
+	format := flag.String("format", "json", "output format: json or typescript")
+	flag.Parse()
+
+	doc, err := openapi.Generate(*root)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	var spec []byte
+	switch *format {
+	case "json":
+		spec, err = openapi.Marshal(doc)
+	case "typescript":
+		spec, err = openapi.TypeScript(doc)
+	default:
+		err = fmt.Errorf("unknown format %q", *format)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if *out == "" {
+		_, err = os.Stdout.Write(spec)
+	} else {
+		err = os.WriteFile(*out, spec, 0o644)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
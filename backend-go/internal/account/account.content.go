@@ -0,0 +1,17 @@
+package account
+
+import "context"
+
+// ContentProvider is implemented by every domain that stores content owned by a user,
+// so that data exports and account deletion reach all of it. The services of
+// such domains embed it in their interface: their content is exported with
+// and deleted with the account.
+type ContentProvider interface {
+	// Name identifies the kind of content, e.g. "notes". It is used as the
+	// directory of the provider's documents inside export archives.
+	Name() string
+	// ExportUserContent returns every document owned by the user.
+	ExportUserContent(ctx context.Context, userID string) ([]ExportDocument, error)
+	// DeleteUserContent permanently removes every document owned by the user.
+	DeleteUserContent(ctx context.Context, userID string) error
+}
@@ -0,0 +1,29 @@
+package account
+
+// ExportStatus is the lifecycle state of an ExportJob.
+type ExportStatus string
+
+const (
+	ExportPending   ExportStatus = "pending"
+	ExportCompleted ExportStatus = "completed"
+	ExportFailed    ExportStatus = "failed"
+)
+
+// ExportJob tracks the asynchronous creation of a personal data archive.
+type ExportJob struct {
+	ID          string       `bson:"_id,omitempty" json:"id"`
+	UserID      string       `bson:"user_id" json:"user_id"`
+	Status      ExportStatus `bson:"status" json:"status"`
+	Size        int64        `bson:"size" json:"size"`
+	Error       string       `bson:"error,omitempty" json:"error,omitempty"`
+	CreatedAt   int64        `bson:"created_at" json:"created_at"`
+	CompletedAt int64        `bson:"completed_at,omitempty" json:"completed_at,omitempty"`
+	ExpiresAt   int64        `bson:"expires_at,omitempty" json:"expires_at,omitempty"` // archive is no longer downloadable afterwards
+}
+
+// ExportDocument is a single piece of user-owned content included in an export archive.
+type ExportDocument struct {
+	ID       string // unique within its ContentProvider, used as the file name
+	Data     any    // marshalled to <id>.json
+	Markdown string // written to <id>.md when not empty
+}
@@ -0,0 +1,45 @@
+package account
+
+import (
+	"context"
+	"io"
+
+	"github.com/AldiandyaIrsyad/author-notes/internal/auth"
+)
+
+// UserStore is the subset of auth.AuthRepository used to manage accounts.
+type UserStore interface {
+	FindByID(ctx context.Context, id string) (*auth.User, error)
+	UpdateUser(ctx context.Context, user *auth.User) error
+	DeleteUser(ctx context.Context, id string) error
+	FindUsersDueForPurge(ctx context.Context, now int64) ([]*auth.User, error)
+	DeleteSessionsByUser(ctx context.Context, userID string) error
+}
+
+// ExportJobRepository defines the interface for export job database operations.
+type ExportJobRepository interface {
+	// CreateJob inserts a new export job.
+	CreateJob(ctx context.Context, job *ExportJob) error
+	// UpdateJob replaces a stored export job with the given one.
+	UpdateJob(ctx context.Context, job *ExportJob) error
+	// FindJobByID retrieves an export job by its ID.
+	// Returns ErrExportNotFound if the job does not exist.
+	FindJobByID(ctx context.Context, id string) (*ExportJob, error)
+	// FindLatestJob retrieves the most recently created export job of a user.
+	// Returns ErrExportNotFound if the user has none.
+	FindLatestJob(ctx context.Context, userID string) (*ExportJob, error)
+	// DeleteJobsByUser removes every export job of a user and returns their IDs.
+	DeleteJobsByUser(ctx context.Context, userID string) ([]string, error)
+}
+
+// ExportStore defines the interface for storing generated export archives.
+type ExportStore interface {
+	// Create opens a new archive for writing. The archive is only complete once
+	// the returned writer has been closed without error.
+	Create(ctx context.Context, jobID string) (io.WriteCloser, error)
+	// Open opens a completed archive for reading.
+	// Returns ErrExportNotFound if the archive does not exist.
+	Open(ctx context.Context, jobID string) (io.ReadCloser, error)
+	// Delete removes an archive. Deleting a missing archive is not an error.
+	Delete(ctx context.Context, jobID string) error
+}
@@ -0,0 +1,329 @@
+package account
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/AldiandyaIrsyad/author-notes/internal/auth"
+)
+
+// AccountService defines the interface for self-service account management.
+type AccountService interface {
+	// DeleteAccount soft-deletes the user and logs them out everywhere. The account
+	// and all content it owns are purged once the grace period has passed, unless
+	// the user logs in again.
+	DeleteAccount(ctx context.Context, userID string) (*auth.User, error)
+	// PurgeDeletedAccounts permanently removes accounts whose grace period has
+	// passed, cascading to every ContentProvider, and returns how many were removed.
+	PurgeDeletedAccounts(ctx context.Context) (int, error)
+	// CurrentExport returns the user's pending or still downloadable export,
+	// starting a new one if there is none.
+	CurrentExport(ctx context.Context, userID string) (*ExportJob, error)
+	// StartExport starts a new export unless one is already pending.
+	StartExport(ctx context.Context, userID string) (*ExportJob, error)
+	// OpenExport opens the archive of a completed export owned by the user.
+	OpenExport(ctx context.Context, userID, jobID string) (io.ReadCloser, *ExportJob, error)
+}
+
+type accountService struct {
+	users       UserStore
+	jobs        ExportJobRepository
+	store       ExportStore
+	providers   []ContentProvider
+	gracePeriod time.Duration
+	exportTTL   time.Duration
+	logger      *slog.Logger
+	// runAsync runs export jobs in the background; replaced in tests.
+	runAsync func(func())
+}
+
+// NewAccountService creates a new instance of AccountService.
+// providers are the domains whose user-owned content is exported and deleted.
+func NewAccountService(users UserStore, jobs ExportJobRepository, store ExportStore, logger *slog.Logger, providers ...ContentProvider) AccountService {
+	return &accountService{
+		users:       users,
+		jobs:        jobs,
+		store:       store,
+		providers:   providers,
+		gracePeriod: durationFromEnv("ACCOUNT_DELETION_GRACE_PERIOD", 30*24*time.Hour),
+		exportTTL:   durationFromEnv("EXPORT_TTL", 7*24*time.Hour),
+		logger:      logger.With("component", "account"),
+		runAsync:    func(f func()) { go f() },
+	}
+}
+
+// durationFromEnv parses a time.ParseDuration value from the environment,
+// falling back to def when it is unset or invalid.
+func durationFromEnv(key string, def time.Duration) time.Duration {
+	if d, err := time.ParseDuration(os.Getenv(key)); err == nil && d > 0 {
+		return d
+	}
+	return def
+}
+
+// DeleteAccount soft-deletes the user and schedules the purge.
+func (s *accountService) DeleteAccount(ctx context.Context, userID string) (*auth.User, error) {
+	user, err := s.users.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.DeletedAt != 0 {
+		// Already scheduled; keep the original purge time.
+		user.Password = ""
+		return user, nil
+	}
+
+	now := time.Now()
+	user.DeletedAt = now.Unix()
+	user.PurgeAt = now.Add(s.gracePeriod).Unix()
+	user.UpdatedAt = now.Unix()
+	if err := s.users.UpdateUser(ctx, user); err != nil {
+		return nil, err
+	}
+	if err := s.users.DeleteSessionsByUser(ctx, user.ID); err != nil {
+		return nil, err
+	}
+
+	user.Password = ""
+	return user, nil
+}
+
+// PurgeDeletedAccounts removes accounts whose grace period has passed.
+func (s *accountService) PurgeDeletedAccounts(ctx context.Context) (int, error) {
+	users, err := s.users.FindUsersDueForPurge(ctx, time.Now().Unix())
+	if err != nil {
+		return 0, err
+	}
+
+	var errs []error
+	purged := 0
+	for _, user := range users {
+		if err := s.purge(ctx, user.ID); err != nil {
+			errs = append(errs, fmt.Errorf("purge user %s: %w", user.ID, err))
+			continue
+		}
+		purged++
+	}
+	return purged, errors.Join(errs...)
+}
+
+// purge removes the content of a user before the user itself, so that a
+// failure leaves the account in place to be retried on the next run.
+func (s *accountService) purge(ctx context.Context, userID string) error {
+	for _, provider := range s.providers {
+		if err := provider.DeleteUserContent(ctx, userID); err != nil {
+			return fmt.Errorf("%s: %w", provider.Name(), err)
+		}
+	}
+
+	jobIDs, err := s.jobs.DeleteJobsByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, id := range jobIDs {
+		if err := s.store.Delete(ctx, id); err != nil {
+			return err
+		}
+	}
+
+	if err := s.users.DeleteUser(ctx, userID); err != nil && !errors.Is(err, auth.ErrUserNotFound) {
+		return err
+	}
+	return nil
+}
+
+// CurrentExport returns the latest usable export or starts a new one.
+func (s *accountService) CurrentExport(ctx context.Context, userID string) (*ExportJob, error) {
+	job, err := s.jobs.FindLatestJob(ctx, userID)
+	if err != nil && !errors.Is(err, ErrExportNotFound) {
+		return nil, err
+	}
+	if job != nil {
+		switch {
+		case job.Status == ExportPending:
+			return job, nil
+		case job.Status == ExportCompleted && time.Now().Unix() < job.ExpiresAt:
+			return job, nil
+		}
+	}
+	return s.createExport(ctx, userID)
+}
+
+// StartExport starts a new export unless one is already pending.
+func (s *accountService) StartExport(ctx context.Context, userID string) (*ExportJob, error) {
+	job, err := s.jobs.FindLatestJob(ctx, userID)
+	if err != nil && !errors.Is(err, ErrExportNotFound) {
+		return nil, err
+	}
+	if job != nil && job.Status == ExportPending {
+		return job, nil
+	}
+	return s.createExport(ctx, userID)
+}
+
+func (s *accountService) createExport(ctx context.Context, userID string) (*ExportJob, error) {
+	job := &ExportJob{
+		UserID:    userID,
+		Status:    ExportPending,
+		CreatedAt: time.Now().Unix(),
+	}
+	if err := s.jobs.CreateJob(ctx, job); err != nil {
+		return nil, err
+	}
+
+	// The export outlives the request that started it, but keeps its
+	// context values so that its logs carry the request ID.
+	jobCopy := *job
+	exportCtx := context.WithoutCancel(ctx)
+	s.runAsync(func() { s.runExport(exportCtx, &jobCopy) })
+	return job, nil
+}
+
+// runExport writes the archive of a job and records the outcome.
+func (s *accountService) runExport(ctx context.Context, job *ExportJob) {
+	size, err := s.writeArchive(ctx, job)
+	now := time.Now()
+	if err != nil {
+		// The cause is only logged, not exposed to the user.
+		s.logger.ErrorContext(ctx, "building export archive failed", "job_id", job.ID, "user_id", job.UserID, "error", err)
+		_ = s.store.Delete(ctx, job.ID)
+		job.Status = ExportFailed
+		job.Error = "failed to build export archive"
+	} else {
+		job.Status = ExportCompleted
+		job.Size = size
+		job.ExpiresAt = now.Add(s.exportTTL).Unix()
+	}
+	job.CompletedAt = now.Unix()
+	if err := s.jobs.UpdateJob(ctx, job); err != nil {
+		s.logger.ErrorContext(ctx, "recording export outcome failed", "job_id", job.ID, "error", err)
+	}
+}
+
+// writeArchive builds the ZIP archive of a job and returns its size in bytes.
+// The archive holds profile.{json,md} and <provider>/<document>.{json,md}.
+func (s *accountService) writeArchive(ctx context.Context, job *ExportJob) (int64, error) {
+	user, err := s.users.FindByID(ctx, job.UserID)
+	if err != nil {
+		return 0, err
+	}
+	user.Password = ""
+
+	w, err := s.store.Create(ctx, job.ID)
+	if err != nil {
+		return 0, err
+	}
+	counter := &countingWriter{w: w}
+	zw := zip.NewWriter(counter)
+
+	write := func() error {
+		if err := writeJSON(zw, "profile.json", user); err != nil {
+			return err
+		}
+		if err := writeFile(zw, "profile.md", profileMarkdown(user)); err != nil {
+			return err
+		}
+		for _, provider := range s.providers {
+			docs, err := provider.ExportUserContent(ctx, job.UserID)
+			if err != nil {
+				return fmt.Errorf("%s: %w", provider.Name(), err)
+			}
+			for _, doc := range docs {
+				base := safeName(provider.Name()) + "/" + safeName(doc.ID)
+				if err := writeJSON(zw, base+".json", doc.Data); err != nil {
+					return err
+				}
+				if doc.Markdown != "" {
+					if err := writeFile(zw, base+".md", doc.Markdown); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		return zw.Close()
+	}
+
+	if err := write(); err != nil {
+		w.Close()
+		return 0, err
+	}
+	if err := w.Close(); err != nil {
+		return 0, err
+	}
+	return counter.n, nil
+}
+
+// OpenExport opens the archive of a completed export owned by the user.
+func (s *accountService) OpenExport(ctx context.Context, userID, jobID string) (io.ReadCloser, *ExportJob, error) {
+	job, err := s.jobs.FindJobByID(ctx, jobID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if job.UserID != userID {
+		// Do not reveal that other users' exports exist.
+		return nil, nil, ErrExportNotFound
+	}
+	if job.Status != ExportCompleted {
+		return nil, nil, ErrExportNotReady
+	}
+	if time.Now().Unix() >= job.ExpiresAt {
+		return nil, nil, ErrExportExpired
+	}
+
+	r, err := s.store.Open(ctx, job.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return r, job, nil
+}
+
+func writeJSON(zw *zip.Writer, name string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFile(zw, name, string(data))
+}
+
+func writeFile(zw *zip.Writer, name, content string) error {
+	f, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(f, content)
+	return err
+}
+
+func profileMarkdown(user *auth.User) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", user.Username)
+	fmt.Fprintf(&b, "- Email: %s\n", user.Email)
+	fmt.Fprintf(&b, "- Roles: %s\n", strings.Join(user.Roles, ", "))
+	fmt.Fprintf(&b, "- Created: %s\n", time.Unix(user.CreatedAt, 0).UTC().Format(time.RFC3339))
+	fmt.Fprintf(&b, "- Updated: %s\n", time.Unix(user.UpdatedAt, 0).UTC().Format(time.RFC3339))
+	return b.String()
+}
+
+// safeName keeps archive entries inside their directory.
+func safeName(name string) string {
+	return strings.NewReplacer("/", "_", "\\", "_", "..", "_").Replace(name)
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,362 @@
+package account
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/AldiandyaIrsyad/author-notes/internal/auth"
+)
+
+// MockUserStore is a mock implementation of UserStore
+type MockUserStore struct {
+	mock.Mock
+}
+
+func (m *MockUserStore) FindByID(ctx context.Context, id string) (*auth.User, error) {
+	args := m.Called(ctx, id)
+	if user := args.Get(0); user != nil {
+		return user.(*auth.User), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockUserStore) UpdateUser(ctx context.Context, user *auth.User) error {
+	args := m.Called(ctx, user)
+	return args.Error(0)
+}
+
+func (m *MockUserStore) DeleteUser(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockUserStore) FindUsersDueForPurge(ctx context.Context, now int64) ([]*auth.User, error) {
+	args := m.Called(ctx, now)
+	if users := args.Get(0); users != nil {
+		return users.([]*auth.User), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockUserStore) DeleteSessionsByUser(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+// MockExportJobRepository is a mock implementation of ExportJobRepository
+type MockExportJobRepository struct {
+	mock.Mock
+}
+
+func (m *MockExportJobRepository) CreateJob(ctx context.Context, job *ExportJob) error {
+	args := m.Called(ctx, job)
+	return args.Error(0)
+}
+
+func (m *MockExportJobRepository) UpdateJob(ctx context.Context, job *ExportJob) error {
+	args := m.Called(ctx, job)
+	return args.Error(0)
+}
+
+func (m *MockExportJobRepository) FindJobByID(ctx context.Context, id string) (*ExportJob, error) {
+	args := m.Called(ctx, id)
+	if job := args.Get(0); job != nil {
+		return job.(*ExportJob), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockExportJobRepository) FindLatestJob(ctx context.Context, userID string) (*ExportJob, error) {
+	args := m.Called(ctx, userID)
+	if job := args.Get(0); job != nil {
+		return job.(*ExportJob), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockExportJobRepository) DeleteJobsByUser(ctx context.Context, userID string) ([]string, error) {
+	args := m.Called(ctx, userID)
+	if ids := args.Get(0); ids != nil {
+		return ids.([]string), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// MockContentProvider is a mock implementation of ContentProvider
+type MockContentProvider struct {
+	mock.Mock
+}
+
+func (m *MockContentProvider) Name() string {
+	return "notes"
+}
+
+func (m *MockContentProvider) ExportUserContent(ctx context.Context, userID string) ([]ExportDocument, error) {
+	args := m.Called(ctx, userID)
+	if docs := args.Get(0); docs != nil {
+		return docs.([]ExportDocument), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockContentProvider) DeleteUserContent(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+// memoryExportStore is an in-memory ExportStore
+type memoryExportStore struct {
+	archives map[string]*bytes.Buffer
+}
+
+func newMemoryExportStore() *memoryExportStore {
+	return &memoryExportStore{archives: map[string]*bytes.Buffer{}}
+}
+
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
+
+func (s *memoryExportStore) Create(ctx context.Context, jobID string) (io.WriteCloser, error) {
+	buf := &bytes.Buffer{}
+	s.archives[jobID] = buf
+	return nopWriteCloser{buf}, nil
+}
+
+func (s *memoryExportStore) Open(ctx context.Context, jobID string) (io.ReadCloser, error) {
+	buf, ok := s.archives[jobID]
+	if !ok {
+		return nil, ErrExportNotFound
+	}
+	return io.NopCloser(bytes.NewReader(buf.Bytes())), nil
+}
+
+func (s *memoryExportStore) Delete(ctx context.Context, jobID string) error {
+	delete(s.archives, jobID)
+	return nil
+}
+
+func newTestService(users *MockUserStore, jobs *MockExportJobRepository, store ExportStore, providers ...ContentProvider) *accountService {
+	service := NewAccountService(users, jobs, store, slog.New(slog.NewTextHandler(io.Discard, nil)), providers...).(*accountService)
+	service.runAsync = func(f func()) { f() } // run exports synchronously
+	return service
+}
+
+func TestAccountService_DeleteAccount(t *testing.T) {
+	users := new(MockUserStore)
+	t.Setenv("ACCOUNT_DELETION_GRACE_PERIOD", "48h")
+	service := newTestService(users, new(MockExportJobRepository), newMemoryExportStore())
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		user := &auth.User{ID: "user123", Password: "hash"}
+		users.On("FindByID", ctx, "user123").Return(user, nil).Once()
+		users.On("UpdateUser", ctx, mock.AnythingOfType("*auth.User")).Return(nil).Once()
+		users.On("DeleteSessionsByUser", ctx, "user123").Return(nil).Once()
+
+		deleted, err := service.DeleteAccount(ctx, "user123")
+
+		require.NoError(t, err)
+		assert.NotZero(t, deleted.DeletedAt)
+		assert.Equal(t, deleted.DeletedAt+int64((48*time.Hour).Seconds()), deleted.PurgeAt)
+		assert.Empty(t, deleted.Password)
+		users.AssertExpectations(t)
+	})
+
+	t.Run("Already Scheduled", func(t *testing.T) {
+		user := &auth.User{ID: "user123", DeletedAt: 100, PurgeAt: 200}
+		users.On("FindByID", ctx, "user123").Return(user, nil).Once()
+
+		deleted, err := service.DeleteAccount(ctx, "user123")
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(200), deleted.PurgeAt)
+		users.AssertExpectations(t) // UpdateUser must not be called
+	})
+}
+
+func TestAccountService_PurgeDeletedAccounts(t *testing.T) {
+	users := new(MockUserStore)
+	jobs := new(MockExportJobRepository)
+	store := newMemoryExportStore()
+	provider := new(MockContentProvider)
+	service := newTestService(users, jobs, store, provider)
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		store.archives["job1"] = &bytes.Buffer{}
+		users.On("FindUsersDueForPurge", ctx, mock.AnythingOfType("int64")).Return([]*auth.User{{ID: "user1"}}, nil).Once()
+		provider.On("DeleteUserContent", ctx, "user1").Return(nil).Once()
+		jobs.On("DeleteJobsByUser", ctx, "user1").Return([]string{"job1"}, nil).Once()
+		users.On("DeleteUser", ctx, "user1").Return(nil).Once()
+
+		purged, err := service.PurgeDeletedAccounts(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, purged)
+		assert.NotContains(t, store.archives, "job1")
+		users.AssertExpectations(t)
+		provider.AssertExpectations(t)
+		jobs.AssertExpectations(t)
+	})
+
+	t.Run("Content Deletion Fails", func(t *testing.T) {
+		users.On("FindUsersDueForPurge", ctx, mock.AnythingOfType("int64")).Return([]*auth.User{{ID: "user1"}, {ID: "user2"}}, nil).Once()
+		provider.On("DeleteUserContent", ctx, "user1").Return(errors.New("db down")).Once()
+		provider.On("DeleteUserContent", ctx, "user2").Return(nil).Once()
+		jobs.On("DeleteJobsByUser", ctx, "user2").Return([]string{}, nil).Once()
+		users.On("DeleteUser", ctx, "user2").Return(nil).Once()
+
+		purged, err := service.PurgeDeletedAccounts(ctx)
+
+		require.Error(t, err)
+		assert.Equal(t, 1, purged)
+		users.AssertExpectations(t) // user1 must be kept for the next run
+		provider.AssertExpectations(t)
+		jobs.AssertExpectations(t)
+	})
+}
+
+func TestAccountService_Export(t *testing.T) {
+	users := new(MockUserStore)
+	jobs := new(MockExportJobRepository)
+	store := newMemoryExportStore()
+	provider := new(MockContentProvider)
+	service := newTestService(users, jobs, store, provider)
+	ctx := context.Background()
+
+	t.Run("Success - Builds Archive", func(t *testing.T) {
+		jobs.On("FindLatestJob", ctx, "user123").Return(nil, ErrExportNotFound).Once()
+		jobs.On("CreateJob", ctx, mock.AnythingOfType("*account.ExportJob")).Run(func(args mock.Arguments) {
+			args.Get(1).(*ExportJob).ID = "job1"
+		}).Return(nil).Once()
+		users.On("FindByID", mock.Anything, "user123").Return(&auth.User{ID: "user123", Username: "writer", Password: "hash"}, nil).Once()
+		provider.On("ExportUserContent", mock.Anything, "user123").Return([]ExportDocument{
+			{ID: "note1", Data: map[string]string{"title": "Hero"}, Markdown: "# Hero\n"},
+		}, nil).Once()
+		var finished *ExportJob
+		jobs.On("UpdateJob", mock.Anything, mock.AnythingOfType("*account.ExportJob")).Run(func(args mock.Arguments) {
+			finished = args.Get(1).(*ExportJob)
+		}).Return(nil).Once()
+
+		job, err := service.CurrentExport(ctx, "user123")
+
+		require.NoError(t, err)
+		assert.Equal(t, ExportPending, job.Status)
+		require.NotNil(t, finished)
+		assert.Equal(t, ExportCompleted, finished.Status)
+		assert.Greater(t, finished.ExpiresAt, time.Now().Unix())
+
+		archive := store.archives["job1"]
+		require.NotNil(t, archive)
+		assert.Equal(t, int64(archive.Len()), finished.Size)
+		zr, err := zip.NewReader(bytes.NewReader(archive.Bytes()), int64(archive.Len()))
+		require.NoError(t, err)
+		names := []string{}
+		for _, f := range zr.File {
+			names = append(names, f.Name)
+		}
+		assert.ElementsMatch(t, []string{"profile.json", "profile.md", "notes/note1.json", "notes/note1.md"}, names)
+		assert.NotContains(t, readZipFile(t, zr, "profile.json"), "hash")
+		mocksExpected(t, users, jobs, provider)
+	})
+
+	t.Run("Reuses Downloadable Export", func(t *testing.T) {
+		existing := &ExportJob{ID: "job1", UserID: "user123", Status: ExportCompleted, ExpiresAt: time.Now().Add(time.Hour).Unix()}
+		jobs.On("FindLatestJob", ctx, "user123").Return(existing, nil).Once()
+
+		job, err := service.CurrentExport(ctx, "user123")
+
+		require.NoError(t, err)
+		assert.Same(t, existing, job)
+		jobs.AssertExpectations(t)
+	})
+
+	t.Run("Provider Failure Marks Job Failed", func(t *testing.T) {
+		jobs.On("FindLatestJob", ctx, "user123").Return(nil, ErrExportNotFound).Once()
+		jobs.On("CreateJob", ctx, mock.AnythingOfType("*account.ExportJob")).Run(func(args mock.Arguments) {
+			args.Get(1).(*ExportJob).ID = "job2"
+		}).Return(nil).Once()
+		users.On("FindByID", mock.Anything, "user123").Return(&auth.User{ID: "user123"}, nil).Once()
+		provider.On("ExportUserContent", mock.Anything, "user123").Return(nil, errors.New("db down")).Once()
+		jobs.On("UpdateJob", mock.Anything, mock.MatchedBy(func(j *ExportJob) bool {
+			return j.Status == ExportFailed && j.Error != ""
+		})).Return(nil).Once()
+
+		_, err := service.StartExport(ctx, "user123")
+
+		require.NoError(t, err)
+		assert.NotContains(t, store.archives, "job2")
+		mocksExpected(t, users, jobs, provider)
+	})
+}
+
+func TestAccountService_OpenExport(t *testing.T) {
+	jobs := new(MockExportJobRepository)
+	store := newMemoryExportStore()
+	store.archives["job1"] = bytes.NewBufferString("zip")
+	service := newTestService(new(MockUserStore), jobs, store)
+	ctx := context.Background()
+	future := time.Now().Add(time.Hour).Unix()
+
+	t.Run("Success", func(t *testing.T) {
+		jobs.On("FindJobByID", ctx, "job1").Return(&ExportJob{ID: "job1", UserID: "user123", Status: ExportCompleted, ExpiresAt: future}, nil).Once()
+
+		r, job, err := service.OpenExport(ctx, "user123", "job1")
+
+		require.NoError(t, err)
+		defer r.Close()
+		assert.Equal(t, "job1", job.ID)
+	})
+
+	t.Run("Other User", func(t *testing.T) {
+		jobs.On("FindJobByID", ctx, "job1").Return(&ExportJob{ID: "job1", UserID: "user123", Status: ExportCompleted, ExpiresAt: future}, nil).Once()
+
+		_, _, err := service.OpenExport(ctx, "intruder", "job1")
+
+		assert.ErrorIs(t, err, ErrExportNotFound)
+	})
+
+	t.Run("Not Ready", func(t *testing.T) {
+		jobs.On("FindJobByID", ctx, "job1").Return(&ExportJob{ID: "job1", UserID: "user123", Status: ExportPending}, nil).Once()
+
+		_, _, err := service.OpenExport(ctx, "user123", "job1")
+
+		assert.ErrorIs(t, err, ErrExportNotReady)
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		jobs.On("FindJobByID", ctx, "job1").Return(&ExportJob{ID: "job1", UserID: "user123", Status: ExportCompleted, ExpiresAt: 1}, nil).Once()
+
+		_, _, err := service.OpenExport(ctx, "user123", "job1")
+
+		assert.ErrorIs(t, err, ErrExportExpired)
+	})
+}
+
+func readZipFile(t *testing.T, zr *zip.Reader, name string) string {
+	t.Helper()
+	f, err := zr.Open(name)
+	require.NoError(t, err)
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func mocksExpected(t *testing.T, mocks ...interface{ AssertExpectations(mock.TestingT) bool }) {
+	t.Helper()
+	for _, m := range mocks {
+		m.AssertExpectations(t)
+	}
+}
@@ -0,0 +1,73 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	app_account "github.com/AldiandyaIrsyad/author-notes/internal/account"
+)
+
+// fileExportStore implements the ExportStore interface on the local filesystem.
+type fileExportStore struct {
+	dir string
+}
+
+// NewFileExportStore creates a new instance of fileExportStore writing archives to dir.
+func NewFileExportStore(dir string) (app_account.ExportStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return &fileExportStore{dir: dir}, nil
+}
+
+func (s *fileExportStore) path(jobID string) string {
+	return filepath.Join(s.dir, filepath.Base(jobID)+".zip")
+}
+
+// Create opens a new archive for writing. The archive is written to a
+// temporary file that only replaces the final path when closed.
+func (s *fileExportStore) Create(ctx context.Context, jobID string) (io.WriteCloser, error) {
+	f, err := os.CreateTemp(s.dir, "export-*.tmp")
+	if err != nil {
+		return nil, err
+	}
+	return &atomicFile{File: f, target: s.path(jobID)}, nil
+}
+
+// Open opens a completed archive for reading.
+func (s *fileExportStore) Open(ctx context.Context, jobID string) (io.ReadCloser, error) {
+	f, err := os.Open(s.path(jobID))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, app_account.ErrExportNotFound
+		}
+		return nil, err
+	}
+	return f, nil
+}
+
+// Delete removes an archive.
+func (s *fileExportStore) Delete(ctx context.Context, jobID string) error {
+	err := os.Remove(s.path(jobID))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+type atomicFile struct {
+	*os.File
+	target string
+}
+
+func (f *atomicFile) Close() error {
+	if err := f.File.Close(); err != nil {
+		os.Remove(f.File.Name())
+		return err
+	}
+	return os.Rename(f.File.Name(), f.target)
+}
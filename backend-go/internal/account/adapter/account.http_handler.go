@@ -0,0 +1,176 @@
+package account
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	v1 "github.com/AldiandyaIrsyad/author-notes/api/v1/account"
+	app_account "github.com/AldiandyaIrsyad/author-notes/internal/account"
+	app_auth "github.com/AldiandyaIrsyad/author-notes/internal/auth"
+	auth_adapter "github.com/AldiandyaIrsyad/author-notes/internal/auth/adapter"
+)
+
+type AccountHTTPHandler struct {
+	service app_account.AccountService
+	logger  *slog.Logger
+}
+
+func NewAccountHTTPHandler(service app_account.AccountService, logger *slog.Logger) *AccountHTTPHandler {
+	return &AccountHTTPHandler{service: service, logger: logger}
+}
+
+// internalError logs err and responds with a generic 500 error that does not leak it.
+func (h *AccountHTTPHandler) internalError(c *gin.Context, message string, err error) {
+	h.logger.ErrorContext(c.Request.Context(), message, "route", c.FullPath(), "error", err)
+	c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+}
+
+// RegisterRoutes registers the account routes on rg, which must be authenticated.
+func (h *AccountHTTPHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	meGroup := rg.Group("/me")
+	meGroup.DELETE("", auth_adapter.RequireLoginToken(), h.DeleteAccount)
+	meGroup.GET("/export", h.GetExport)
+	meGroup.POST("/export", h.StartExport)
+	meGroup.GET("/export/:id/download", h.DownloadExport)
+}
+
+// DeleteAccount handles the request to delete the caller's account.
+// @Summary Delete my account
+// @Description Schedules the account and all content it owns for deletion after a grace period. Logging in again before then cancels the deletion.
+// @Tags account
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} v1.DeleteAccountResponse "Deletion scheduled"
+// @Failure 401 {object} map[string]string "Missing or invalid token"
+// @Failure 403 {object} map[string]string "Called with a personal access token"
+// @Failure 404 {object} map[string]string "User not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/me [delete]
+func (h *AccountHTTPHandler) DeleteAccount(c *gin.Context) {
+	claims, _ := app_auth.ClaimsFromContext(c.Request.Context())
+
+	user, err := h.service.DeleteAccount(c.Request.Context(), claims.UserID)
+	if err != nil {
+		switch {
+		case errors.Is(err, app_auth.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			h.internalError(c, "Failed to delete account", err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.DeleteAccountResponse{PurgeAt: user.PurgeAt})
+}
+
+// GetExport handles the request for the caller's personal data export.
+// @Summary Get my data export
+// @Description Returns the current export of the caller's profile and content, starting one if none is pending or downloadable. Poll until the status is completed, then follow download_url.
+// @Tags account
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} v1.ExportJobResponse "Export ready or failed"
+// @Success 202 {object} v1.ExportJobResponse "Export in progress"
+// @Failure 401 {object} map[string]string "Missing or invalid token"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/me/export [get]
+func (h *AccountHTTPHandler) GetExport(c *gin.Context) {
+	claims, _ := app_auth.ClaimsFromContext(c.Request.Context())
+
+	job, err := h.service.CurrentExport(c.Request.Context(), claims.UserID)
+	if err != nil {
+		h.internalError(c, "Failed to export account", err)
+		return
+	}
+
+	h.respondJob(c, job)
+}
+
+// StartExport handles the request to build a fresh personal data export.
+// @Summary Start a new data export
+// @Description Starts a new export of the caller's profile and content unless one is already in progress.
+// @Tags account
+// @Produce json
+// @Security BearerAuth
+// @Success 202 {object} v1.ExportJobResponse "Export in progress"
+// @Failure 401 {object} map[string]string "Missing or invalid token"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/me/export [post]
+func (h *AccountHTTPHandler) StartExport(c *gin.Context) {
+	claims, _ := app_auth.ClaimsFromContext(c.Request.Context())
+
+	job, err := h.service.StartExport(c.Request.Context(), claims.UserID)
+	if err != nil {
+		h.internalError(c, "Failed to export account", err)
+		return
+	}
+
+	h.respondJob(c, job)
+}
+
+// DownloadExport handles the download of a completed export archive.
+// @Summary Download a data export
+// @Description Streams the ZIP archive of a completed export.
+// @Tags account
+// @Produce application/zip
+// @Security BearerAuth
+// @Param id path string true "Export ID"
+// @Success 200 {file} file "ZIP archive"
+// @Failure 401 {object} map[string]string "Missing or invalid token"
+// @Failure 404 {object} map[string]string "Export not found"
+// @Failure 409 {object} map[string]string "Export not ready yet"
+// @Failure 410 {object} map[string]string "Export expired"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/me/export/{id}/download [get]
+func (h *AccountHTTPHandler) DownloadExport(c *gin.Context) {
+	claims, _ := app_auth.ClaimsFromContext(c.Request.Context())
+
+	archive, job, err := h.service.OpenExport(c.Request.Context(), claims.UserID, c.Param("id"))
+	if err != nil {
+		switch {
+		case errors.Is(err, app_account.ErrExportNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, app_account.ErrExportNotReady):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, app_account.ErrExportExpired):
+			c.JSON(http.StatusGone, gin.H{"error": err.Error()})
+		default:
+			h.internalError(c, "Failed to download export", err)
+		}
+		return
+	}
+	defer archive.Close()
+
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="author-notes-export-%s.zip"`, job.ID))
+	c.Header("Content-Length", strconv.FormatInt(job.Size, 10))
+	c.Status(http.StatusOK)
+	_, _ = io.Copy(c.Writer, archive)
+}
+
+func (h *AccountHTTPHandler) respondJob(c *gin.Context, job *app_account.ExportJob) {
+	resp := v1.ExportJobResponse{
+		ID:          job.ID,
+		Status:      string(job.Status),
+		CreatedAt:   job.CreatedAt,
+		CompletedAt: job.CompletedAt,
+		ExpiresAt:   job.ExpiresAt,
+		Size:        job.Size,
+		Error:       job.Error,
+	}
+
+	status := http.StatusOK
+	switch job.Status {
+	case app_account.ExportPending:
+		status = http.StatusAccepted
+	case app_account.ExportCompleted:
+		resp.DownloadURL = "/v1/me/export/" + job.ID + "/download"
+	}
+	c.JSON(status, resp)
+}
@@ -0,0 +1,102 @@
+package account
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	app_account "github.com/AldiandyaIrsyad/author-notes/internal/account"
+)
+
+// mongoExportJobRepository implements the ExportJobRepository interface using MongoDB.
+type mongoExportJobRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoExportJobRepository creates a new instance of mongoExportJobRepository.
+func NewMongoExportJobRepository(db *mongo.Database) app_account.ExportJobRepository {
+	return &mongoExportJobRepository{
+		collection: db.Collection("export_jobs"),
+	}
+}
+
+// CreateJob inserts a new export job.
+func (r *mongoExportJobRepository) CreateJob(ctx context.Context, job *app_account.ExportJob) error {
+	if job.ID == "" {
+		job.ID = uuid.NewString()
+	}
+	_, err := r.collection.InsertOne(ctx, job)
+	return err
+}
+
+// UpdateJob replaces a stored export job with the given one.
+func (r *mongoExportJobRepository) UpdateJob(ctx context.Context, job *app_account.ExportJob) error {
+	result, err := r.collection.ReplaceOne(ctx, bson.M{"_id": job.ID}, job)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return app_account.ErrExportNotFound
+	}
+	return nil
+}
+
+// FindJobByID retrieves an export job by its ID.
+func (r *mongoExportJobRepository) FindJobByID(ctx context.Context, id string) (*app_account.ExportJob, error) {
+	var job app_account.ExportJob
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&job)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, app_account.ErrExportNotFound
+		}
+		return nil, err
+	}
+	return &job, nil
+}
+
+// FindLatestJob retrieves the most recently created export job of a user.
+func (r *mongoExportJobRepository) FindLatestJob(ctx context.Context, userID string) (*app_account.ExportJob, error) {
+	var job app_account.ExportJob
+	opts := options.FindOne().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	err := r.collection.FindOne(ctx, bson.M{"user_id": userID}, opts).Decode(&job)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, app_account.ErrExportNotFound
+		}
+		return nil, err
+	}
+	return &job, nil
+}
+
+// DeleteJobsByUser removes every export job of a user and returns their IDs.
+func (r *mongoExportJobRepository) DeleteJobsByUser(ctx context.Context, userID string) ([]string, error) {
+	filter := bson.M{"user_id": userID}
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	ids := []string{}
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID string `bson:"_id"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		ids = append(ids, doc.ID)
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	if _, err := r.collection.DeleteMany(ctx, filter); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
@@ -0,0 +1,112 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	app_auth "github.com/AldiandyaIrsyad/author-notes/internal/auth"
+	"github.com/AldiandyaIrsyad/author-notes/internal/pagination"
+)
+
+// mongoAuditLog implements the AuditLog interface using MongoDB. Retention is
+// enforced by a TTL index on each event's expiry date.
+type mongoAuditLog struct {
+	collection *mongo.Collection
+	retention  time.Duration
+}
+
+// auditDocument is the stored form of an AuditEvent.
+type auditDocument struct {
+	app_auth.AuditEvent `bson:",inline"`
+	ExpireAt            time.Time `bson:"expire_at"`
+}
+
+// NewMongoAuditLog creates a new instance of mongoAuditLog keeping events for retention.
+func NewMongoAuditLog(ctx context.Context, db *mongo.Database, retention time.Duration) (app_auth.AuditLog, error) {
+	collection := db.Collection("audit_events")
+	_, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "expire_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+		{Keys: bson.D{{Key: "actor_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "target_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &mongoAuditLog{collection: collection, retention: retention}, nil
+}
+
+// Append records a new event.
+func (l *mongoAuditLog) Append(ctx context.Context, event *app_auth.AuditEvent) error {
+	if event.ID == "" {
+		event.ID = uuid.NewString()
+	}
+	doc := auditDocument{
+		AuditEvent: *event,
+		ExpireAt:   time.Unix(event.CreatedAt, 0).Add(l.retention),
+	}
+	_, err := l.collection.InsertOne(ctx, doc)
+	return err
+}
+
+// List retrieves one page of events matching the filter, newest first.
+func (l *mongoAuditLog) List(ctx context.Context, filter app_auth.AuditFilter) (*app_auth.AuditPage, error) {
+	conditions := []bson.M{}
+	if filter.UserID != "" {
+		conditions = append(conditions, bson.M{"$or": []bson.M{
+			{"actor_id": filter.UserID},
+			{"target_id": filter.UserID},
+		}})
+	}
+	if filter.Action != "" {
+		conditions = append(conditions, bson.M{"action": filter.Action})
+	}
+	if filter.Outcome != "" {
+		conditions = append(conditions, bson.M{"outcome": filter.Outcome})
+	}
+	if filter.Cursor != "" {
+		cursor, err := pagination.DecodeCursor(filter.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, bson.M{"$or": []bson.M{
+			{"created_at": bson.M{"$lt": cursor.CreatedAt}},
+			{"created_at": cursor.CreatedAt, "_id": bson.M{"$lt": cursor.ID}},
+		}})
+	}
+
+	query := bson.M{}
+	if len(conditions) > 0 {
+		query["$and"] = conditions
+	}
+
+	// Fetch one extra document to find out whether another page exists.
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(int64(filter.Limit) + 1)
+	cursor, err := l.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	events := []*app_auth.AuditEvent{}
+	if err := cursor.All(ctx, &events); err != nil {
+		return nil, err
+	}
+
+	page := &app_auth.AuditPage{Events: events}
+	if len(events) > filter.Limit {
+		page.Events = events[:filter.Limit]
+		last := page.Events[filter.Limit-1]
+		page.NextCursor = pagination.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}.Encode()
+	}
+	return page, nil
+}
@@ -0,0 +1,119 @@
+package auth
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"strings"
+
+	app_auth "github.com/AldiandyaIrsyad/author-notes/internal/auth"
+)
+
+// breachedPasswordFile implements the BreachedPasswords interface on top of a
+// local copy of a breached password list, in the format of the "ordered by
+// hash" SHA-1 download of Have I Been Pwned: one "HASH:COUNT" line per
+// password, sorted by hash. The file is searched in place, so lists of many
+// gigabytes do not have to fit in memory.
+type breachedPasswordFile struct {
+	file *os.File
+	size int64
+}
+
+// maxBreachedLineLength bounds a line of the list: a SHA-1 hash, a count and a line break.
+const maxBreachedLineLength = 64
+
+// NewBreachedPasswordFile opens the breached password list at path.
+func NewBreachedPasswordFile(path string) (app_auth.BreachedPasswords, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &breachedPasswordFile{file: file, size: info.Size()}, nil
+}
+
+// Range returns the hash suffixes of every listed password whose hash starts with prefix.
+func (f *breachedPasswordFile) Range(ctx context.Context, prefix string) ([]string, error) {
+	prefix = strings.ToUpper(prefix)
+
+	// Binary search for the first line whose hash is not below prefix.
+	lo, hi := int64(0), f.size
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		start, err := f.lineStart(mid)
+		if err != nil {
+			return nil, err
+		}
+		line, err := f.lineAt(start)
+		if err != nil {
+			return nil, err
+		}
+		if start < f.size && hashPrefix(line, len(prefix)) < prefix {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	start, err := f.lineStart(lo)
+	if err != nil {
+		return nil, err
+	}
+
+	suffixes := []string{}
+	scanner := bufio.NewScanner(io.NewSectionReader(f.file, start, f.size-start))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if hashPrefix(line, len(prefix)) != prefix {
+			break
+		}
+		hash, _, _ := strings.Cut(strings.TrimSpace(line), ":")
+		suffixes = append(suffixes, strings.ToUpper(hash[len(prefix):]))
+	}
+	return suffixes, scanner.Err()
+}
+
+// lineStart returns the offset of the first line starting at or after offset.
+func (f *breachedPasswordFile) lineStart(offset int64) (int64, error) {
+	if offset == 0 {
+		return 0, nil
+	}
+	buf := make([]byte, maxBreachedLineLength)
+	for pos := offset - 1; pos < f.size; pos += int64(len(buf)) {
+		n, err := f.file.ReadAt(buf, pos)
+		if i := bytes.IndexByte(buf[:n], '\n'); i >= 0 {
+			return pos + int64(i) + 1, nil
+		}
+		if err != nil && err != io.EOF {
+			return 0, err
+		}
+	}
+	return f.size, nil
+}
+
+// lineAt returns the line starting at offset, without the line break.
+func (f *breachedPasswordFile) lineAt(offset int64) (string, error) {
+	if offset >= f.size {
+		return "", nil
+	}
+	buf := make([]byte, maxBreachedLineLength)
+	n, err := f.file.ReadAt(buf, offset)
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	line, _, _ := bytes.Cut(buf[:n], []byte{'\n'})
+	return string(line), nil
+}
+
+// hashPrefix returns the first n characters of the hash on line, uppercased.
+func hashPrefix(line string, n int) string {
+	if len(line) < n {
+		return strings.ToUpper(line)
+	}
+	return strings.ToUpper(line[:n])
+}
@@ -0,0 +1,112 @@
+package auth
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// breachedFixture is a small breached password list, sorted by hash, with
+// two hashes sharing the prefix 3A1F0.
+var breachedFixture = []string{
+	"000A1B2C3D4E5F60718293A4B5C6D7E8F9012345:12",
+	"3A1F00A9B8C7D6E5F4A3B2C1D0E9F8A7B6C5D4E3:5",
+	"3A1F0F1E2D3C4B5A69788796A5B4C3D2E1F0A9B8:41",
+	"7C4A8D09CA3762AF61E59520943DC26494F8941B:24230577",
+	"FFFFF2AB3C4D5E6F708192A3B4C5D6E7F8091A2B:3",
+}
+
+// newBreachedFixture writes lines to a file joined by sep and opens it.
+func newBreachedFixture(t *testing.T, sep string) *breachedPasswordFile {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "pwned-passwords.txt")
+	require.NoError(t, os.WriteFile(path, []byte(strings.Join(breachedFixture, sep)+sep), 0o600))
+
+	passwords, err := NewBreachedPasswordFile(path)
+	require.NoError(t, err)
+	file := passwords.(*breachedPasswordFile)
+	t.Cleanup(func() { file.file.Close() })
+	return file
+}
+
+func TestBreachedPasswordFile_Range(t *testing.T) {
+	tests := []struct {
+		name   string
+		prefix string
+		want   []string
+	}{
+		{
+			name:   "First Line",
+			prefix: "000A1",
+			want:   []string{"B2C3D4E5F60718293A4B5C6D7E8F9012345"},
+		},
+		{
+			name:   "Last Line",
+			prefix: "FFFFF",
+			want:   []string{"2AB3C4D5E6F708192A3B4C5D6E7F8091A2B"},
+		},
+		{
+			name:   "Several Lines",
+			prefix: "3A1F0",
+			want:   []string{"0A9B8C7D6E5F4A3B2C1D0E9F8A7B6C5D4E3", "F1E2D3C4B5A69788796A5B4C3D2E1F0A9B8"},
+		},
+		{
+			name:   "Lower Case Prefix",
+			prefix: "7c4a8",
+			want:   []string{"D09CA3762AF61E59520943DC26494F8941B"},
+		},
+		{
+			name:   "Prefix Before Every Line",
+			prefix: "00000",
+			want:   []string{},
+		},
+		{
+			name:   "Prefix Between Lines",
+			prefix: "5B2E9",
+			want:   []string{},
+		},
+		{
+			name:   "Prefix Sharing Characters With A Line",
+			prefix: "3A1F1",
+			want:   []string{},
+		},
+	}
+
+	// The downloads of Have I Been Pwned end lines with CRLF.
+	for name, sep := range map[string]string{"LF": "\n", "CRLF": "\r\n"} {
+		t.Run(name, func(t *testing.T) {
+			file := newBreachedFixture(t, sep)
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					suffixes, err := file.Range(context.Background(), tt.prefix)
+
+					require.NoError(t, err)
+					assert.Equal(t, tt.want, suffixes)
+				})
+			}
+		})
+	}
+}
+
+func TestBreachedPasswordFile_Empty(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pwned-passwords.txt")
+	require.NoError(t, os.WriteFile(path, nil, 0o600))
+	passwords, err := NewBreachedPasswordFile(path)
+	require.NoError(t, err)
+
+	suffixes, err := passwords.Range(context.Background(), "7C4A8")
+
+	require.NoError(t, err)
+	assert.Empty(t, suffixes)
+}
+
+func TestNewBreachedPasswordFile_Missing(t *testing.T) {
+	_, err := NewBreachedPasswordFile(filepath.Join(t.TempDir(), "missing.txt"))
+
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
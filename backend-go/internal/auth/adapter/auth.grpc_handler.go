@@ -0,0 +1,226 @@
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"log/slog"
+	"net"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	authv1 "github.com/AldiandyaIrsyad/author-notes/api/proto/auth/v1"
+	v1 "github.com/AldiandyaIrsyad/author-notes/api/v1/auth"
+	app_auth "github.com/AldiandyaIrsyad/author-notes/internal/auth"
+)
+
+// ServiceTokenMetadata is the metadata key carrying the shared secret that
+// services calling ValidateToken authenticate with.
+const ServiceTokenMetadata = "x-service-token"
+
+// AuthGRPCHandler exposes the AuthService over gRPC, next to AuthHTTPHandler.
+type AuthGRPCHandler struct {
+	authv1.UnimplementedAuthServiceServer
+	service      app_auth.AuthService
+	logger       *slog.Logger
+	serviceToken string
+}
+
+// NewAuthGRPCHandler creates the gRPC handler. serviceToken is the secret
+// expected in the ServiceTokenMetadata of ValidateToken calls; when it is
+// empty, ValidateToken rejects every caller.
+func NewAuthGRPCHandler(service app_auth.AuthService, logger *slog.Logger, serviceToken string) *AuthGRPCHandler {
+	return &AuthGRPCHandler{service: service, logger: logger, serviceToken: serviceToken}
+}
+
+// RegisterService registers the auth service on s.
+func (h *AuthGRPCHandler) RegisterService(s grpc.ServiceRegistrar) {
+	authv1.RegisterAuthServiceServer(s, h)
+}
+
+// withClientInfo stores the peer address and user agent in the context, as
+// CaptureClientInfo does for HTTP requests.
+func withClientInfo(ctx context.Context) context.Context {
+	var info app_auth.ClientInfo
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		info.IP = p.Addr.String()
+		if host, _, err := net.SplitHostPort(info.IP); err == nil {
+			info.IP = host
+		}
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ua := md.Get("user-agent"); len(ua) > 0 {
+			info.UserAgent = ua[0]
+		}
+	}
+	return app_auth.ContextWithClientInfo(ctx, info)
+}
+
+// Register creates a new user account.
+func (h *AuthGRPCHandler) Register(ctx context.Context, req *authv1.RegisterRequest) (*authv1.User, error) {
+	user, err := h.service.Register(withClientInfo(ctx), v1.RegisterRequest{
+		Username:   req.GetUsername(),
+		Password:   req.GetPassword(),
+		Email:      req.GetEmail(),
+		InviteCode: req.GetInviteCode(),
+	})
+	if err != nil {
+		return nil, h.statusError(ctx, "Failed to register user", err)
+	}
+	return toUserMessage(user), nil
+}
+
+// Login authenticates a user and returns a login token.
+func (h *AuthGRPCHandler) Login(ctx context.Context, req *authv1.LoginRequest) (*authv1.LoginResponse, error) {
+	token, err := h.service.Login(withClientInfo(ctx), v1.LoginRequest{
+		Username:   req.GetUsername(),
+		Password:   req.GetPassword(),
+		DeviceName: req.GetDeviceName(),
+	})
+	if err != nil {
+		return nil, h.statusError(ctx, "Failed to login", err)
+	}
+	return &authv1.LoginResponse{Token: token}, nil
+}
+
+// ValidateToken returns the claims of a login or personal access token, so
+// that other services can authenticate users without sharing the JWT secret.
+// Callers must present the service token.
+func (h *AuthGRPCHandler) ValidateToken(ctx context.Context, req *authv1.ValidateTokenRequest) (*authv1.Claims, error) {
+	if err := h.authenticateService(ctx); err != nil {
+		return nil, err
+	}
+	if req.GetToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, app_auth.ErrValidationFailed.Error())
+	}
+	claims, err := h.service.ValidateToken(withClientInfo(ctx), req.GetToken())
+	if err != nil {
+		return nil, h.statusError(ctx, "Failed to validate token", err)
+	}
+	return &authv1.Claims{
+		UserId:          claims.UserID,
+		Username:        claims.Username,
+		Roles:           claims.Roles,
+		Permissions:     claims.Permissions,
+		SessionId:       claims.SessionID,
+		PersonalTokenId: claims.PersonalTokenID,
+	}, nil
+}
+
+// GetProfile returns the account of the user authenticated by the
+// "authorization: Bearer <token>" metadata.
+func (h *AuthGRPCHandler) GetProfile(ctx context.Context, _ *authv1.GetProfileRequest) (*authv1.User, error) {
+	ctx = withClientInfo(ctx)
+	claims, err := h.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	user, err := h.service.GetProfile(ctx, claims.UserID)
+	if err != nil {
+		return nil, h.statusError(ctx, "Failed to get profile", err)
+	}
+	return toUserMessage(user), nil
+}
+
+// toUserMessage converts a user to its gRPC message, leaving out the password.
+func toUserMessage(user *app_auth.User) *authv1.User {
+	identities := make([]*authv1.Identity, len(user.Identities))
+	for i, identity := range user.Identities {
+		identities[i] = &authv1.Identity{Provider: identity.Provider, Subject: identity.Subject, LinkedAt: identity.LinkedAt}
+	}
+	return &authv1.User{
+		Id:         user.ID,
+		Email:      user.Email,
+		Username:   user.Username,
+		Roles:      user.Roles,
+		Verified:   user.Verified,
+		Disabled:   user.Disabled,
+		CreatedAt:  user.CreatedAt,
+		UpdatedAt:  user.UpdatedAt,
+		DeletedAt:  user.DeletedAt,
+		PurgeAt:    user.PurgeAt,
+		Identities: identities,
+	}
+}
+
+// authenticateService checks the service token in the ServiceTokenMetadata.
+func (h *AuthGRPCHandler) authenticateService(ctx context.Context) error {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(ServiceTokenMetadata)
+	if h.serviceToken == "" || len(values) == 0 ||
+		subtle.ConstantTimeCompare([]byte(values[0]), []byte(h.serviceToken)) != 1 {
+		return status.Error(codes.Unauthenticated, "Missing or invalid service token")
+	}
+	return nil
+}
+
+// authenticate validates the bearer token in the "authorization" metadata.
+func (h *AuthGRPCHandler) authenticate(ctx context.Context) (*app_auth.Claims, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "Missing or malformed authorization metadata")
+	}
+	scheme, token, found := strings.Cut(values[0], " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return nil, status.Error(codes.Unauthenticated, "Missing or malformed authorization metadata")
+	}
+	claims, err := h.service.ValidateToken(ctx, token)
+	if err != nil {
+		return nil, h.statusError(ctx, "Failed to authenticate request", err)
+	}
+	return claims, nil
+}
+
+// statusError maps the domain errors of the AuthService to gRPC status codes,
+// mirroring the HTTP status codes of AuthHTTPHandler. Unexpected errors are
+// logged and reported as Internal without leaking them.
+func (h *AuthGRPCHandler) statusError(ctx context.Context, message string, err error) error {
+	var policyErr *app_auth.PasswordPolicyError
+	if errors.As(err, &policyErr) {
+		st := status.New(codes.InvalidArgument, err.Error())
+		violations := make([]*errdetails.BadRequest_FieldViolation, len(policyErr.Reasons))
+		for i, reason := range policyErr.Reasons {
+			violations[i] = &errdetails.BadRequest_FieldViolation{
+				Field:       "password",
+				Description: reason.Code + ": " + reason.Message,
+			}
+		}
+		if detailed, detailErr := st.WithDetails(&errdetails.BadRequest{FieldViolations: violations}); detailErr == nil {
+			st = detailed
+		}
+		return st.Err()
+	}
+
+	switch {
+	case errors.Is(err, app_auth.ErrValidationFailed):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, app_auth.ErrUserAlreadyExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, app_auth.ErrInvalidCredentials),
+		errors.Is(err, app_auth.ErrTokenExpired),
+		errors.Is(err, app_auth.ErrTokenInvalid):
+		return status.Error(codes.Unauthenticated, err.Error())
+	case errors.Is(err, app_auth.ErrUserDisabled),
+		errors.Is(err, app_auth.ErrLoginRequired),
+		errors.Is(err, app_auth.ErrRegistrationClosed),
+		errors.Is(err, app_auth.ErrInviteRequired),
+		errors.Is(err, app_auth.ErrInvalidInvite),
+		errors.Is(err, app_auth.ErrDomainNotAllowed):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, app_auth.ErrUserNotFound),
+		errors.Is(err, app_auth.ErrSessionNotFound),
+		errors.Is(err, app_auth.ErrTokenNotFound),
+		errors.Is(err, app_auth.ErrInviteNotFound),
+		errors.Is(err, app_auth.ErrUnknownProvider):
+		return status.Error(codes.NotFound, err.Error())
+	}
+	h.logger.ErrorContext(ctx, message, "error", err)
+	return status.Error(codes.Internal, message)
+}
@@ -0,0 +1,283 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	authv1 "github.com/AldiandyaIrsyad/author-notes/api/proto/auth/v1"
+	v1 "github.com/AldiandyaIrsyad/author-notes/api/v1/auth"
+	app_auth "github.com/AldiandyaIrsyad/author-notes/internal/auth"
+)
+
+// MockAuthService is a mock implementation of the AuthService methods served
+// over gRPC. Calling any other method panics.
+type MockAuthService struct {
+	app_auth.AuthService
+	mock.Mock
+}
+
+func (m *MockAuthService) Register(ctx context.Context, req v1.RegisterRequest) (*app_auth.User, error) {
+	args := m.Called(ctx, req)
+	if user := args.Get(0); user != nil {
+		return user.(*app_auth.User), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAuthService) Login(ctx context.Context, req v1.LoginRequest) (string, error) {
+	args := m.Called(ctx, req)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockAuthService) ValidateToken(ctx context.Context, token string) (*app_auth.Claims, error) {
+	args := m.Called(ctx, token)
+	if claims := args.Get(0); claims != nil {
+		return claims.(*app_auth.Claims), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAuthService) GetProfile(ctx context.Context, userID string) (*app_auth.User, error) {
+	args := m.Called(ctx, userID)
+	if user := args.Get(0); user != nil {
+		return user.(*app_auth.User), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+var discardLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// newAuthClient serves a handler of service over an in-memory connection and
+// returns a client of it.
+func newAuthClient(t *testing.T, service app_auth.AuthService, serviceToken string) authv1.AuthServiceClient {
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	NewAuthGRPCHandler(service, discardLogger, serviceToken).RegisterService(server)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUserAgent("notes-test"),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return authv1.NewAuthServiceClient(conn)
+}
+
+// withClientAgent matches contexts carrying the user agent of the test client,
+// which gRPC suffixes with its own.
+func withClientAgent() any {
+	return mock.MatchedBy(func(ctx context.Context) bool {
+		return strings.HasPrefix(app_auth.ClientInfoFromContext(ctx).UserAgent, "notes-test ")
+	})
+}
+
+var alice = &app_auth.User{
+	ID: "user123", Email: "alice@example.com", Username: "alice", Password: "hash", Roles: []string{app_auth.RoleAuthor},
+	Identities: []app_auth.Identity{{Provider: "github", Subject: "42", LinkedAt: 1700000000}},
+}
+
+func TestAuthGRPCHandler_Register(t *testing.T) {
+	ctx := context.Background()
+	service := new(MockAuthService)
+	client := newAuthClient(t, service, "")
+
+	t.Run("Success", func(t *testing.T) {
+		req := v1.RegisterRequest{Username: "alice", Password: "correct horse", Email: "alice@example.com", InviteCode: "abc"}
+		service.On("Register", withClientAgent(), req).Return(alice, nil).Once()
+
+		user, err := client.Register(ctx, &authv1.RegisterRequest{Username: "alice", Password: "correct horse", Email: "alice@example.com", InviteCode: "abc"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "user123", user.GetId())
+		assert.Equal(t, []string{app_auth.RoleAuthor}, user.GetRoles())
+		assert.Equal(t, "github", user.GetIdentities()[0].GetProvider())
+		service.AssertExpectations(t)
+	})
+
+	t.Run("Weak Password", func(t *testing.T) {
+		policyErr := &app_auth.PasswordPolicyError{Reasons: []app_auth.PasswordReason{{Code: "too_short", Message: "at least 12 characters"}}}
+		service.On("Register", mock.Anything, mock.Anything).Return(nil, policyErr).Once()
+
+		_, err := client.Register(ctx, &authv1.RegisterRequest{Username: "alice", Password: "short", Email: "alice@example.com"})
+
+		st := status.Convert(err)
+		assert.Equal(t, codes.InvalidArgument, st.Code())
+		require.Len(t, st.Details(), 1)
+		details := st.Details()[0].(*errdetails.BadRequest)
+		assert.Equal(t, "password", details.GetFieldViolations()[0].GetField())
+		assert.Equal(t, "too_short: at least 12 characters", details.GetFieldViolations()[0].GetDescription())
+	})
+}
+
+func TestAuthGRPCHandler_Login(t *testing.T) {
+	ctx := context.Background()
+	service := new(MockAuthService)
+	client := newAuthClient(t, service, "")
+
+	t.Run("Success", func(t *testing.T) {
+		service.On("Login", withClientAgent(), v1.LoginRequest{Username: "alice", Password: "secret", DeviceName: "cli"}).Return("token", nil).Once()
+
+		res, err := client.Login(ctx, &authv1.LoginRequest{Username: "alice", Password: "secret", DeviceName: "cli"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "token", res.GetToken())
+		service.AssertExpectations(t)
+	})
+
+	t.Run("Invalid Credentials", func(t *testing.T) {
+		service.On("Login", mock.Anything, mock.Anything).Return("", app_auth.ErrInvalidCredentials).Once()
+
+		_, err := client.Login(ctx, &authv1.LoginRequest{Username: "alice", Password: "wrong"})
+
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+}
+
+func TestAuthGRPCHandler_ValidateToken(t *testing.T) {
+	service := new(MockAuthService)
+	client := newAuthClient(t, service, "service-secret")
+	withServiceToken := func(token string) context.Context {
+		return metadata.AppendToOutgoingContext(context.Background(), ServiceTokenMetadata, token)
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		claims := &app_auth.Claims{UserID: "user123", Username: "alice", Roles: []string{app_auth.RoleAuthor}, Permissions: []string{app_auth.PermContentRead}, SessionID: "sess1"}
+		service.On("ValidateToken", mock.Anything, "user-token").Return(claims, nil).Once()
+
+		res, err := client.ValidateToken(withServiceToken("service-secret"), &authv1.ValidateTokenRequest{Token: "user-token"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "user123", res.GetUserId())
+		assert.Equal(t, []string{app_auth.PermContentRead}, res.GetPermissions())
+		assert.Equal(t, "sess1", res.GetSessionId())
+		service.AssertExpectations(t)
+	})
+
+	t.Run("Missing Service Token", func(t *testing.T) {
+		_, err := client.ValidateToken(context.Background(), &authv1.ValidateTokenRequest{Token: "user-token"})
+
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("Wrong Service Token", func(t *testing.T) {
+		_, err := client.ValidateToken(withServiceToken("guess"), &authv1.ValidateTokenRequest{Token: "user-token"})
+
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("Service Token Not Configured", func(t *testing.T) {
+		unconfigured := newAuthClient(t, service, "")
+
+		_, err := unconfigured.ValidateToken(withServiceToken(""), &authv1.ValidateTokenRequest{Token: "user-token"})
+
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("Missing Token", func(t *testing.T) {
+		_, err := client.ValidateToken(withServiceToken("service-secret"), &authv1.ValidateTokenRequest{})
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("Expired Token", func(t *testing.T) {
+		service.On("ValidateToken", mock.Anything, "old-token").Return(nil, app_auth.ErrTokenExpired).Once()
+
+		_, err := client.ValidateToken(withServiceToken("service-secret"), &authv1.ValidateTokenRequest{Token: "old-token"})
+
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	// Only the calls with the service token reached the service.
+	service.AssertNumberOfCalls(t, "ValidateToken", 2)
+}
+
+func TestAuthGRPCHandler_GetProfile(t *testing.T) {
+	service := new(MockAuthService)
+	client := newAuthClient(t, service, "")
+
+	t.Run("Success", func(t *testing.T) {
+		ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer user-token")
+		service.On("ValidateToken", mock.Anything, "user-token").Return(&app_auth.Claims{UserID: "user123"}, nil).Once()
+		service.On("GetProfile", withClientAgent(), "user123").Return(alice, nil).Once()
+
+		user, err := client.GetProfile(ctx, &authv1.GetProfileRequest{})
+
+		require.NoError(t, err)
+		assert.Equal(t, "alice", user.GetUsername())
+		service.AssertExpectations(t)
+	})
+
+	t.Run("Missing Authorization", func(t *testing.T) {
+		_, err := client.GetProfile(context.Background(), &authv1.GetProfileRequest{})
+
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("Malformed Authorization", func(t *testing.T) {
+		ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Basic user-token")
+
+		_, err := client.GetProfile(ctx, &authv1.GetProfileRequest{})
+
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+}
+
+func TestAuthGRPCHandler_StatusError(t *testing.T) {
+	h := NewAuthGRPCHandler(nil, discardLogger, "")
+	tests := []struct {
+		err  error
+		code codes.Code
+	}{
+		{app_auth.ErrValidationFailed, codes.InvalidArgument},
+		{app_auth.ErrUserAlreadyExists, codes.AlreadyExists},
+		{app_auth.ErrInvalidCredentials, codes.Unauthenticated},
+		{app_auth.ErrTokenExpired, codes.Unauthenticated},
+		{app_auth.ErrTokenInvalid, codes.Unauthenticated},
+		{app_auth.ErrUserDisabled, codes.PermissionDenied},
+		{app_auth.ErrLoginRequired, codes.PermissionDenied},
+		{app_auth.ErrRegistrationClosed, codes.PermissionDenied},
+		{app_auth.ErrInviteRequired, codes.PermissionDenied},
+		{app_auth.ErrInvalidInvite, codes.PermissionDenied},
+		{app_auth.ErrDomainNotAllowed, codes.PermissionDenied},
+		{app_auth.ErrUserNotFound, codes.NotFound},
+		{app_auth.ErrSessionNotFound, codes.NotFound},
+		{app_auth.ErrTokenNotFound, codes.NotFound},
+		{app_auth.ErrInviteNotFound, codes.NotFound},
+		{app_auth.ErrUnknownProvider, codes.NotFound},
+		{&app_auth.PasswordPolicyError{}, codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			st := status.Convert(h.statusError(context.Background(), "Failed", tt.err))
+
+			assert.Equal(t, tt.code, st.Code())
+			assert.Equal(t, tt.err.Error(), st.Message())
+		})
+	}
+
+	t.Run("Unexpected Error Is Not Leaked", func(t *testing.T) {
+		st := status.Convert(h.statusError(context.Background(), "Failed to login", errors.New("mongo: connection refused")))
+
+		assert.Equal(t, codes.Internal, st.Code())
+		assert.Equal(t, "Failed to login", st.Message())
+	})
+}
@@ -2,37 +2,79 @@ package auth
 
 import (
 	"errors"
+	"log/slog"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 
 	v1 "github.com/AldiandyaIrsyad/author-notes/api/v1/auth"
 	app_auth "github.com/AldiandyaIrsyad/author-notes/internal/auth"
+	"github.com/AldiandyaIrsyad/author-notes/internal/pagination"
 )
 
 type AuthHTTPHandler struct {
-	service app_auth.AuthService
+	service    app_auth.AuthService
+	middleware *AuthMiddleware
+	logger     *slog.Logger
 }
 
-func NewAuthHTTPHandler(service app_auth.AuthService) *AuthHTTPHandler {
-	return &AuthHTTPHandler{service: service}
+func NewAuthHTTPHandler(service app_auth.AuthService, logger *slog.Logger) *AuthHTTPHandler {
+	return &AuthHTTPHandler{
+		service:    service,
+		middleware: NewAuthMiddleware(service, logger),
+		logger:     logger,
+	}
+}
+
+// internalError logs err and responds with a generic 500 error that does not leak it.
+func (h *AuthHTTPHandler) internalError(c *gin.Context, message string, err error) {
+	h.logger.ErrorContext(c.Request.Context(), message, "route", c.FullPath(), "error", err)
+	c.JSON(http.StatusInternalServerError, gin.H{"error": message})
 }
 
 func (h *AuthHTTPHandler) RegisterRoutes(rg *gin.RouterGroup) {
 	authGroup := rg.Group("/auth")
 	authGroup.POST("/register", h.Register)
 	authGroup.POST("/login", h.Login)
+	authGroup.GET("/oidc", h.ListIdentityProviders)
+	authGroup.POST("/oidc/:provider/start", h.StartOIDCLogin)
+	authGroup.POST("/oidc/:provider/callback", h.CompleteOIDCLogin)
+
+	meGroup := rg.Group("/me", h.middleware.Authenticate())
+	meGroup.GET("", h.GetProfile)
+	meGroup.GET("/audit", h.ListMyAuditEvents)
+	meGroup.POST("/password", RequireLoginToken(), h.ChangePassword)
+
+	// A leaked personal access token must not be able to mint further tokens.
+	tokensGroup := rg.Group("/me/tokens", h.middleware.Authenticate(), RequireLoginToken())
+	tokensGroup.GET("", h.ListPersonalTokens)
+	tokensGroup.POST("", h.CreatePersonalToken)
+	tokensGroup.DELETE("/:id", h.RevokePersonalToken)
+
+	sessionsGroup := rg.Group("/me/sessions", h.middleware.Authenticate(), RequireLoginToken())
+	sessionsGroup.GET("", h.ListSessions)
+	sessionsGroup.DELETE("/:id", h.RevokeSession)
+
+	adminGroup := rg.Group("/admin", h.middleware.Authenticate())
+	adminGroup.GET("/users", RequirePermission(app_auth.PermUsersManage), h.ListUsers)
+	adminGroup.POST("/users/:id/disable", RequirePermission(app_auth.PermUsersManage), h.DisableUser)
+	adminGroup.POST("/users/:id/enable", RequirePermission(app_auth.PermUsersManage), h.EnableUser)
+	adminGroup.GET("/invites", RequirePermission(app_auth.PermUsersManage), h.ListInvites)
+	adminGroup.POST("/invites", RequirePermission(app_auth.PermUsersManage), h.CreateInvite)
+	adminGroup.DELETE("/invites/:id", RequirePermission(app_auth.PermUsersManage), h.RevokeInvite)
+	adminGroup.GET("/audit", RequirePermission(app_auth.PermAuditRead), h.ListAuditEvents)
 }
 
 // Register handles the user registration request.
 // @Summary Register a new user
-// @Description Creates a new user account.
+// @Description Creates a new user account, subject to the registration mode and allowed email domains.
 // @Tags auth
 // @Accept json
 // @Produce json
 // @Param user body v1.RegisterRequest true "User registration details"
 // @Success 201 {object} auth.User "User created successfully (Password field will be empty)"
-// @Failure 400 {object} map[string]string "Validation error or bad request"
+// @Failure 400 {object} map[string]interface{} "Validation error, or password rejected by the password policy with a list of reasons"
+// @Failure 403 {object} map[string]string "Registration closed, invite code missing or invalid, or email domain not allowed"
 // @Failure 409 {object} map[string]string "User already exists"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /v1/auth/register [post]
@@ -45,16 +87,21 @@ func (h *AuthHTTPHandler) Register(c *gin.Context) {
 
 	user, err := h.service.Register(c.Request.Context(), req)
 	if err != nil {
-
+		var policyErr *app_auth.PasswordPolicyError
 		switch {
+		case errors.As(err, &policyErr):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "reasons": policyErr.Reasons})
 		case errors.Is(err, app_auth.ErrValidationFailed): //
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		case errors.Is(err, app_auth.ErrUserAlreadyExists):
 			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, app_auth.ErrRegistrationClosed),
+			errors.Is(err, app_auth.ErrInviteRequired),
+			errors.Is(err, app_auth.ErrInvalidInvite),
+			errors.Is(err, app_auth.ErrDomainNotAllowed):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
 		default:
-			// Log the internal error (consider adding logging)
-			// log.Printf("Internal server error during registration: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to register user"})
+			h.internalError(c, "Failed to register user", err)
 		}
 		return
 	}
@@ -89,13 +136,567 @@ func (h *AuthHTTPHandler) Login(c *gin.Context) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		case errors.Is(err, app_auth.ErrInvalidCredentials):
 			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		case errors.Is(err, app_auth.ErrUserDisabled):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		default:
+			h.internalError(c, "Failed to login", err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.LoginResponse{Token: token})
+}
+
+// ListIdentityProviders handles the request for the available external sign in options.
+// @Summary List identity providers
+// @Description Returns the names of the OpenID Connect providers users can sign in with.
+// @Tags auth
+// @Produce json
+// @Success 200 {object} v1.IdentityProvidersResponse "Provider names"
+// @Router /v1/auth/oidc [get]
+func (h *AuthHTTPHandler) ListIdentityProviders(c *gin.Context) {
+	c.JSON(http.StatusOK, v1.IdentityProvidersResponse{Providers: h.service.IdentityProviders()})
+}
+
+// StartOIDCLogin handles the start of a sign in with an identity provider.
+// @Summary Start an external sign in
+// @Description Returns the provider URL the browser must be sent to. The provider redirects back to the frontend with state and code.
+// @Tags auth
+// @Produce json
+// @Param provider path string true "Identity provider name"
+// @Success 200 {object} v1.OIDCStartResponse "Authorization URL"
+// @Failure 404 {object} map[string]string "Unknown provider"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/auth/oidc/{provider}/start [post]
+func (h *AuthHTTPHandler) StartOIDCLogin(c *gin.Context) {
+	url, err := h.service.BeginOIDCLogin(c.Request.Context(), c.Param("provider"))
+	if err != nil {
+		switch {
+		case errors.Is(err, app_auth.ErrUnknownProvider):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			h.internalError(c, "Failed to start login", err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.OIDCStartResponse{AuthorizationURL: url})
+}
+
+// CompleteOIDCLogin handles the redirect back from an identity provider.
+// @Summary Complete an external sign in
+// @Description Exchanges the code for the provider's ID token, links or creates the user and returns a JWT token.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param provider path string true "Identity provider name"
+// @Param callback body v1.OIDCCallbackRequest true "State and code from the redirect"
+// @Success 200 {object} v1.LoginResponse "Login successful, JWT token returned"
+// @Failure 400 {object} map[string]string "Validation error or invalid state"
+// @Failure 401 {object} map[string]string "Provider authentication failed"
+// @Failure 403 {object} map[string]string "User disabled, or a new account is not allowed by the registration policy"
+// @Failure 404 {object} map[string]string "Unknown provider"
+// @Failure 409 {object} map[string]string "Email belongs to an existing account but is not verified"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/auth/oidc/{provider}/callback [post]
+func (h *AuthHTTPHandler) CompleteOIDCLogin(c *gin.Context) {
+	var req v1.OIDCCallbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	token, err := h.service.CompleteOIDCLogin(c.Request.Context(), c.Param("provider"), req.State, req.Code)
+	if err != nil {
+		switch {
+		case errors.Is(err, app_auth.ErrUnknownProvider):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, app_auth.ErrValidationFailed), errors.Is(err, app_auth.ErrInvalidLoginState):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, app_auth.ErrExternalAuthFailed):
+			c.JSON(http.StatusUnauthorized, gin.H{"error": app_auth.ErrExternalAuthFailed.Error()})
+		case errors.Is(err, app_auth.ErrUserDisabled),
+			errors.Is(err, app_auth.ErrRegistrationClosed),
+			errors.Is(err, app_auth.ErrInviteRequired),
+			errors.Is(err, app_auth.ErrDomainNotAllowed):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, app_auth.ErrIdentityConflict):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
 		default:
-			// Log the internal error (consider adding logging)
-			// log.Printf("Internal server error during login: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to login"})
+			h.internalError(c, "Failed to login", err)
 		}
 		return
 	}
 
 	c.JSON(http.StatusOK, v1.LoginResponse{Token: token})
 }
+
+// ListUsers handles the admin request to list users.
+// @Summary List users
+// @Description Returns a page of user accounts, newest first. Requires the users:manage permission.
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param q query string false "Prefix of the username or email"
+// @Param role query string false "Only users with this role" Enums(author, admin)
+// @Param verified query bool false "Only verified or unverified users"
+// @Param created_after query int false "Only users created at or after this Unix time"
+// @Param created_before query int false "Only users created before this Unix time"
+// @Param cursor query string false "Cursor returned as next_cursor by the previous page"
+// @Param limit query int false "Page size (1-100, default 20)"
+// @Success 200 {object} auth.UserPage "Users"
+// @Failure 400 {object} map[string]string "Invalid filter or cursor"
+// @Failure 401 {object} map[string]string "Missing or invalid token"
+// @Failure 403 {object} map[string]string "Missing permission"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/admin/users [get]
+func (h *AuthHTTPHandler) ListUsers(c *gin.Context) {
+	var req v1.ListUsersRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query parameters: " + err.Error()})
+		return
+	}
+
+	page, err := h.service.ListUsers(c.Request.Context(), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, app_auth.ErrValidationFailed), errors.Is(err, pagination.ErrInvalidCursor):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			h.internalError(c, "Failed to list users", err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// DisableUser handles the admin request to disable a user account.
+// @Summary Disable a user
+// @Description Prevents the user from logging in. Requires the users:manage permission.
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} auth.User "Updated user"
+// @Failure 400 {object} map[string]string "Admins cannot disable themselves"
+// @Failure 401 {object} map[string]string "Missing or invalid token"
+// @Failure 403 {object} map[string]string "Missing permission"
+// @Failure 404 {object} map[string]string "User not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/admin/users/{id}/disable [post]
+func (h *AuthHTTPHandler) DisableUser(c *gin.Context) {
+	h.setUserDisabled(c, true)
+}
+
+// EnableUser handles the admin request to re-enable a disabled user account.
+// @Summary Re-enable a user
+// @Description Allows a disabled user to log in again. Requires the users:manage permission.
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} auth.User "Updated user"
+// @Failure 400 {object} map[string]string "Admins cannot enable themselves"
+// @Failure 401 {object} map[string]string "Missing or invalid token"
+// @Failure 403 {object} map[string]string "Missing permission"
+// @Failure 404 {object} map[string]string "User not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/admin/users/{id}/enable [post]
+func (h *AuthHTTPHandler) EnableUser(c *gin.Context) {
+	h.setUserDisabled(c, false)
+}
+
+func (h *AuthHTTPHandler) setUserDisabled(c *gin.Context, disabled bool) {
+	claims, _ := app_auth.ClaimsFromContext(c.Request.Context())
+
+	user, err := h.service.SetUserDisabled(c.Request.Context(), claims.UserID, c.Param("id"), disabled)
+	if err != nil {
+		switch {
+		case errors.Is(err, app_auth.ErrCannotModifySelf):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, app_auth.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			h.internalError(c, "Failed to update user", err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// ChangePassword handles the request to change the caller's password.
+// @Summary Change my password
+// @Description Replaces the caller's password and logs out all other sessions.
+// @Tags account
+// @Accept json
+// @Security BearerAuth
+// @Param password body v1.ChangePasswordRequest true "Current and new password"
+// @Success 204 "Password changed"
+// @Failure 400 {object} map[string]interface{} "Validation error, or password rejected by the password policy with a list of reasons"
+// @Failure 401 {object} map[string]string "Missing or invalid token, or wrong current password"
+// @Failure 403 {object} map[string]string "Called with a personal access token"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/me/password [post]
+func (h *AuthHTTPHandler) ChangePassword(c *gin.Context) {
+	claims, _ := app_auth.ClaimsFromContext(c.Request.Context())
+
+	var req v1.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	if err := h.service.ChangePassword(c.Request.Context(), claims.UserID, claims.SessionID, req); err != nil {
+		var policyErr *app_auth.PasswordPolicyError
+		switch {
+		case errors.As(err, &policyErr):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "reasons": policyErr.Reasons})
+		case errors.Is(err, app_auth.ErrValidationFailed):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, app_auth.ErrInvalidCredentials):
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "current password is incorrect"})
+		default:
+			h.internalError(c, "Failed to change password", err)
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetProfile handles the request for the caller's own account.
+// @Summary Get my profile
+// @Description Returns the account of the authenticated user.
+// @Tags account
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} auth.User "Profile"
+// @Failure 401 {object} map[string]string "Missing or invalid token"
+// @Failure 404 {object} map[string]string "User not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/me [get]
+func (h *AuthHTTPHandler) GetProfile(c *gin.Context) {
+	claims, _ := app_auth.ClaimsFromContext(c.Request.Context())
+
+	user, err := h.service.GetProfile(c.Request.Context(), claims.UserID)
+	if err != nil {
+		if errors.Is(err, app_auth.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		h.internalError(c, "Failed to get profile", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// ListMyAuditEvents handles the request for the caller's own security events.
+// @Summary List my security events
+// @Description Returns a page of authentication events where the caller is the actor or the target, newest first.
+// @Tags audit
+// @Produce json
+// @Security BearerAuth
+// @Param action query string false "Only events with this action"
+// @Param outcome query string false "Only events with this outcome" Enums(success, failure)
+// @Param cursor query string false "Cursor returned as next_cursor by the previous page"
+// @Param limit query int false "Page size (1-200, default 50)"
+// @Success 200 {object} auth.AuditPage "Events"
+// @Failure 400 {object} map[string]string "Invalid filter or cursor"
+// @Failure 401 {object} map[string]string "Missing or invalid token"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/me/audit [get]
+func (h *AuthHTTPHandler) ListMyAuditEvents(c *gin.Context) {
+	claims, _ := app_auth.ClaimsFromContext(c.Request.Context())
+
+	var req v1.ListAuditEventsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query parameters: " + err.Error()})
+		return
+	}
+	req.UserID = claims.UserID
+
+	h.listAuditEvents(c, req)
+}
+
+// ListAuditEvents handles the admin request for security events of all users.
+// @Summary List security events
+// @Description Returns a page of authentication events of all users, newest first. Requires the audit:read permission.
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param user_id query string false "Only events where this user is the actor or the target"
+// @Param action query string false "Only events with this action"
+// @Param outcome query string false "Only events with this outcome" Enums(success, failure)
+// @Param cursor query string false "Cursor returned as next_cursor by the previous page"
+// @Param limit query int false "Page size (1-200, default 50)"
+// @Success 200 {object} auth.AuditPage "Events"
+// @Failure 400 {object} map[string]string "Invalid filter or cursor"
+// @Failure 401 {object} map[string]string "Missing or invalid token"
+// @Failure 403 {object} map[string]string "Missing permission"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/admin/audit [get]
+func (h *AuthHTTPHandler) ListAuditEvents(c *gin.Context) {
+	var req v1.ListAuditEventsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query parameters: " + err.Error()})
+		return
+	}
+
+	h.listAuditEvents(c, req)
+}
+
+func (h *AuthHTTPHandler) listAuditEvents(c *gin.Context, req v1.ListAuditEventsRequest) {
+	page, err := h.service.ListAuditEvents(c.Request.Context(), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, app_auth.ErrValidationFailed), errors.Is(err, pagination.ErrInvalidCursor):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			h.internalError(c, "Failed to list audit events", err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// ListSessions handles the request to list the caller's active sessions.
+// @Summary List my sessions
+// @Description Returns the devices the caller is logged in on. The session making the request is marked as current.
+// @Tags sessions
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} auth.Session "Sessions"
+// @Failure 401 {object} map[string]string "Missing or invalid token"
+// @Failure 403 {object} map[string]string "Called with a personal access token"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/me/sessions [get]
+func (h *AuthHTTPHandler) ListSessions(c *gin.Context) {
+	claims, _ := app_auth.ClaimsFromContext(c.Request.Context())
+
+	sessions, err := h.service.ListSessions(c.Request.Context(), claims.UserID, claims.SessionID)
+	if err != nil {
+		h.internalError(c, "Failed to list sessions", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, sessions)
+}
+
+// RevokeSession handles the request to log out one of the caller's sessions.
+// @Summary Revoke a session
+// @Description Logs the caller out on one device; tokens of the session stop working immediately.
+// @Tags sessions
+// @Security BearerAuth
+// @Param id path string true "Session ID"
+// @Success 204 "Session revoked"
+// @Failure 401 {object} map[string]string "Missing or invalid token"
+// @Failure 403 {object} map[string]string "Called with a personal access token"
+// @Failure 404 {object} map[string]string "Session not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/me/sessions/{id} [delete]
+func (h *AuthHTTPHandler) RevokeSession(c *gin.Context) {
+	claims, _ := app_auth.ClaimsFromContext(c.Request.Context())
+
+	if err := h.service.RevokeSession(c.Request.Context(), claims.UserID, c.Param("id")); err != nil {
+		switch {
+		case errors.Is(err, app_auth.ErrSessionNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			h.internalError(c, "Failed to revoke session", err)
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListPersonalTokens handles the request to list the caller's personal access tokens.
+// @Summary List my personal access tokens
+// @Description Returns the caller's personal access tokens without their secrets.
+// @Tags tokens
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} auth.PersonalAccessToken "Tokens"
+// @Failure 401 {object} map[string]string "Missing or invalid token"
+// @Failure 403 {object} map[string]string "Called with a personal access token"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/me/tokens [get]
+func (h *AuthHTTPHandler) ListPersonalTokens(c *gin.Context) {
+	claims, _ := app_auth.ClaimsFromContext(c.Request.Context())
+
+	tokens, err := h.service.ListPersonalTokens(c.Request.Context(), claims.UserID)
+	if err != nil {
+		h.internalError(c, "Failed to list tokens", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// CreatePersonalToken handles the request to create a personal access token.
+// @Summary Create a personal access token
+// @Description Creates a scoped token for scripts and integrations. The secret is only returned once.
+// @Tags tokens
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param token body v1.CreatePersonalTokenRequest true "Token name, scopes and optional expiry"
+// @Success 201 {object} v1.CreatePersonalTokenResponse "Token created"
+// @Failure 400 {object} map[string]string "Validation error or scope not granted"
+// @Failure 401 {object} map[string]string "Missing or invalid token"
+// @Failure 403 {object} map[string]string "Called with a personal access token"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/me/tokens [post]
+func (h *AuthHTTPHandler) CreatePersonalToken(c *gin.Context) {
+	claims, _ := app_auth.ClaimsFromContext(c.Request.Context())
+
+	var req v1.CreatePersonalTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	secret, token, err := h.service.CreatePersonalToken(c.Request.Context(), claims.UserID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, app_auth.ErrValidationFailed), errors.Is(err, app_auth.ErrInvalidScope):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			h.internalError(c, "Failed to create token", err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, v1.CreatePersonalTokenResponse{
+		ID:        token.ID,
+		Name:      token.Name,
+		Scopes:    token.Scopes,
+		Token:     secret,
+		CreatedAt: token.CreatedAt,
+		ExpiresAt: token.ExpiresAt,
+	})
+}
+
+// RevokePersonalToken handles the request to revoke a personal access token.
+// @Summary Revoke a personal access token
+// @Description Deletes one of the caller's personal access tokens; it stops working immediately.
+// @Tags tokens
+// @Security BearerAuth
+// @Param id path string true "Token ID"
+// @Success 204 "Token revoked"
+// @Failure 401 {object} map[string]string "Missing or invalid token"
+// @Failure 403 {object} map[string]string "Called with a personal access token"
+// @Failure 404 {object} map[string]string "Token not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/me/tokens/{id} [delete]
+func (h *AuthHTTPHandler) RevokePersonalToken(c *gin.Context) {
+	claims, _ := app_auth.ClaimsFromContext(c.Request.Context())
+
+	if err := h.service.RevokePersonalToken(c.Request.Context(), claims.UserID, c.Param("id")); err != nil {
+		switch {
+		case errors.Is(err, app_auth.ErrTokenNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			h.internalError(c, "Failed to revoke token", err)
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListInvites handles the admin request to list invite codes.
+// @Summary List invite codes
+// @Description Returns all invite codes, newest first. The codes themselves are not included. Requires the users:manage permission.
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} auth.InviteCode "Invite codes"
+// @Failure 401 {object} map[string]string "Missing or invalid token"
+// @Failure 403 {object} map[string]string "Missing permission"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/admin/invites [get]
+func (h *AuthHTTPHandler) ListInvites(c *gin.Context) {
+	invites, err := h.service.ListInvites(c.Request.Context())
+	if err != nil {
+		h.internalError(c, "Failed to list invites", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, invites)
+}
+
+// CreateInvite handles the admin request to create an invite code.
+// @Summary Create an invite code
+// @Description Creates an expiring, single or multi-use invite code. The code is only returned once. Requires the users:manage permission.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param invite body v1.CreateInviteRequest true "Number of uses and expiry"
+// @Success 201 {object} v1.CreateInviteResponse "Invite created"
+// @Failure 400 {object} map[string]string "Validation error"
+// @Failure 401 {object} map[string]string "Missing or invalid token"
+// @Failure 403 {object} map[string]string "Missing permission"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/admin/invites [post]
+func (h *AuthHTTPHandler) CreateInvite(c *gin.Context) {
+	claims, _ := app_auth.ClaimsFromContext(c.Request.Context())
+
+	var req v1.CreateInviteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	code, invite, err := h.service.CreateInvite(c.Request.Context(), claims.UserID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, app_auth.ErrValidationFailed):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			h.internalError(c, "Failed to create invite", err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, v1.CreateInviteResponse{
+		ID:        invite.ID,
+		Code:      code,
+		MaxUses:   invite.MaxUses,
+		CreatedAt: invite.CreatedAt,
+		ExpiresAt: invite.ExpiresAt,
+	})
+}
+
+// RevokeInvite handles the admin request to revoke an invite code.
+// @Summary Revoke an invite code
+// @Description Deletes an invite code so that it can no longer be used. Requires the users:manage permission.
+// @Tags admin
+// @Security BearerAuth
+// @Param id path string true "Invite ID"
+// @Success 204 "Invite revoked"
+// @Failure 401 {object} map[string]string "Missing or invalid token"
+// @Failure 403 {object} map[string]string "Missing permission"
+// @Failure 404 {object} map[string]string "Invite not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/admin/invites/{id} [delete]
+func (h *AuthHTTPHandler) RevokeInvite(c *gin.Context) {
+	claims, _ := app_auth.ClaimsFromContext(c.Request.Context())
+
+	if err := h.service.RevokeInvite(c.Request.Context(), claims.UserID, c.Param("id")); err != nil {
+		switch {
+		case errors.Is(err, app_auth.ErrInviteNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			h.internalError(c, "Failed to revoke invite", err)
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,39 @@
+package auth
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	app_auth "github.com/AldiandyaIrsyad/author-notes/internal/auth"
+)
+
+// prometheusMetrics implements the Metrics interface with Prometheus counters.
+type prometheusMetrics struct {
+	events           *prometheus.CounterVec
+	tokenValidations *prometheus.CounterVec
+}
+
+// NewPrometheusMetrics creates the authentication counters and registers them with reg.
+func NewPrometheusMetrics(reg prometheus.Registerer) app_auth.Metrics {
+	m := &prometheusMetrics{
+		events: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "auth_events_total",
+			Help: "Authentication events such as registrations and logins, by action and outcome.",
+		}, []string{"action", "outcome"}),
+		tokenValidations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "auth_token_validations_total",
+			Help: "Token validations by token kind and result.",
+		}, []string{"kind", "result"}),
+	}
+	reg.MustRegister(m.events, m.tokenValidations)
+	return m
+}
+
+// AuthEvent counts an audited event.
+func (m *prometheusMetrics) AuthEvent(action, outcome string) {
+	m.events.WithLabelValues(action, outcome).Inc()
+}
+
+// TokenValidated counts a token validation.
+func (m *prometheusMetrics) TokenValidated(kind, result string) {
+	m.tokenValidations.WithLabelValues(kind, result).Inc()
+}
@@ -0,0 +1,94 @@
+package auth
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	app_auth "github.com/AldiandyaIrsyad/author-notes/internal/auth"
+)
+
+// AuthMiddleware authenticates requests using tokens validated by the AuthService.
+type AuthMiddleware struct {
+	service app_auth.AuthService
+	logger  *slog.Logger
+}
+
+func NewAuthMiddleware(service app_auth.AuthService, logger *slog.Logger) *AuthMiddleware {
+	return &AuthMiddleware{service: service, logger: logger}
+}
+
+// CaptureClientInfo stores the client IP and user agent in the request context
+// (see app_auth.ClientInfoFromContext) for sessions and auditing.
+func CaptureClientInfo() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		info := app_auth.ClientInfo{IP: c.ClientIP(), UserAgent: c.Request.UserAgent()}
+		c.Request = c.Request.WithContext(app_auth.ContextWithClientInfo(c.Request.Context(), info))
+		c.Next()
+	}
+}
+
+// Authenticate requires a valid "Authorization: Bearer <token>" header and stores
+// the resulting claims in the request context (see app_auth.ClaimsFromContext).
+func (m *AuthMiddleware) Authenticate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		scheme, token, found := strings.Cut(header, " ")
+		if !found || !strings.EqualFold(scheme, "Bearer") || token == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Missing or malformed authorization header"})
+			return
+		}
+
+		claims, err := m.service.ValidateToken(c.Request.Context(), token)
+		if err != nil {
+			switch {
+			case errors.Is(err, app_auth.ErrTokenExpired), errors.Is(err, app_auth.ErrTokenInvalid):
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			default:
+				m.logger.ErrorContext(c.Request.Context(), "authenticating request failed", "error", err)
+				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to authenticate request"})
+			}
+			return
+		}
+
+		c.Request = c.Request.WithContext(app_auth.ContextWithClaims(c.Request.Context(), claims))
+		c.Next()
+	}
+}
+
+// RequirePermission rejects requests whose claims lack the given permission.
+// It must run after Authenticate.
+func RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := app_auth.ClaimsFromContext(c.Request.Context())
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+			return
+		}
+		if !claims.HasPermission(permission) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Missing permission " + permission})
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireLoginToken rejects requests authenticated with a personal access token.
+// It must run after Authenticate.
+func RequireLoginToken() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := app_auth.ClaimsFromContext(c.Request.Context())
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+			return
+		}
+		if claims.PersonalTokenID != "" {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": app_auth.ErrLoginRequired.Error()})
+			return
+		}
+		c.Next()
+	}
+}
@@ -3,23 +3,36 @@ package auth
 import (
 	"context"
 	"errors"
+	"regexp"
+	"time"
 
 	"github.com/google/uuid"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 
 	app_auth "github.com/AldiandyaIrsyad/author-notes/internal/auth"
+	"github.com/AldiandyaIrsyad/author-notes/internal/pagination"
 )
 
 // mongoAuthRepository implements the AuthRepository interface using MongoDB.
 type mongoAuthRepository struct {
-	collection *mongo.Collection
+	collection  *mongo.Collection
+	tokens      *mongo.Collection
+	sessions    *mongo.Collection
+	loginStates *mongo.Collection
+	invites     *mongo.Collection
 }
 
 // NewMongoAuthRepository creates a new instance of mongoAuthRepository.
 func NewMongoAuthRepository(db *mongo.Database) app_auth.AuthRepository {
 	return &mongoAuthRepository{
-		collection: db.Collection("users"), // Assuming the collection name is "users"
+		collection:  db.Collection("users"), // Assuming the collection name is "users"
+		tokens:      db.Collection("personal_tokens"),
+		sessions:    db.Collection("sessions"),
+		loginStates: db.Collection("oidc_login_states"),
+		invites:     db.Collection("invites"),
 	}
 }
 
@@ -34,6 +47,18 @@ func (r *mongoAuthRepository) CreateUser(ctx context.Context, user *app_auth.Use
 	return err
 }
 
+// UpdateUser replaces a stored user with the given one.
+func (r *mongoAuthRepository) UpdateUser(ctx context.Context, user *app_auth.User) error {
+	result, err := r.collection.ReplaceOne(ctx, bson.M{"_id": user.ID}, user)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return app_auth.ErrUserNotFound
+	}
+	return nil
+}
+
 // FindByUsername retrieves a user by their username.
 func (r *mongoAuthRepository) FindByUsername(ctx context.Context, username string) (*app_auth.User, error) {
 	var user app_auth.User
@@ -67,6 +92,33 @@ func (r *mongoAuthRepository) FindByEmailOrUsername(ctx context.Context, email,
 	return &user, nil
 }
 
+// FindByEmail retrieves a user by their email.
+func (r *mongoAuthRepository) FindByEmail(ctx context.Context, email string) (*app_auth.User, error) {
+	var user app_auth.User
+	err := r.collection.FindOne(ctx, bson.M{"email": email}).Decode(&user)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, app_auth.ErrUserNotFound
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+// FindByIdentity retrieves the user linked to an external identity.
+func (r *mongoAuthRepository) FindByIdentity(ctx context.Context, provider, subject string) (*app_auth.User, error) {
+	var user app_auth.User
+	filter := bson.M{"identities": bson.M{"$elemMatch": bson.M{"provider": provider, "subject": subject}}}
+	err := r.collection.FindOne(ctx, filter).Decode(&user)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, app_auth.ErrUserNotFound
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
 // FindByID retrieves a user by their ID.
 func (r *mongoAuthRepository) FindByID(ctx context.Context, id string) (*app_auth.User, error) {
 	var user app_auth.User
@@ -80,3 +132,316 @@ func (r *mongoAuthRepository) FindByID(ctx context.Context, id string) (*app_aut
 	}
 	return &user, nil
 }
+
+// DeleteUser permanently removes a user along with their sessions and personal access tokens.
+func (r *mongoAuthRepository) DeleteUser(ctx context.Context, id string) error {
+	if _, err := r.tokens.DeleteMany(ctx, bson.M{"user_id": id}); err != nil {
+		return err
+	}
+	if _, err := r.sessions.DeleteMany(ctx, bson.M{"user_id": id}); err != nil {
+		return err
+	}
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return app_auth.ErrUserNotFound
+	}
+	return nil
+}
+
+// FindUsersDueForPurge retrieves soft-deleted users whose purge time has passed.
+func (r *mongoAuthRepository) FindUsersDueForPurge(ctx context.Context, now int64) ([]*app_auth.User, error) {
+	filter := bson.M{"purge_at": bson.M{"$gt": 0, "$lte": now}}
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	users := []*app_auth.User{}
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// ListUsers retrieves one page of users matching the filter, newest first.
+func (r *mongoAuthRepository) ListUsers(ctx context.Context, filter app_auth.UserFilter) (*app_auth.UserPage, error) {
+	conditions := []bson.M{}
+	if filter.Search != "" {
+		prefix := primitive.Regex{Pattern: "^" + regexp.QuoteMeta(filter.Search), Options: "i"}
+		conditions = append(conditions, bson.M{"$or": []bson.M{
+			{"username": prefix},
+			{"email": prefix},
+		}})
+	}
+	if filter.Role == app_auth.RoleAuthor {
+		// Accounts created before roles existed have no roles and count as authors.
+		conditions = append(conditions, bson.M{"$or": []bson.M{
+			{"roles": app_auth.RoleAuthor},
+			{"roles": bson.M{"$in": bson.A{nil, bson.A{}}}},
+		}})
+	} else if filter.Role != "" {
+		conditions = append(conditions, bson.M{"roles": filter.Role})
+	}
+	if filter.Verified != nil {
+		if *filter.Verified {
+			conditions = append(conditions, bson.M{"verified": true})
+		} else {
+			conditions = append(conditions, bson.M{"verified": bson.M{"$ne": true}})
+		}
+	}
+	if filter.CreatedAfter != 0 {
+		conditions = append(conditions, bson.M{"created_at": bson.M{"$gte": filter.CreatedAfter}})
+	}
+	if filter.CreatedBefore != 0 {
+		conditions = append(conditions, bson.M{"created_at": bson.M{"$lt": filter.CreatedBefore}})
+	}
+	if filter.Cursor != "" {
+		cursor, err := pagination.DecodeCursor(filter.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, bson.M{"$or": []bson.M{
+			{"created_at": bson.M{"$lt": cursor.CreatedAt}},
+			{"created_at": cursor.CreatedAt, "_id": bson.M{"$lt": cursor.ID}},
+		}})
+	}
+
+	query := bson.M{}
+	if len(conditions) > 0 {
+		query["$and"] = conditions
+	}
+
+	// Fetch one extra document to find out whether another page exists.
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(int64(filter.Limit) + 1)
+	cursor, err := r.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	users := []*app_auth.User{}
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, err
+	}
+
+	page := &app_auth.UserPage{Users: users}
+	if len(users) > filter.Limit {
+		page.Users = users[:filter.Limit]
+		last := page.Users[filter.Limit-1]
+		page.NextCursor = pagination.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}.Encode()
+	}
+	return page, nil
+}
+
+// CreateSession inserts a new session. Expired sessions are cleaned up on the way.
+func (r *mongoAuthRepository) CreateSession(ctx context.Context, session *app_auth.Session) error {
+	if session.ID == "" {
+		session.ID = uuid.NewString()
+	}
+	if _, err := r.sessions.DeleteMany(ctx, bson.M{"user_id": session.UserID, "expires_at": bson.M{"$lte": session.CreatedAt}}); err != nil {
+		return err
+	}
+	_, err := r.sessions.InsertOne(ctx, session)
+	return err
+}
+
+// FindSession retrieves an unexpired session by its ID.
+func (r *mongoAuthRepository) FindSession(ctx context.Context, id string) (*app_auth.Session, error) {
+	var session app_auth.Session
+	filter := bson.M{"_id": id, "expires_at": bson.M{"$gt": time.Now().Unix()}}
+	err := r.sessions.FindOne(ctx, filter).Decode(&session)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, app_auth.ErrSessionNotFound
+		}
+		return nil, err
+	}
+	return &session, nil
+}
+
+// ListSessions retrieves the unexpired sessions of a user, most recently seen first.
+func (r *mongoAuthRepository) ListSessions(ctx context.Context, userID string) ([]*app_auth.Session, error) {
+	filter := bson.M{"user_id": userID, "expires_at": bson.M{"$gt": time.Now().Unix()}}
+	opts := options.Find().SetSort(bson.D{{Key: "last_seen_at", Value: -1}})
+	cursor, err := r.sessions.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	sessions := []*app_auth.Session{}
+	if err := cursor.All(ctx, &sessions); err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// TouchSession records when and from where a session was last seen.
+func (r *mongoAuthRepository) TouchSession(ctx context.Context, id string, lastSeenAt int64, ip string) error {
+	update := bson.M{"last_seen_at": lastSeenAt}
+	if ip != "" {
+		update["ip"] = ip
+	}
+	_, err := r.sessions.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": update})
+	return err
+}
+
+// DeleteSession removes a session of the user.
+func (r *mongoAuthRepository) DeleteSession(ctx context.Context, userID, id string) error {
+	result, err := r.sessions.DeleteOne(ctx, bson.M{"_id": id, "user_id": userID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return app_auth.ErrSessionNotFound
+	}
+	return nil
+}
+
+// DeleteSessionsByUser removes every session of a user.
+func (r *mongoAuthRepository) DeleteSessionsByUser(ctx context.Context, userID string) error {
+	_, err := r.sessions.DeleteMany(ctx, bson.M{"user_id": userID})
+	return err
+}
+
+// CreatePersonalToken inserts a new personal access token.
+func (r *mongoAuthRepository) CreatePersonalToken(ctx context.Context, token *app_auth.PersonalAccessToken) error {
+	if token.ID == "" {
+		token.ID = uuid.NewString()
+	}
+	_, err := r.tokens.InsertOne(ctx, token)
+	return err
+}
+
+// FindPersonalTokenByHash retrieves a personal access token by the hash of its secret.
+func (r *mongoAuthRepository) FindPersonalTokenByHash(ctx context.Context, hash string) (*app_auth.PersonalAccessToken, error) {
+	var token app_auth.PersonalAccessToken
+	err := r.tokens.FindOne(ctx, bson.M{"hash": hash}).Decode(&token)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, app_auth.ErrTokenNotFound
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+// ListPersonalTokens retrieves the personal access tokens of a user, newest first.
+func (r *mongoAuthRepository) ListPersonalTokens(ctx context.Context, userID string) ([]*app_auth.PersonalAccessToken, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := r.tokens.Find(ctx, bson.M{"user_id": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	tokens := []*app_auth.PersonalAccessToken{}
+	if err := cursor.All(ctx, &tokens); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// DeletePersonalToken removes a personal access token owned by the user.
+func (r *mongoAuthRepository) DeletePersonalToken(ctx context.Context, userID, id string) error {
+	result, err := r.tokens.DeleteOne(ctx, bson.M{"_id": id, "user_id": userID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return app_auth.ErrTokenNotFound
+	}
+	return nil
+}
+
+// TouchPersonalToken records when a personal access token was last used.
+func (r *mongoAuthRepository) TouchPersonalToken(ctx context.Context, id string, lastUsedAt int64) error {
+	_, err := r.tokens.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"last_used_at": lastUsedAt}})
+	return err
+}
+
+// CreateInvite inserts a new invite code.
+func (r *mongoAuthRepository) CreateInvite(ctx context.Context, invite *app_auth.InviteCode) error {
+	if invite.ID == "" {
+		invite.ID = uuid.NewString()
+	}
+	_, err := r.invites.InsertOne(ctx, invite)
+	return err
+}
+
+// RedeemInvite uses up one use of an invite code in a single operation, so
+// that concurrent registrations cannot exceed its uses.
+func (r *mongoAuthRepository) RedeemInvite(ctx context.Context, hash string, now int64) (*app_auth.InviteCode, error) {
+	filter := bson.M{
+		"hash":       hash,
+		"expires_at": bson.M{"$gt": now},
+		"$expr":      bson.M{"$lt": bson.A{"$uses", "$max_uses"}},
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var invite app_auth.InviteCode
+	err := r.invites.FindOneAndUpdate(ctx, filter, bson.M{"$inc": bson.M{"uses": 1}}, opts).Decode(&invite)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, app_auth.ErrInvalidInvite
+		}
+		return nil, err
+	}
+	return &invite, nil
+}
+
+// ListInvites retrieves all invite codes, newest first.
+func (r *mongoAuthRepository) ListInvites(ctx context.Context) ([]*app_auth.InviteCode, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := r.invites.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	invites := []*app_auth.InviteCode{}
+	if err := cursor.All(ctx, &invites); err != nil {
+		return nil, err
+	}
+	return invites, nil
+}
+
+// DeleteInvite removes an invite code.
+func (r *mongoAuthRepository) DeleteInvite(ctx context.Context, id string) error {
+	result, err := r.invites.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return app_auth.ErrInviteNotFound
+	}
+	return nil
+}
+
+// CreateLoginState stores the state of a started OpenID Connect sign in.
+// Abandoned states are cleaned up on the way.
+func (r *mongoAuthRepository) CreateLoginState(ctx context.Context, state *app_auth.OIDCLoginState) error {
+	if _, err := r.loginStates.DeleteMany(ctx, bson.M{"expires_at": bson.M{"$lt": state.CreatedAt}}); err != nil {
+		return err
+	}
+	_, err := r.loginStates.InsertOne(ctx, state)
+	return err
+}
+
+// ConsumeLoginState retrieves and removes a login state in a single operation.
+func (r *mongoAuthRepository) ConsumeLoginState(ctx context.Context, state string) (*app_auth.OIDCLoginState, error) {
+	var loginState app_auth.OIDCLoginState
+	err := r.loginStates.FindOneAndDelete(ctx, bson.M{"_id": state}).Decode(&loginState)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, app_auth.ErrInvalidLoginState
+		}
+		return nil, err
+	}
+	return &loginState, nil
+}
@@ -0,0 +1,136 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	v1 "github.com/AldiandyaIrsyad/author-notes/api/v1/auth"
+	"github.com/AldiandyaIrsyad/author-notes/internal/logging"
+	"github.com/AldiandyaIrsyad/author-notes/internal/pagination"
+)
+
+// Audit actions recorded by the AuthService.
+const (
+	AuditRegister       = "register"
+	AuditLogin          = "login"
+	AuditOIDCLogin      = "oidc_login"
+	AuditPasswordChange = "password_change"
+	AuditSessionRevoke  = "session_revoke"
+	AuditTokenCreate    = "token_create"
+	AuditTokenRevoke    = "token_revoke"
+	AuditUserDisable    = "user_disable"
+	AuditUserEnable     = "user_enable"
+	AuditInviteCreate   = "invite_create"
+	AuditInviteRevoke   = "invite_revoke"
+)
+
+// Audit outcomes.
+const (
+	AuditSuccess = "success"
+	AuditFailure = "failure"
+)
+
+// AuditEvent is an immutable record of a security relevant action.
+type AuditEvent struct {
+	ID        string `bson:"_id,omitempty" json:"id"`
+	ActorID   string `bson:"actor_id,omitempty" json:"actor_id,omitempty"`   // user who performed the action, empty if unknown
+	TargetID  string `bson:"target_id,omitempty" json:"target_id,omitempty"` // user the action was performed on, if not the actor
+	Username  string `bson:"username,omitempty" json:"username,omitempty"`   // as submitted, for failed logins of unknown users
+	Action    string `bson:"action" json:"action"`
+	Outcome   string `bson:"outcome" json:"outcome"`
+	Reason    string `bson:"reason,omitempty" json:"reason,omitempty"`
+	IP        string `bson:"ip" json:"ip"`
+	UserAgent string `bson:"user_agent" json:"user_agent"`
+	RequestID string `bson:"request_id,omitempty" json:"request_id,omitempty"` // correlates the event with the request logs
+	CreatedAt int64  `bson:"created_at" json:"created_at"`
+}
+
+// AuditFilter narrows down the events returned by AuditLog.List.
+// Zero values disable the corresponding filter.
+type AuditFilter struct {
+	UserID  string // matches events where the user is the actor or the target
+	Action  string
+	Outcome string
+	Cursor  string
+	Limit   int
+}
+
+// AuditPage is one page of audit events, newest first.
+type AuditPage struct {
+	Events     []*AuditEvent `json:"events"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+}
+
+// AuditLog is an append-only store of audit events. Events are removed only
+// by the store's own retention policy.
+type AuditLog interface {
+	// Append records a new event.
+	Append(ctx context.Context, event *AuditEvent) error
+	// List retrieves one page of events matching the filter, newest first.
+	// Returns pagination.ErrInvalidCursor if filter.Cursor was not produced by a previous call.
+	List(ctx context.Context, filter AuditFilter) (*AuditPage, error)
+}
+
+// WithAuditLog records authentication events to the given log.
+func WithAuditLog(log AuditLog) Option {
+	return func(s *authService) {
+		s.auditLog = log
+	}
+}
+
+// audit counts an event and records it with the client information of ctx.
+// Recording is best effort: a failing audit log must not lock users out.
+func (s *authService) audit(ctx context.Context, event AuditEvent) {
+	if event.Outcome == "" {
+		event.Outcome = AuditSuccess
+	}
+	s.metrics.AuthEvent(event.Action, event.Outcome)
+	if s.auditLog == nil {
+		return
+	}
+	client := ClientInfoFromContext(ctx)
+	event.IP = client.IP
+	event.UserAgent = client.UserAgent
+	event.CreatedAt = time.Now().Unix()
+	event.RequestID = logging.RequestIDFromContext(ctx)
+	if err := s.auditLog.Append(ctx, &event); err != nil {
+		s.logger.ErrorContext(ctx, "writing audit event failed", "action", event.Action, "error", err)
+	}
+}
+
+// auditFailure records a failed action together with the reason.
+func (s *authService) auditFailure(ctx context.Context, event AuditEvent, reason error) {
+	event.Outcome = AuditFailure
+	event.Reason = reason.Error()
+	s.audit(ctx, event)
+}
+
+// ListAuditEvents returns one page of audit events matching the request.
+func (s *authService) ListAuditEvents(ctx context.Context, req v1.ListAuditEventsRequest) (*AuditPage, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, ErrValidationFailed
+	}
+	if req.Cursor != "" {
+		if _, err := pagination.DecodeCursor(req.Cursor); err != nil {
+			return nil, err
+		}
+	}
+	if s.auditLog == nil {
+		return &AuditPage{Events: []*AuditEvent{}}, nil
+	}
+
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultAuditPageSize
+	}
+	return s.auditLog.List(ctx, AuditFilter{
+		UserID:  req.UserID,
+		Action:  req.Action,
+		Outcome: req.Outcome,
+		Cursor:  req.Cursor,
+		Limit:   limit,
+	})
+}
+
+// defaultAuditPageSize is used by ListAuditEvents when the request does not specify a limit.
+const defaultAuditPageSize = 50
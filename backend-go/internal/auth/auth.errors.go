@@ -6,7 +6,23 @@ var (
 	ErrUserAlreadyExists  = errors.New("user already exists")
 	ErrInvalidCredentials = errors.New("invalid username or password")
 	ErrUserNotFound       = errors.New("user not found")
+	ErrUserDisabled       = errors.New("user account is disabled")
+	ErrCannotModifySelf   = errors.New("cannot change the status of your own account")
 	ErrTokenExpired       = errors.New("token expired")
 	ErrTokenInvalid       = errors.New("token invalid")
-	ErrValidationFailed   = errors.New("input validation failed") 
+	ErrSessionNotFound    = errors.New("session not found")
+	ErrTokenNotFound      = errors.New("personal access token not found")
+	ErrInvalidScope       = errors.New("scope is unknown or not granted to the user")
+	ErrLoginRequired      = errors.New("this action requires a login token, not a personal access token")
+	ErrUnknownProvider    = errors.New("unknown identity provider")
+	ErrInvalidLoginState  = errors.New("invalid or expired login state")
+	ErrExternalAuthFailed = errors.New("identity provider authentication failed")
+	ErrIdentityConflict   = errors.New("an account with this email already exists and the provider did not verify the email")
+	ErrValidationFailed   = errors.New("input validation failed")
+	ErrRegistrationClosed = errors.New("registration is closed")
+	ErrInviteRequired     = errors.New("registration requires an invite code")
+	ErrInvalidInvite      = errors.New("invite code is invalid, expired or used up")
+	ErrInviteNotFound     = errors.New("invite code not found")
+	ErrDomainNotAllowed   = errors.New("registration is not open to this email domain")
+	ErrWeakPassword       = errors.New("password does not meet the password policy")
 )
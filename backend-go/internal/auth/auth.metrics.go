@@ -0,0 +1,51 @@
+package auth
+
+import "errors"
+
+// Token kinds and validation results reported to Metrics.TokenValidated.
+const (
+	TokenKindLogin    = "login"
+	TokenKindPersonal = "personal"
+
+	TokenValid   = "valid"
+	TokenExpired = "expired"
+	TokenInvalid = "invalid"
+	TokenError   = "error"
+)
+
+// Metrics counts authentication activity for monitoring.
+type Metrics interface {
+	// AuthEvent counts an audited event, such as a registration or a login,
+	// by its audit action and outcome.
+	AuthEvent(action, outcome string)
+	// TokenValidated counts a token validation by token kind and result.
+	TokenValidated(kind, result string)
+}
+
+// WithMetrics reports authentication activity to m.
+func WithMetrics(m Metrics) Option {
+	return func(s *authService) {
+		s.metrics = m
+	}
+}
+
+// noopMetrics is used when no Metrics are configured.
+type noopMetrics struct{}
+
+func (noopMetrics) AuthEvent(action, outcome string)   {}
+func (noopMetrics) TokenValidated(kind, result string) {}
+
+// countTokenValidation counts a validation of a token of the kind by the
+// error it ended with.
+func (s *authService) countTokenValidation(kind string, err error) {
+	switch {
+	case err == nil:
+		s.metrics.TokenValidated(kind, TokenValid)
+	case errors.Is(err, ErrTokenExpired):
+		s.metrics.TokenValidated(kind, TokenExpired)
+	case errors.Is(err, ErrTokenInvalid):
+		s.metrics.TokenValidated(kind, TokenInvalid)
+	default:
+		s.metrics.TokenValidated(kind, TokenError)
+	}
+}
@@ -1,14 +1,126 @@
 package auth
 
 type User struct {
-	ID        string `bson:"_id,omitempty" json:"id"`
-	Email     string `bson:"email" json:"email"`
-	Username  string `bson:"username" json:"username"`
-	Password  string `bson:"password" json:"-"` // hashed password
-	CreatedAt int64  `bson:"created_at" json:"created_at"`
-	UpdatedAt int64  `bson:"updated_at" json:"updated_at"`
+	ID        string   `bson:"_id,omitempty" json:"id"`
+	Email     string   `bson:"email" json:"email"`
+	Username  string   `bson:"username" json:"username"`
+	Password  string   `bson:"password" json:"-"` // hashed password
+	Roles     []string `bson:"roles" json:"roles"`
+	Verified  bool     `bson:"verified" json:"verified"` // email address confirmed
+	Disabled  bool     `bson:"disabled" json:"disabled"`
+	CreatedAt int64    `bson:"created_at" json:"created_at"`
+	UpdatedAt int64    `bson:"updated_at" json:"updated_at"`
+	DeletedAt int64    `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"` // set when the owner requested deletion
+	PurgeAt   int64    `bson:"purge_at,omitempty" json:"purge_at,omitempty"`     // when the account and its content are removed for good
+	// Identities are the external OpenID Connect accounts linked to this user.
+	Identities []Identity `bson:"identities,omitempty" json:"identities,omitempty"`
+}
+
+// Identity links a user to an account at an external identity provider.
+type Identity struct {
+	Provider string `bson:"provider" json:"provider"`
+	Subject  string `bson:"subject" json:"subject"`
+	LinkedAt int64  `bson:"linked_at" json:"linked_at"`
+}
+
+// ExternalIdentity is the verified identity returned by an IdentityProvider.
+type ExternalIdentity struct {
+	Provider          string
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	PreferredUsername string
+}
+
+// OIDCLoginState is the server-side state of a sign in started with an
+// IdentityProvider, consumed once by the callback.
+type OIDCLoginState struct {
+	State        string `bson:"_id"`
+	Provider     string `bson:"provider"`
+	Nonce        string `bson:"nonce"`
+	CodeVerifier string `bson:"code_verifier"`
+	CreatedAt    int64  `bson:"created_at"`
+	ExpiresAt    int64  `bson:"expires_at"`
+}
+
+// UserFilter narrows down the users returned by AuthRepository.ListUsers.
+// Zero values disable the corresponding filter.
+type UserFilter struct {
+	Search        string // prefix of the username or email, case insensitive
+	Role          string
+	Verified      *bool
+	CreatedAfter  int64 // inclusive, Unix seconds
+	CreatedBefore int64 // exclusive, Unix seconds
+	Cursor        string
+	Limit         int
+}
+
+// UserPage is one page of users, newest first.
+// NextCursor is empty when there are no more users.
+type UserPage struct {
+	Users      []*User `json:"users"`
+	NextCursor string  `json:"next_cursor,omitempty"`
 }
 
 type AuthToken struct {
 	Token string `json:"token"`
 }
+
+// PersonalAccessToken is a named, scoped token a user creates for scripts and
+// integrations. Only the SHA-256 hash of the secret is stored.
+type PersonalAccessToken struct {
+	ID         string   `bson:"_id,omitempty" json:"id"`
+	UserID     string   `bson:"user_id" json:"-"`
+	Name       string   `bson:"name" json:"name"`
+	Scopes     []string `bson:"scopes" json:"scopes"`
+	Hash       string   `bson:"hash" json:"-"`
+	Prefix     string   `bson:"prefix" json:"prefix"` // leading characters of the secret, to recognise it
+	CreatedAt  int64    `bson:"created_at" json:"created_at"`
+	LastUsedAt int64    `bson:"last_used_at,omitempty" json:"last_used_at,omitempty"`
+	ExpiresAt  int64    `bson:"expires_at,omitempty" json:"expires_at,omitempty"` // zero means never
+}
+
+// InviteCode lets someone register while registration is invite-only. Only the
+// SHA-256 hash of the code is stored.
+type InviteCode struct {
+	ID        string `bson:"_id,omitempty" json:"id"`
+	Hash      string `bson:"hash" json:"-"`
+	Prefix    string `bson:"prefix" json:"prefix"` // leading characters of the code, to recognise it
+	CreatedBy string `bson:"created_by" json:"created_by"`
+	MaxUses   int    `bson:"max_uses" json:"max_uses"`
+	Uses      int    `bson:"uses" json:"uses"`
+	CreatedAt int64  `bson:"created_at" json:"created_at"`
+	ExpiresAt int64  `bson:"expires_at" json:"expires_at"`
+}
+
+// Session is a login on one device. Tokens issued by Login carry the session ID,
+// so deleting the session revokes them.
+type Session struct {
+	ID          string `bson:"_id,omitempty" json:"id"`
+	UserID      string `bson:"user_id" json:"-"`
+	DeviceLabel string `bson:"device_label" json:"device_label"`
+	UserAgent   string `bson:"user_agent" json:"user_agent"`
+	IP          string `bson:"ip" json:"ip"`
+	CreatedAt   int64  `bson:"created_at" json:"created_at"`
+	LastSeenAt  int64  `bson:"last_seen_at" json:"last_seen_at"`
+	ExpiresAt   int64  `bson:"expires_at" json:"expires_at"`
+	Current     bool   `bson:"-" json:"current"` // whether the listing request was made with this session
+}
+
+// Claims is the authenticated identity extracted from a validated token.
+type Claims struct {
+	UserID      string   `json:"sub"`
+	Username    string   `json:"usr"`
+	Roles       []string `json:"roles"`
+	Permissions []string `json:"perms"`
+	// SessionID is set when the request was authenticated with a login token.
+	SessionID string `json:"sid,omitempty"`
+	// PersonalTokenID is set when the request was authenticated with a
+	// personal access token instead of a login token.
+	PersonalTokenID string `json:"pat,omitempty"`
+}
+
+// HasPermission reports whether the claims grant the given permission.
+func (c *Claims) HasPermission(permission string) bool {
+	return containsString(c.Permissions, permission)
+}
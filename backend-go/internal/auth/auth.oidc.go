@@ -0,0 +1,241 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// IdentityProvider is an external OpenID Connect provider users can sign in with.
+type IdentityProvider interface {
+	// Name identifies the provider in URLs and linked identities, e.g. "google".
+	Name() string
+	// AuthCodeURL returns the authorization URL for the given state, nonce and
+	// S256 PKCE code challenge.
+	AuthCodeURL(state, nonce, codeChallenge string) string
+	// Exchange redeems an authorization code and returns the identity from the
+	// validated ID token, which must carry the given nonce.
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (*ExternalIdentity, error)
+}
+
+// oidcLoginTTL is how long a started sign in may take to complete.
+const oidcLoginTTL = 10 * time.Minute
+
+// IdentityProviders returns the names of the configured providers, sorted.
+func (s *authService) IdentityProviders() []string {
+	names := make([]string, 0, len(s.providers))
+	for name := range s.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// BeginOIDCLogin stores a fresh state, nonce and PKCE verifier and returns
+// the provider's authorization URL.
+func (s *authService) BeginOIDCLogin(ctx context.Context, providerName string) (string, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return "", ErrUnknownProvider
+	}
+
+	state, err := randomURLString(32)
+	if err != nil {
+		return "", err
+	}
+	nonce, err := randomURLString(32)
+	if err != nil {
+		return "", err
+	}
+	verifier, err := randomURLString(32)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	loginState := &OIDCLoginState{
+		State:        state,
+		Provider:     providerName,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		CreatedAt:    now.Unix(),
+		ExpiresAt:    now.Add(oidcLoginTTL).Unix(),
+	}
+	if err := s.repo.CreateLoginState(ctx, loginState); err != nil {
+		return "", err
+	}
+
+	return provider.AuthCodeURL(state, nonce, pkceChallenge(verifier)), nil
+}
+
+// CompleteOIDCLogin validates the callback, resolves the user and issues a token.
+func (s *authService) CompleteOIDCLogin(ctx context.Context, providerName, state, code string) (string, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return "", ErrUnknownProvider
+	}
+	if state == "" || code == "" {
+		return "", ErrValidationFailed
+	}
+
+	loginState, err := s.repo.ConsumeLoginState(ctx, state)
+	if err != nil {
+		return "", err
+	}
+	if loginState.Provider != providerName || time.Now().Unix() >= loginState.ExpiresAt {
+		return "", ErrInvalidLoginState
+	}
+
+	identity, err := provider.Exchange(ctx, code, loginState.CodeVerifier, loginState.Nonce)
+	if err != nil {
+		s.auditFailure(ctx, AuditEvent{Action: AuditOIDCLogin}, fmt.Errorf("%s: %w", providerName, ErrExternalAuthFailed))
+		return "", fmt.Errorf("%w: %v", ErrExternalAuthFailed, err)
+	}
+	identity.Provider = providerName
+
+	user, err := s.resolveExternalUser(ctx, identity)
+	if err != nil {
+		if isRegistrationError(err) || errors.Is(err, ErrIdentityConflict) {
+			s.auditFailure(ctx, AuditEvent{Username: identity.Email, Action: AuditOIDCLogin}, err)
+		}
+		return "", err
+	}
+	if user.Disabled {
+		s.auditFailure(ctx, AuditEvent{ActorID: user.ID, Username: user.Username, Action: AuditOIDCLogin}, ErrUserDisabled)
+		return "", ErrUserDisabled
+	}
+	if err := s.cancelDeletion(ctx, user); err != nil {
+		s.logger.ErrorContext(ctx, "canceling account deletion failed", "user_id", user.ID, "error", err)
+		return "", errors.New("login failed")
+	}
+
+	token, err := s.startSession(ctx, user, "")
+	if err != nil {
+		s.logger.ErrorContext(ctx, "starting session failed", "user_id", user.ID, "error", err)
+		return "", errors.New("login failed")
+	}
+	s.audit(ctx, AuditEvent{ActorID: user.ID, Username: user.Username, Action: AuditOIDCLogin})
+	return token, nil
+}
+
+// resolveExternalUser returns the user linked to identity. An unlinked identity
+// is linked to the account with the same email if the provider verified it,
+// otherwise a new account is created.
+func (s *authService) resolveExternalUser(ctx context.Context, identity *ExternalIdentity) (*User, error) {
+	user, err := s.repo.FindByIdentity(ctx, identity.Provider, identity.Subject)
+	if err == nil {
+		return user, nil
+	}
+	if !errors.Is(err, ErrUserNotFound) {
+		return nil, err
+	}
+
+	now := time.Now().Unix()
+	link := Identity{Provider: identity.Provider, Subject: identity.Subject, LinkedAt: now}
+
+	if identity.Email != "" {
+		user, err = s.repo.FindByEmail(ctx, identity.Email)
+		switch {
+		case err == nil && identity.EmailVerified:
+			user.Identities = append(user.Identities, link)
+			user.Verified = true
+			user.UpdatedAt = now
+			if err := s.repo.UpdateUser(ctx, user); err != nil {
+				return nil, err
+			}
+			return user, nil
+		case err == nil:
+			// Linking on an unverified email would let anyone who registers
+			// that address at the provider take over the account.
+			return nil, ErrIdentityConflict
+		case !errors.Is(err, ErrUserNotFound):
+			return nil, err
+		}
+	}
+
+	// Sign in with a provider has no way to carry an invite code.
+	if err := s.signup.check(identity.Email, false); err != nil {
+		return nil, err
+	}
+
+	username, err := s.availableUsername(ctx, identity)
+	if err != nil {
+		return nil, err
+	}
+
+	roles := []string{RoleAuthor}
+	if identity.EmailVerified {
+		roles = s.initialRoles(identity.Email)
+	}
+	user = &User{
+		Email:      identity.Email,
+		Username:   username,
+		Roles:      roles,
+		Verified:   identity.EmailVerified,
+		Identities: []Identity{link},
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := s.repo.CreateUser(ctx, user); err != nil {
+		return nil, errors.New("failed to save user")
+	}
+	return user, nil
+}
+
+var usernameDisallowed = regexp.MustCompile(`[^a-z0-9_.-]+`)
+
+// availableUsername derives an unused username from the identity.
+func (s *authService) availableUsername(ctx context.Context, identity *ExternalIdentity) (string, error) {
+	base := identity.PreferredUsername
+	if base == "" {
+		base, _, _ = strings.Cut(identity.Email, "@")
+	}
+	base = usernameDisallowed.ReplaceAllString(strings.ToLower(base), "")
+	if len(base) > 24 {
+		base = base[:24]
+	}
+	if len(base) < 3 {
+		base = "author"
+	}
+
+	for i := 0; i < 20; i++ {
+		candidate := base
+		if i > 0 {
+			suffix, err := randomURLString(3)
+			if err != nil {
+				return "", err
+			}
+			candidate = base + "-" + usernameDisallowed.ReplaceAllString(strings.ToLower(suffix), "")
+		}
+		_, err := s.repo.FindByUsername(ctx, candidate)
+		if errors.Is(err, ErrUserNotFound) {
+			return candidate, nil
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	return "", errors.New("failed to find an available username")
+}
+
+// pkceChallenge returns the S256 code challenge of a PKCE verifier.
+func pkceChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// randomURLString returns n random bytes encoded as unpadded base64url.
+func randomURLString(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,164 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/nbutton23/zxcvbn-go"
+)
+
+// Password policy violations reported in PasswordPolicyError.Reasons.
+const (
+	PasswordTooShort         = "too_short"
+	PasswordTooLong          = "too_long"
+	PasswordTooWeak          = "too_weak"
+	PasswordContainsUsername = "contains_username"
+	PasswordContainsEmail    = "contains_email"
+	PasswordBreached         = "breached"
+)
+
+// PasswordReason is one reason a password was rejected.
+type PasswordReason struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// PasswordPolicyError is returned when a new password violates the password
+// policy. It matches ErrWeakPassword with errors.Is.
+type PasswordPolicyError struct {
+	Reasons []PasswordReason
+}
+
+func (e *PasswordPolicyError) Error() string {
+	return ErrWeakPassword.Error()
+}
+
+func (e *PasswordPolicyError) Unwrap() error {
+	return ErrWeakPassword
+}
+
+// BreachedPasswords looks up leaked password hashes by k-anonymity: only the
+// first five hex characters of a password's SHA-1 hash are revealed to it.
+type BreachedPasswords interface {
+	// Range returns the uppercase hex SHA-1 suffixes of every breached
+	// password whose hash starts with prefix.
+	Range(ctx context.Context, prefix string) ([]string, error)
+}
+
+// WithBreachedPasswords rejects new passwords that appear in the given list.
+func WithBreachedPasswords(list BreachedPasswords) Option {
+	return func(s *authService) {
+		s.passwords.breached = list
+	}
+}
+
+const (
+	defaultPasswordMinLength   = 8
+	defaultPasswordMinStrength = 2
+	// passwordStrengthMaxLength bounds the input of the strength estimator,
+	// whose cost grows quickly with the length of the password.
+	passwordStrengthMaxLength = 64
+	// passwordMaxBytes is the most bcrypt hashes; longer passwords are refused
+	// by bcrypt.GenerateFromPassword.
+	passwordMaxBytes = 72
+)
+
+// passwordPolicy decides which new passwords are accepted.
+type passwordPolicy struct {
+	minLength   int
+	minStrength int // zxcvbn score from 0 (guessable) to 4 (very unguessable)
+	breached    BreachedPasswords
+	logger      *slog.Logger
+}
+
+// loadPasswordPolicy reads PASSWORD_MIN_LENGTH and PASSWORD_MIN_STRENGTH,
+// falling back to the defaults for missing or invalid values.
+func loadPasswordPolicy() passwordPolicy {
+	policy := passwordPolicy{
+		minLength:   defaultPasswordMinLength,
+		minStrength: defaultPasswordMinStrength,
+		logger:      slog.Default(),
+	}
+	if n, err := strconv.Atoi(os.Getenv("PASSWORD_MIN_LENGTH")); err == nil && n > 0 {
+		policy.minLength = n
+	}
+	if n, err := strconv.Atoi(os.Getenv("PASSWORD_MIN_STRENGTH")); err == nil && n >= 0 && n <= 4 {
+		policy.minStrength = n
+	}
+	return policy
+}
+
+// check returns a PasswordPolicyError listing every rule password breaks for
+// the account with username and email, or nil if it is acceptable.
+func (p passwordPolicy) check(ctx context.Context, password, username, email string) error {
+	var reasons []PasswordReason
+	lower := strings.ToLower(password)
+
+	if utf8.RuneCountInString(password) < p.minLength {
+		reasons = append(reasons, PasswordReason{
+			Code:    PasswordTooShort,
+			Message: fmt.Sprintf("must be at least %d characters long", p.minLength),
+		})
+	}
+	if len(password) > passwordMaxBytes {
+		reasons = append(reasons, PasswordReason{
+			Code:    PasswordTooLong,
+			Message: fmt.Sprintf("must be at most %d bytes long", passwordMaxBytes),
+		})
+	}
+	if username != "" && strings.Contains(lower, strings.ToLower(username)) {
+		reasons = append(reasons, PasswordReason{Code: PasswordContainsUsername, Message: "must not contain your username"})
+	}
+	if local, _, _ := strings.Cut(strings.ToLower(email), "@"); len(local) >= 3 && strings.Contains(lower, local) {
+		reasons = append(reasons, PasswordReason{Code: PasswordContainsEmail, Message: "must not contain your email address"})
+	}
+
+	estimated := password
+	if len(estimated) > passwordStrengthMaxLength {
+		estimated = estimated[:passwordStrengthMaxLength]
+	}
+	if zxcvbn.PasswordStrength(estimated, []string{username, email}).Score < p.minStrength {
+		reasons = append(reasons, PasswordReason{
+			Code:    PasswordTooWeak,
+			Message: "is too easy to guess; use a longer passphrase or avoid common words and patterns",
+		})
+	}
+
+	if p.breached != nil && p.isBreached(ctx, password) {
+		reasons = append(reasons, PasswordReason{
+			Code:    PasswordBreached,
+			Message: "has appeared in a data breach and must not be used",
+		})
+	}
+
+	if len(reasons) > 0 {
+		return &PasswordPolicyError{Reasons: reasons}
+	}
+	return nil
+}
+
+// isBreached reports whether password is in the breached password list.
+// The check is best effort: an unavailable list must not block sign ups.
+func (p passwordPolicy) isBreached(ctx context.Context, password string) bool {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	suffixes, err := p.breached.Range(ctx, hash[:5])
+	if err != nil {
+		p.logger.WarnContext(ctx, "checking breached passwords failed", "error", err)
+		return false
+	}
+	for _, suffix := range suffixes {
+		if suffix == hash[5:] {
+			return true
+		}
+	}
+	return false
+}
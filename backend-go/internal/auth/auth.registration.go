@@ -0,0 +1,137 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	v1 "github.com/AldiandyaIrsyad/author-notes/api/v1/auth"
+)
+
+// Registration modes, selected with the REGISTRATION_MODE environment variable.
+const (
+	// RegistrationOpen lets anyone create an account.
+	RegistrationOpen = "open"
+	// RegistrationClosed rejects every new account.
+	RegistrationClosed = "closed"
+	// RegistrationInvite requires an invite code created by an admin.
+	RegistrationInvite = "invite"
+)
+
+const (
+	// defaultInviteTTL is used when an invite is created without an expiry.
+	defaultInviteTTL = 7 * 24 * time.Hour
+	// inviteCodeLength is the number of random bytes in an invite code.
+	inviteCodeLength = 12
+)
+
+// registrationPolicy decides who may create an account. The domain allowlist
+// applies in every mode, including to invited users.
+type registrationPolicy struct {
+	mode           string
+	allowedDomains map[string]bool // empty allows every domain
+}
+
+// loadRegistrationPolicy reads REGISTRATION_MODE and the comma separated
+// REGISTRATION_ALLOWED_DOMAINS. An unknown mode falls back to closed rather
+// than silently opening registration.
+func loadRegistrationPolicy(logger *slog.Logger) registrationPolicy {
+	policy := registrationPolicy{
+		mode:           strings.ToLower(strings.TrimSpace(os.Getenv("REGISTRATION_MODE"))),
+		allowedDomains: make(map[string]bool),
+	}
+	switch policy.mode {
+	case "":
+		policy.mode = RegistrationOpen
+	case RegistrationOpen, RegistrationClosed, RegistrationInvite:
+	default:
+		logger.Warn("unknown REGISTRATION_MODE, registration is closed", "mode", policy.mode)
+		policy.mode = RegistrationClosed
+	}
+	for _, domain := range strings.Split(os.Getenv("REGISTRATION_ALLOWED_DOMAINS"), ",") {
+		domain = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(domain), "@"))
+		if domain != "" {
+			policy.allowedDomains[domain] = true
+		}
+	}
+	return policy
+}
+
+// check reports whether an account with email may be created. The invite code
+// itself is redeemed separately, once the rest of the registration is valid.
+func (p registrationPolicy) check(email string, hasInvite bool) error {
+	switch p.mode {
+	case RegistrationClosed:
+		return ErrRegistrationClosed
+	case RegistrationInvite:
+		if !hasInvite {
+			return ErrInviteRequired
+		}
+	}
+	if len(p.allowedDomains) > 0 {
+		at := strings.LastIndex(email, "@")
+		if at < 0 || !p.allowedDomains[strings.ToLower(email[at+1:])] {
+			return ErrDomainNotAllowed
+		}
+	}
+	return nil
+}
+
+// isRegistrationError reports whether err is a rejection by the registration policy.
+func isRegistrationError(err error) bool {
+	return errors.Is(err, ErrRegistrationClosed) ||
+		errors.Is(err, ErrInviteRequired) ||
+		errors.Is(err, ErrInvalidInvite) ||
+		errors.Is(err, ErrDomainNotAllowed)
+}
+
+// CreateInvite creates an invite code on behalf of an admin.
+func (s *authService) CreateInvite(ctx context.Context, actorID string, req v1.CreateInviteRequest) (string, *InviteCode, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return "", nil, ErrValidationFailed
+	}
+
+	code, err := randomURLString(inviteCodeLength)
+	if err != nil {
+		return "", nil, errors.New("failed to generate invite code")
+	}
+
+	now := time.Now()
+	invite := &InviteCode{
+		Hash:      hashSecret(code),
+		Prefix:    code[:4],
+		CreatedBy: actorID,
+		MaxUses:   1,
+		CreatedAt: now.Unix(),
+		ExpiresAt: now.Add(defaultInviteTTL).Unix(),
+	}
+	if req.MaxUses > 0 {
+		invite.MaxUses = req.MaxUses
+	}
+	if req.ExpiresInDays > 0 {
+		invite.ExpiresAt = now.AddDate(0, 0, req.ExpiresInDays).Unix()
+	}
+
+	if err := s.repo.CreateInvite(ctx, invite); err != nil {
+		return "", nil, err
+	}
+	s.audit(ctx, AuditEvent{ActorID: actorID, Action: AuditInviteCreate})
+	return code, invite, nil
+}
+
+// ListInvites returns all invite codes.
+func (s *authService) ListInvites(ctx context.Context) ([]*InviteCode, error) {
+	return s.repo.ListInvites(ctx)
+}
+
+// RevokeInvite deletes an invite code.
+func (s *authService) RevokeInvite(ctx context.Context, actorID, inviteID string) error {
+	if err := s.repo.DeleteInvite(ctx, inviteID); err != nil {
+		return err
+	}
+	s.audit(ctx, AuditEvent{ActorID: actorID, Action: AuditInviteRevoke})
+	return nil
+}
@@ -8,13 +8,76 @@ import (
 type AuthRepository interface {
 	// CreateUser inserts a new user into the database.
 	CreateUser(ctx context.Context, user *User) error
+	// UpdateUser replaces a stored user with the given one.
+	// Returns ErrUserNotFound if the user does not exist.
+	UpdateUser(ctx context.Context, user *User) error
 	// FindByUsername retrieves a user by their username.
 	// Returns ErrUserNotFound if the user does not exist.
 	FindByUsername(ctx context.Context, username string) (*User, error)
 	// FindByEmailOrUsername retrieves a user by their email or username.
 	// Returns ErrUserNotFound if the user does not exist.
 	FindByEmailOrUsername(ctx context.Context, email, username string) (*User, error)
+	// FindByEmail retrieves a user by their email.
+	// Returns ErrUserNotFound if the user does not exist.
+	FindByEmail(ctx context.Context, email string) (*User, error)
+	// FindByIdentity retrieves the user linked to an external identity.
+	// Returns ErrUserNotFound if no user is linked to it.
+	FindByIdentity(ctx context.Context, provider, subject string) (*User, error)
 	// FindByID retrieves a user by their ID.
 	// Returns ErrUserNotFound if the user does not exist.
 	FindByID(ctx context.Context, id string) (*User, error)
+	// DeleteUser permanently removes a user.
+	// Returns ErrUserNotFound if the user does not exist.
+	DeleteUser(ctx context.Context, id string) error
+	// FindUsersDueForPurge retrieves soft-deleted users whose PurgeAt is at or before now.
+	FindUsersDueForPurge(ctx context.Context, now int64) ([]*User, error)
+	// ListUsers retrieves one page of users matching the filter, newest first.
+	// Returns pagination.ErrInvalidCursor if filter.Cursor was not produced by a previous call.
+	ListUsers(ctx context.Context, filter UserFilter) (*UserPage, error)
+
+	// CreateSession inserts a new session.
+	CreateSession(ctx context.Context, session *Session) error
+	// FindSession retrieves an unexpired session by its ID.
+	// Returns ErrSessionNotFound if it does not exist or has expired.
+	FindSession(ctx context.Context, id string) (*Session, error)
+	// ListSessions retrieves the unexpired sessions of a user, most recently seen first.
+	ListSessions(ctx context.Context, userID string) ([]*Session, error)
+	// TouchSession records when and from where a session was last seen.
+	TouchSession(ctx context.Context, id string, lastSeenAt int64, ip string) error
+	// DeleteSession removes a session of the user.
+	// Returns ErrSessionNotFound if the user has no such session.
+	DeleteSession(ctx context.Context, userID, id string) error
+	// DeleteSessionsByUser removes every session of a user.
+	DeleteSessionsByUser(ctx context.Context, userID string) error
+
+	// CreatePersonalToken inserts a new personal access token.
+	CreatePersonalToken(ctx context.Context, token *PersonalAccessToken) error
+	// FindPersonalTokenByHash retrieves a personal access token by the hash of its secret.
+	// Returns ErrTokenNotFound if no token matches.
+	FindPersonalTokenByHash(ctx context.Context, hash string) (*PersonalAccessToken, error)
+	// ListPersonalTokens retrieves the personal access tokens of a user, newest first.
+	ListPersonalTokens(ctx context.Context, userID string) ([]*PersonalAccessToken, error)
+	// DeletePersonalToken removes a personal access token owned by the user.
+	// Returns ErrTokenNotFound if the user has no such token.
+	DeletePersonalToken(ctx context.Context, userID, id string) error
+	// TouchPersonalToken records when a personal access token was last used.
+	TouchPersonalToken(ctx context.Context, id string, lastUsedAt int64) error
+
+	// CreateInvite inserts a new invite code.
+	CreateInvite(ctx context.Context, invite *InviteCode) error
+	// RedeemInvite uses up one use of the invite code with the given hash in a
+	// single operation. Returns ErrInvalidInvite if no unexpired code with uses
+	// left matches.
+	RedeemInvite(ctx context.Context, hash string, now int64) (*InviteCode, error)
+	// ListInvites retrieves all invite codes, newest first.
+	ListInvites(ctx context.Context) ([]*InviteCode, error)
+	// DeleteInvite removes an invite code.
+	// Returns ErrInviteNotFound if it does not exist.
+	DeleteInvite(ctx context.Context, id string) error
+
+	// CreateLoginState stores the state of a started OpenID Connect sign in.
+	CreateLoginState(ctx context.Context, state *OIDCLoginState) error
+	// ConsumeLoginState retrieves and removes a login state so that it can only be used once.
+	// Returns ErrInvalidLoginState if it does not exist.
+	ConsumeLoginState(ctx context.Context, state string) (*OIDCLoginState, error)
 }
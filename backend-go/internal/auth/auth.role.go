@@ -0,0 +1,52 @@
+package auth
+
+// Roles that can be assigned to a user.
+const (
+	RoleAuthor = "author"
+	RoleAdmin  = "admin"
+)
+
+// Permissions checked by the delivery layer.
+const (
+	PermContentRead  = "content:read"
+	PermContentWrite = "content:write"
+	PermUsersManage  = "users:manage"
+	PermAuditRead    = "audit:read"
+)
+
+// rolePermissions maps each role to the permissions it grants.
+var rolePermissions = map[string][]string{
+	RoleAuthor: {PermContentRead, PermContentWrite},
+	RoleAdmin:  {PermContentRead, PermContentWrite, PermUsersManage, PermAuditRead},
+}
+
+// IsValidRole reports whether role is a known role.
+func IsValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// PermissionsForRoles returns the de-duplicated set of permissions granted by roles.
+// Unknown roles are ignored.
+func PermissionsForRoles(roles []string) []string {
+	seen := make(map[string]bool)
+	permissions := []string{}
+	for _, role := range roles {
+		for _, p := range rolePermissions[role] {
+			if !seen[p] {
+				seen[p] = true
+				permissions = append(permissions, p)
+			}
+		}
+	}
+	return permissions
+}
+
+// userRoles returns the roles of a user, treating accounts created before
+// roles existed as plain authors.
+func userRoles(user *User) []string {
+	if len(user.Roles) == 0 {
+		return []string{RoleAuthor}
+	}
+	return user.Roles
+}
@@ -3,7 +3,9 @@ package auth
 import (
 	"context"
 	"errors"
+	"log/slog"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/go-playground/validator/v10"
@@ -11,34 +13,140 @@ import (
 	"golang.org/x/crypto/bcrypt"
 
 	v1 "github.com/AldiandyaIrsyad/author-notes/api/v1/auth"
+	"github.com/AldiandyaIrsyad/author-notes/internal/pagination"
 )
 
 // AuthService defines the interface for authentication related business logic.
 type AuthService interface {
 	Register(ctx context.Context, req v1.RegisterRequest) (*User, error)
 	Login(ctx context.Context, req v1.LoginRequest) (string, error)
+	// ValidateToken verifies a token issued by Login or a personal access
+	// token and returns its claims.
+	ValidateToken(ctx context.Context, token string) (*Claims, error)
+	// GetProfile returns the account of the user with the given ID.
+	GetProfile(ctx context.Context, userID string) (*User, error)
+	// ListUsers returns one page of user accounts matching the request.
+	ListUsers(ctx context.Context, req v1.ListUsersRequest) (*UserPage, error)
+	// SetUserDisabled disables or re-enables the user with the given ID.
+	// actorID is the admin performing the change, who may not target themselves.
+	SetUserDisabled(ctx context.Context, actorID, userID string, disabled bool) (*User, error)
+
+	// CreateInvite creates an invite code and returns the code, which cannot be
+	// retrieved again.
+	CreateInvite(ctx context.Context, actorID string, req v1.CreateInviteRequest) (string, *InviteCode, error)
+	// ListInvites returns all invite codes.
+	ListInvites(ctx context.Context) ([]*InviteCode, error)
+	// RevokeInvite deletes an invite code so that it can no longer be used.
+	RevokeInvite(ctx context.Context, actorID, inviteID string) error
+
+	// ChangePassword replaces the password of a user after checking the current
+	// one, and logs out every other session.
+	ChangePassword(ctx context.Context, userID, currentSessionID string, req v1.ChangePasswordRequest) error
+	// ListAuditEvents returns one page of authentication audit events.
+	ListAuditEvents(ctx context.Context, req v1.ListAuditEventsRequest) (*AuditPage, error)
+
+	// ListSessions returns the active sessions of a user, marking currentSessionID as current.
+	ListSessions(ctx context.Context, userID, currentSessionID string) ([]*Session, error)
+	// RevokeSession ends a session of a user; its tokens stop working immediately.
+	RevokeSession(ctx context.Context, userID, sessionID string) error
+
+	// CreatePersonalToken creates a personal access token and returns its secret,
+	// which cannot be retrieved again.
+	CreatePersonalToken(ctx context.Context, userID string, req v1.CreatePersonalTokenRequest) (string, *PersonalAccessToken, error)
+	// ListPersonalTokens returns the personal access tokens of a user.
+	ListPersonalTokens(ctx context.Context, userID string) ([]*PersonalAccessToken, error)
+	// RevokePersonalToken deletes a personal access token of a user.
+	RevokePersonalToken(ctx context.Context, userID, tokenID string) error
+
+	// IdentityProviders returns the names of the configured OpenID Connect providers.
+	IdentityProviders() []string
+	// BeginOIDCLogin starts a sign in with an identity provider and returns the
+	// URL the user must be sent to.
+	BeginOIDCLogin(ctx context.Context, provider string) (string, error)
+	// CompleteOIDCLogin finishes a sign in with the state and code the provider
+	// redirected back with, linking or creating the user, and returns a token.
+	CompleteOIDCLogin(ctx context.Context, provider, state, code string) (string, error)
 }
 
 type authService struct {
-	repo      AuthRepository
-	validator *validator.Validate
-	jwtSecret []byte
+	repo        AuthRepository
+	validator   *validator.Validate
+	jwtSecret   []byte
+	adminEmails map[string]bool
+	signup      registrationPolicy
+	passwords   passwordPolicy
+	providers   map[string]IdentityProvider
+	auditLog    AuditLog
+	logger      *slog.Logger
+	metrics     Metrics
+}
+
+// Option configures optional collaborators of the AuthService.
+type Option func(*authService)
+
+// WithLogger sets the logger used to report internal errors. Defaults to slog.Default().
+func WithLogger(logger *slog.Logger) Option {
+	return func(s *authService) {
+		s.logger = logger.With("component", "auth")
+	}
+}
+
+// WithIdentityProviders enables sign in with the given OpenID Connect providers.
+func WithIdentityProviders(providers ...IdentityProvider) Option {
+	return func(s *authService) {
+		for _, p := range providers {
+			s.providers[p.Name()] = p
+		}
+	}
+}
+
+// jwtClaims is the wire representation of Claims inside a signed JWT.
+type jwtClaims struct {
+	Username    string   `json:"usr"`
+	Roles       []string `json:"roles"`
+	Permissions []string `json:"perms"`
+	SessionID   string   `json:"sid"`
+	jwt.RegisteredClaims
 }
 
+// loginTokenTTL is the lifetime of tokens issued by Login and of their sessions.
+const loginTokenTTL = 72 * time.Hour
+
 // NewAuthService creates a new instance of AuthService.
-func NewAuthService(repo AuthRepository) AuthService {
+func NewAuthService(repo AuthRepository, opts ...Option) AuthService {
 	jwtSecret := os.Getenv("JWT_SECRET")
 	if jwtSecret == "" {
 		// Provide a default secret for development, but log a warning.
 		// In production, this should cause a fatal error or use a secure default.
 		jwtSecret = "default_dev_secret_key_please_change"
-		// Consider adding logging here: log.Println("Warning: JWT_SECRET environment variable not set. Using default.")
 	}
-	return &authService{
-		repo:      repo,
-		validator: validator.New(),
-		jwtSecret: []byte(jwtSecret),
+	// ADMIN_EMAILS is a comma separated list of emails that are granted the
+	// admin role when they register, used to bootstrap the first admins.
+	adminEmails := make(map[string]bool)
+	for _, email := range strings.Split(os.Getenv("ADMIN_EMAILS"), ",") {
+		if email = strings.ToLower(strings.TrimSpace(email)); email != "" {
+			adminEmails[email] = true
+		}
+	}
+	s := &authService{
+		repo:        repo,
+		validator:   validator.New(),
+		jwtSecret:   []byte(jwtSecret),
+		adminEmails: adminEmails,
+		passwords:   loadPasswordPolicy(),
+		providers:   make(map[string]IdentityProvider),
+		logger:      slog.Default(),
+		metrics:     noopMetrics{},
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	if os.Getenv("JWT_SECRET") == "" {
+		s.logger.Warn("JWT_SECRET environment variable not set, using the insecure development default")
+	}
+	s.signup = loadRegistrationPolicy(s.logger)
+	s.passwords.logger = s.logger
+	return s
 }
 
 // Register handles user registration.
@@ -48,6 +156,14 @@ func (s *authService) Register(ctx context.Context, req v1.RegisterRequest) (*Us
 		return nil, ErrValidationFailed
 	}
 
+	if err := s.signup.check(req.Email, req.InviteCode != ""); err != nil {
+		s.auditFailure(ctx, AuditEvent{Username: req.Username, Action: AuditRegister}, err)
+		return nil, err
+	}
+	if err := s.passwords.check(ctx, req.Password, req.Username, req.Email); err != nil {
+		return nil, err
+	}
+
 	existingUser, err := s.repo.FindByEmailOrUsername(ctx, req.Email, req.Username)
 	if err != nil && !errors.Is(err, ErrUserNotFound) {
 		// Handle potential database errors
@@ -57,9 +173,19 @@ func (s *authService) Register(ctx context.Context, req v1.RegisterRequest) (*Us
 		return nil, ErrUserAlreadyExists
 	}
 
-	hashedPassword, err := hashPassword(req.Password)
+	// Redeemed last so that a rejected registration does not use up the code.
+	if s.signup.mode == RegistrationInvite {
+		if _, err := s.repo.RedeemInvite(ctx, hashSecret(req.InviteCode), time.Now().Unix()); err != nil {
+			if errors.Is(err, ErrInvalidInvite) {
+				s.auditFailure(ctx, AuditEvent{Username: req.Username, Action: AuditRegister}, err)
+			}
+			return nil, err
+		}
+	}
+
+	hashedPassword, err := hashPassword(ctx, req.Password)
 	if err != nil {
-		// Log error: log.Printf("Error hashing password: %v", err)
+		s.logger.ErrorContext(ctx, "hashing password failed", "error", err)
 		return nil, errors.New("failed to process registration")
 	}
 
@@ -68,15 +194,17 @@ func (s *authService) Register(ctx context.Context, req v1.RegisterRequest) (*Us
 		Email:     req.Email,
 		Username:  req.Username,
 		Password:  hashedPassword,
+		Roles:     s.initialRoles(req.Email),
 		CreatedAt: now,
 		UpdatedAt: now,
 	}
 
 	if err := s.repo.CreateUser(ctx, user); err != nil {
 		// Handle potential database errors (e.g., duplicate key if check failed due to race condition)
-		// Log error: log.Printf("Error creating user: %v", err)
+		s.logger.ErrorContext(ctx, "creating user failed", "error", err)
 		return nil, errors.New("failed to save user") // Generic error
 	}
+	s.audit(ctx, AuditEvent{ActorID: user.ID, Username: user.Username, Action: AuditRegister})
 
 	user.Password = ""
 	return user, nil
@@ -92,45 +220,274 @@ func (s *authService) Login(ctx context.Context, req v1.LoginRequest) (string, e
 	user, err := s.repo.FindByUsername(ctx, req.Username)
 	if err != nil {
 		if errors.Is(err, ErrUserNotFound) {
+			s.auditFailure(ctx, AuditEvent{Username: req.Username, Action: AuditLogin}, ErrUserNotFound)
 			return "", ErrInvalidCredentials
 		}
-		// Log error: log.Printf("Error finding user by username: %v", err)
+		s.logger.ErrorContext(ctx, "finding user by username failed", "error", err)
 		return "", errors.New("login failed") // Generic internal error
 	}
 
-	if !checkPasswordHash(req.Password, user.Password) {
+	if !checkPasswordHash(ctx, req.Password, user.Password) {
+		s.auditFailure(ctx, AuditEvent{ActorID: user.ID, Username: user.Username, Action: AuditLogin}, ErrInvalidCredentials)
 		return "", ErrInvalidCredentials
 	}
 
-	token, err := s.generateJWT(user)
+	// Checked after the password so that the response does not reveal
+	// whether a disabled account exists.
+	if user.Disabled {
+		s.auditFailure(ctx, AuditEvent{ActorID: user.ID, Username: user.Username, Action: AuditLogin}, ErrUserDisabled)
+		return "", ErrUserDisabled
+	}
+
+	if err := s.cancelDeletion(ctx, user); err != nil {
+		return "", errors.New("login failed")
+	}
+
+	token, err := s.startSession(ctx, user, req.DeviceName)
 	if err != nil {
-		// Log error: log.Printf("Error generating JWT token: %v", err)
+		s.logger.ErrorContext(ctx, "starting session failed", "user_id", user.ID, "error", err)
 		return "", errors.New("login failed")
 	}
+	s.audit(ctx, AuditEvent{ActorID: user.ID, Username: user.Username, Action: AuditLogin})
 
 	return token, nil
 }
 
-// hashPassword generates a bcrypt hash of the password.
-func hashPassword(password string) (string, error) {
+// cancelDeletion cancels the pending deletion of a user, if any: logging in
+// during the deletion grace period, by any means, cancels the deletion.
+func (s *authService) cancelDeletion(ctx context.Context, user *User) error {
+	if user.DeletedAt == 0 {
+		return nil
+	}
+	user.DeletedAt = 0
+	user.PurgeAt = 0
+	user.UpdatedAt = time.Now().Unix()
+	return s.repo.UpdateUser(ctx, user)
+}
+
+// ValidateToken verifies a personal access token, recognized by its prefix, or
+// else a token issued by Login.
+func (s *authService) ValidateToken(ctx context.Context, tokenString string) (*Claims, error) {
+	kind, validate := TokenKindLogin, s.validateLoginToken
+	if strings.HasPrefix(tokenString, personalTokenPrefix) {
+		kind, validate = TokenKindPersonal, s.validatePersonalToken
+	}
+	claims, err := validate(ctx, tokenString)
+	s.countTokenValidation(kind, err)
+	return claims, err
+}
+
+// validateLoginToken verifies a JWT issued by Login and its session.
+func (s *authService) validateLoginToken(ctx context.Context, tokenString string) (*Claims, error) {
+	var claims jwtClaims
+	_, err := jwt.ParseWithClaims(tokenString, &claims, func(token *jwt.Token) (interface{}, error) {
+		return s.jwtSecret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, ErrTokenExpired
+		}
+		return nil, ErrTokenInvalid
+	}
+	if claims.Subject == "" || claims.SessionID == "" {
+		return nil, ErrTokenInvalid
+	}
+
+	// The session is looked up on every request so that revoking it takes
+	// effect immediately rather than when the token expires.
+	session, err := s.repo.FindSession(ctx, claims.SessionID)
+	if err != nil {
+		if errors.Is(err, ErrSessionNotFound) {
+			return nil, ErrTokenInvalid
+		}
+		return nil, err
+	}
+	if session.UserID != claims.Subject {
+		return nil, ErrTokenInvalid
+	}
+	s.touchSession(ctx, session)
+
+	return &Claims{
+		UserID:      claims.Subject,
+		Username:    claims.Username,
+		Roles:       claims.Roles,
+		Permissions: claims.Permissions,
+		SessionID:   claims.SessionID,
+	}, nil
+}
+
+// defaultUserPageSize is used by ListUsers when the request does not specify a limit.
+const defaultUserPageSize = 20
+
+// ListUsers returns one page of user accounts without their password hashes.
+func (s *authService) ListUsers(ctx context.Context, req v1.ListUsersRequest) (*UserPage, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, ErrValidationFailed
+	}
+	if req.CreatedAfter != 0 && req.CreatedBefore != 0 && req.CreatedAfter >= req.CreatedBefore {
+		return nil, ErrValidationFailed
+	}
+	if req.Cursor != "" {
+		if _, err := pagination.DecodeCursor(req.Cursor); err != nil {
+			return nil, err
+		}
+	}
+
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultUserPageSize
+	}
+
+	page, err := s.repo.ListUsers(ctx, UserFilter{
+		Search:        strings.TrimSpace(req.Query),
+		Role:          req.Role,
+		Verified:      req.Verified,
+		CreatedAfter:  req.CreatedAfter,
+		CreatedBefore: req.CreatedBefore,
+		Cursor:        req.Cursor,
+		Limit:         limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, user := range page.Users {
+		user.Password = ""
+	}
+	return page, nil
+}
+
+// GetProfile returns a user account without its password hash.
+func (s *authService) GetProfile(ctx context.Context, userID string) (*User, error) {
+	user, err := s.repo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	user.Password = ""
+	return user, nil
+}
+
+// SetUserDisabled disables or re-enables a user account.
+func (s *authService) SetUserDisabled(ctx context.Context, actorID, userID string, disabled bool) (*User, error) {
+	if actorID == userID {
+		return nil, ErrCannotModifySelf
+	}
+
+	user, err := s.repo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Disabled != disabled {
+		user.Disabled = disabled
+		user.UpdatedAt = time.Now().Unix()
+		if err := s.repo.UpdateUser(ctx, user); err != nil {
+			return nil, err
+		}
+	}
+	if disabled {
+		// Log the user out everywhere.
+		if err := s.repo.DeleteSessionsByUser(ctx, user.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	action := AuditUserEnable
+	if disabled {
+		action = AuditUserDisable
+	}
+	s.audit(ctx, AuditEvent{ActorID: actorID, TargetID: user.ID, Username: user.Username, Action: action})
+
+	user.Password = ""
+	return user, nil
+}
+
+// ChangePassword replaces the password of the user.
+func (s *authService) ChangePassword(ctx context.Context, userID, currentSessionID string, req v1.ChangePasswordRequest) error {
+	if err := s.validator.Struct(req); err != nil {
+		return ErrValidationFailed
+	}
+
+	user, err := s.repo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !checkPasswordHash(ctx, req.CurrentPassword, user.Password) {
+		s.auditFailure(ctx, AuditEvent{ActorID: user.ID, Username: user.Username, Action: AuditPasswordChange}, ErrInvalidCredentials)
+		return ErrInvalidCredentials
+	}
+	if err := s.passwords.check(ctx, req.NewPassword, user.Username, user.Email); err != nil {
+		return err
+	}
+
+	hashedPassword, err := hashPassword(ctx, req.NewPassword)
+	if err != nil {
+		return errors.New("failed to change password")
+	}
+	user.Password = hashedPassword
+	user.UpdatedAt = time.Now().Unix()
+	if err := s.repo.UpdateUser(ctx, user); err != nil {
+		return err
+	}
+
+	// Whoever knew the old password may still be logged in elsewhere.
+	sessions, err := s.repo.ListSessions(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	for _, session := range sessions {
+		if session.ID == currentSessionID {
+			continue
+		}
+		if err := s.repo.DeleteSession(ctx, user.ID, session.ID); err != nil && !errors.Is(err, ErrSessionNotFound) {
+			return err
+		}
+	}
+
+	s.audit(ctx, AuditEvent{ActorID: user.ID, Username: user.Username, Action: AuditPasswordChange})
+	return nil
+}
+
+// initialRoles returns the roles of a new account registered with email.
+func (s *authService) initialRoles(email string) []string {
+	roles := []string{RoleAuthor}
+	if s.adminEmails[strings.ToLower(email)] {
+		roles = append(roles, RoleAdmin)
+	}
+	return roles
+}
+
+// hashPassword generates a bcrypt hash of the password. bcrypt is slow by
+// design, so it gets its own span.
+func hashPassword(ctx context.Context, password string) (string, error) {
+	_, span := tracer.Start(ctx, "bcrypt.GenerateFromPassword")
+	defer span.End()
 	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	return string(bytes), err
 }
 
 // checkPasswordHash compares a plain text password with a bcrypt hash.
-func checkPasswordHash(password, hash string) bool {
+func checkPasswordHash(ctx context.Context, password, hash string) bool {
+	_, span := tracer.Start(ctx, "bcrypt.CompareHashAndPassword")
+	defer span.End()
 	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
 	return err == nil
 }
 
-// generateJWT creates a new JWT token for the given user.
-func (s *authService) generateJWT(user *User) (string, error) {
+// generateJWT creates a new JWT token for the given user and session.
+func (s *authService) generateJWT(user *User, session *Session) (string, error) {
+	roles := userRoles(user)
+
 	// Create the claims
-	claims := jwt.MapClaims{
-		"sub": user.ID,                               // Subject (user ID)
-		"usr": user.Username,                         // Username
-		"exp": time.Now().Add(time.Hour * 72).Unix(), // Expiration time
-		"iat": time.Now().Unix(),                     // Issued at
+	claims := jwtClaims{
+		Username:    user.Username,
+		Roles:       roles,
+		Permissions: PermissionsForRoles(roles),
+		SessionID:   session.ID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   user.ID,                                             // Subject (user ID)
+			ExpiresAt: jwt.NewNumericDate(time.Unix(session.ExpiresAt, 0)), // Expiration time
+			IssuedAt:  jwt.NewNumericDate(time.Unix(session.CreatedAt, 0)), // Issued at
+		},
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
@@ -2,16 +2,25 @@ package auth
 
 import (
 	"context"
+	"crypto/sha1"
+	"encoding/hex"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	"golang.org/x/crypto/bcrypt"
 
 	v1 "github.com/AldiandyaIrsyad/author-notes/api/v1/auth"
+	"github.com/AldiandyaIrsyad/author-notes/internal/pagination"
 )
 
 // MockAuthRepository is a mock implementation of AuthRepository
@@ -24,6 +33,11 @@ func (m *MockAuthRepository) CreateUser(ctx context.Context, user *User) error {
 	return args.Error(0)
 }
 
+func (m *MockAuthRepository) UpdateUser(ctx context.Context, user *User) error {
+	args := m.Called(ctx, user)
+	return args.Error(0)
+}
+
 func (m *MockAuthRepository) FindByUsername(ctx context.Context, username string) (*User, error) {
 	args := m.Called(ctx, username)
 	if user := args.Get(0); user != nil {
@@ -40,6 +54,22 @@ func (m *MockAuthRepository) FindByEmailOrUsername(ctx context.Context, email, u
 	return nil, args.Error(1)
 }
 
+func (m *MockAuthRepository) FindByEmail(ctx context.Context, email string) (*User, error) {
+	args := m.Called(ctx, email)
+	if user := args.Get(0); user != nil {
+		return user.(*User), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAuthRepository) FindByIdentity(ctx context.Context, provider, subject string) (*User, error) {
+	args := m.Called(ctx, provider, subject)
+	if user := args.Get(0); user != nil {
+		return user.(*User), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
 func (m *MockAuthRepository) FindByID(ctx context.Context, id string) (*User, error) {
 	args := m.Called(ctx, id)
 	if user := args.Get(0); user != nil {
@@ -48,6 +78,184 @@ func (m *MockAuthRepository) FindByID(ctx context.Context, id string) (*User, er
 	return nil, args.Error(1)
 }
 
+func (m *MockAuthRepository) DeleteUser(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockAuthRepository) FindUsersDueForPurge(ctx context.Context, now int64) ([]*User, error) {
+	args := m.Called(ctx, now)
+	if users := args.Get(0); users != nil {
+		return users.([]*User), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAuthRepository) ListUsers(ctx context.Context, filter UserFilter) (*UserPage, error) {
+	args := m.Called(ctx, filter)
+	if page := args.Get(0); page != nil {
+		return page.(*UserPage), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAuthRepository) CreatePersonalToken(ctx context.Context, token *PersonalAccessToken) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
+func (m *MockAuthRepository) FindPersonalTokenByHash(ctx context.Context, hash string) (*PersonalAccessToken, error) {
+	args := m.Called(ctx, hash)
+	if token := args.Get(0); token != nil {
+		return token.(*PersonalAccessToken), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAuthRepository) ListPersonalTokens(ctx context.Context, userID string) ([]*PersonalAccessToken, error) {
+	args := m.Called(ctx, userID)
+	if tokens := args.Get(0); tokens != nil {
+		return tokens.([]*PersonalAccessToken), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAuthRepository) DeletePersonalToken(ctx context.Context, userID, id string) error {
+	args := m.Called(ctx, userID, id)
+	return args.Error(0)
+}
+
+func (m *MockAuthRepository) TouchPersonalToken(ctx context.Context, id string, lastUsedAt int64) error {
+	args := m.Called(ctx, id, lastUsedAt)
+	return args.Error(0)
+}
+
+func (m *MockAuthRepository) CreateLoginState(ctx context.Context, state *OIDCLoginState) error {
+	args := m.Called(ctx, state)
+	return args.Error(0)
+}
+
+func (m *MockAuthRepository) ConsumeLoginState(ctx context.Context, state string) (*OIDCLoginState, error) {
+	args := m.Called(ctx, state)
+	if loginState := args.Get(0); loginState != nil {
+		return loginState.(*OIDCLoginState), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAuthRepository) CreateSession(ctx context.Context, session *Session) error {
+	args := m.Called(ctx, session)
+	return args.Error(0)
+}
+
+func (m *MockAuthRepository) FindSession(ctx context.Context, id string) (*Session, error) {
+	args := m.Called(ctx, id)
+	if session := args.Get(0); session != nil {
+		return session.(*Session), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAuthRepository) ListSessions(ctx context.Context, userID string) ([]*Session, error) {
+	args := m.Called(ctx, userID)
+	if sessions := args.Get(0); sessions != nil {
+		return sessions.([]*Session), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAuthRepository) TouchSession(ctx context.Context, id string, lastSeenAt int64, ip string) error {
+	args := m.Called(ctx, id, lastSeenAt, ip)
+	return args.Error(0)
+}
+
+func (m *MockAuthRepository) DeleteSession(ctx context.Context, userID, id string) error {
+	args := m.Called(ctx, userID, id)
+	return args.Error(0)
+}
+
+func (m *MockAuthRepository) DeleteSessionsByUser(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+// expectSession expects a session to be created for userID and assigns it
+// sessionID. The returned pointer is filled in once the session is created.
+func expectSession(mockRepo *MockAuthRepository, ctx context.Context, userID, sessionID string) *Session {
+	session := &Session{}
+	mockRepo.On("CreateSession", ctx, mock.MatchedBy(func(s *Session) bool { return s.UserID == userID })).Run(func(args mock.Arguments) {
+		created := args.Get(1).(*Session)
+		created.ID = sessionID
+		*session = *created
+	}).Return(nil).Once()
+	return session
+}
+
+func (m *MockAuthRepository) CreateInvite(ctx context.Context, invite *InviteCode) error {
+	args := m.Called(ctx, invite)
+	return args.Error(0)
+}
+
+func (m *MockAuthRepository) RedeemInvite(ctx context.Context, hash string, now int64) (*InviteCode, error) {
+	args := m.Called(ctx, hash, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*InviteCode), args.Error(1)
+}
+
+func (m *MockAuthRepository) ListInvites(ctx context.Context) ([]*InviteCode, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*InviteCode), args.Error(1)
+}
+
+func (m *MockAuthRepository) DeleteInvite(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// MockAuditLog is a mock implementation of AuditLog
+type MockAuditLog struct {
+	mock.Mock
+}
+
+func (m *MockAuditLog) Append(ctx context.Context, event *AuditEvent) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+func (m *MockAuditLog) List(ctx context.Context, filter AuditFilter) (*AuditPage, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*AuditPage), args.Error(1)
+}
+
+// MockIdentityProvider is a mock implementation of IdentityProvider
+type MockIdentityProvider struct {
+	mock.Mock
+}
+
+func (m *MockIdentityProvider) Name() string {
+	return "test"
+}
+
+func (m *MockIdentityProvider) AuthCodeURL(state, nonce, codeChallenge string) string {
+	return "https://idp.example.com/authorize?state=" + state + "&challenge=" + codeChallenge
+}
+
+func (m *MockIdentityProvider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (*ExternalIdentity, error) {
+	args := m.Called(ctx, code, codeVerifier, nonce)
+	if identity := args.Get(0); identity != nil {
+		return identity.(*ExternalIdentity), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
 // Helper to create a hashed password for tests
 func hashPasswordForTest(t *testing.T, password string) string {
 	t.Helper()
@@ -65,7 +273,7 @@ func TestAuthService_Register(t *testing.T) {
 
 	registerReq := v1.RegisterRequest{
 		Username: "testuser",
-		Password: "password123",
+		Password: "tangerine-vortex-drizzle",
 		Email:    "test@example.com",
 	}
 
@@ -84,9 +292,23 @@ func TestAuthService_Register(t *testing.T) {
 		assert.Empty(t, user.Password, "Password should be empty in the response") // Important security check
 		assert.NotZero(t, user.CreatedAt)
 		assert.NotZero(t, user.UpdatedAt)
+		assert.Equal(t, []string{RoleAuthor}, user.Roles)
 		mockRepo.AssertExpectations(t) // Verify that the expected methods were called
 	})
 
+	t.Run("Success - Bootstrap Admin", func(t *testing.T) {
+		t.Setenv("ADMIN_EMAILS", "other@example.com, Test@Example.com")
+		adminService := NewAuthService(mockRepo)
+		mockRepo.On("FindByEmailOrUsername", ctx, registerReq.Email, registerReq.Username).Return(nil, ErrUserNotFound).Once()
+		mockRepo.On("CreateUser", ctx, mock.AnythingOfType("*auth.User")).Return(nil).Once()
+
+		user, err := adminService.Register(ctx, registerReq)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{RoleAuthor, RoleAdmin}, user.Roles)
+		mockRepo.AssertExpectations(t)
+	})
+
 	t.Run("Validation Failed - Short Username", func(t *testing.T) {
 		invalidReq := v1.RegisterRequest{Username: "us", Password: "password123", Email: "test@example.com"}
 		user, err := service.Register(ctx, invalidReq)
@@ -95,12 +317,22 @@ func TestAuthService_Register(t *testing.T) {
 		assert.ErrorIs(t, err, ErrValidationFailed)
 	})
 
-	t.Run("Validation Failed - Short Password", func(t *testing.T) {
+	t.Run("Password Policy - Short Password", func(t *testing.T) {
 		invalidReq := v1.RegisterRequest{Username: "testuser", Password: "pass", Email: "test@example.com"}
 		user, err := service.Register(ctx, invalidReq)
 		require.Error(t, err)
 		assert.Nil(t, user)
-		assert.ErrorIs(t, err, ErrValidationFailed)
+		assert.ErrorIs(t, err, ErrWeakPassword)
+		assert.Contains(t, passwordReasonCodes(err), PasswordTooShort)
+	})
+
+	t.Run("Password Policy - Longer Than Bcrypt Accepts", func(t *testing.T) {
+		invalidReq := v1.RegisterRequest{Username: "testuser", Password: strings.Repeat("tangerine-vortex-", 4) + "drizz", Email: "test@example.com"}
+		require.Len(t, invalidReq.Password, 73)
+		user, err := service.Register(ctx, invalidReq)
+		assert.Nil(t, user)
+		assert.ErrorIs(t, err, ErrWeakPassword)
+		assert.Equal(t, []string{PasswordTooLong}, passwordReasonCodes(err))
 	})
 
 	t.Run("Validation Failed - Invalid Email", func(t *testing.T) {
@@ -173,14 +405,57 @@ func TestAuthService_Login(t *testing.T) {
 	}
 
 	t.Run("Success", func(t *testing.T) {
-		mockRepo.On("FindByUsername", ctx, loginReq.Username).Return(existingUser, nil).Once()
+		clientCtx := ContextWithClientInfo(ctx, ClientInfo{
+			IP:        "203.0.113.7",
+			UserAgent: "Mozilla/5.0 (X11; Linux x86_64; rv:125.0) Gecko/20100101 Firefox/125.0",
+		})
+		mockRepo.On("FindByUsername", clientCtx, loginReq.Username).Return(existingUser, nil).Once()
+		session := expectSession(mockRepo, clientCtx, existingUser.ID, "session1")
 
-		token, err := service.Login(ctx, loginReq)
+		token, err := service.Login(clientCtx, loginReq)
 
 		require.NoError(t, err)
 		require.NotEmpty(t, token)
-		// Basic check: Add more robust JWT validation if needed (e.g., parse and check claims)
-		assert.Greater(t, len(token), 20)
+		assert.Equal(t, "Firefox on Linux", session.DeviceLabel)
+		assert.Equal(t, "203.0.113.7", session.IP)
+
+		mockRepo.On("FindSession", ctx, "session1").Return(session, nil).Once()
+		claims, err := service.ValidateToken(ctx, token)
+		require.NoError(t, err)
+		assert.Equal(t, "session1", claims.SessionID)
+		assert.Equal(t, existingUser.ID, claims.UserID)
+		assert.Equal(t, existingUser.Username, claims.Username)
+		assert.Equal(t, []string{RoleAuthor}, claims.Roles)
+		assert.True(t, claims.HasPermission(PermContentWrite))
+		assert.False(t, claims.HasPermission(PermUsersManage))
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - Cancels Pending Deletion", func(t *testing.T) {
+		deletedUser := *existingUser
+		deletedUser.DeletedAt = time.Now().Unix()
+		deletedUser.PurgeAt = deletedUser.DeletedAt + 3600
+		mockRepo.On("FindByUsername", ctx, loginReq.Username).Return(&deletedUser, nil).Once()
+		mockRepo.On("UpdateUser", ctx, mock.MatchedBy(func(u *User) bool { return u.DeletedAt == 0 && u.PurgeAt == 0 })).Return(nil).Once()
+		expectSession(mockRepo, ctx, existingUser.ID, "session2")
+
+		token, err := service.Login(ctx, loginReq)
+
+		require.NoError(t, err)
+		assert.NotEmpty(t, token)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Disabled User", func(t *testing.T) {
+		disabledUser := *existingUser
+		disabledUser.Disabled = true
+		mockRepo.On("FindByUsername", ctx, loginReq.Username).Return(&disabledUser, nil).Once()
+
+		token, err := service.Login(ctx, loginReq)
+
+		require.Error(t, err)
+		assert.Empty(t, token)
+		assert.ErrorIs(t, err, ErrUserDisabled)
 		mockRepo.AssertExpectations(t)
 	})
 
@@ -241,3 +516,949 @@ func TestAuthService_Login(t *testing.T) {
 		mockRepo.AssertExpectations(t)
 	})
 }
+
+func TestAuthService_ValidateToken(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test_secret_for_validate")
+	mockRepo := new(MockAuthRepository)
+	service := NewAuthService(mockRepo)
+	ctx := context.Background()
+
+	sign := func(t *testing.T, claims jwt.Claims, secret string) string {
+		t.Helper()
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+		require.NoError(t, err)
+		return token
+	}
+
+	t.Run("Expired", func(t *testing.T) {
+		token := sign(t, jwt.RegisteredClaims{
+			Subject:   "user123",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Minute)),
+		}, "test_secret_for_validate")
+
+		claims, err := service.ValidateToken(ctx, token)
+
+		assert.Nil(t, claims)
+		assert.ErrorIs(t, err, ErrTokenExpired)
+	})
+
+	t.Run("Wrong Secret", func(t *testing.T) {
+		token := sign(t, jwt.RegisteredClaims{
+			Subject:   "user123",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		}, "another_secret")
+
+		claims, err := service.ValidateToken(ctx, token)
+
+		assert.Nil(t, claims)
+		assert.ErrorIs(t, err, ErrTokenInvalid)
+	})
+
+	t.Run("Revoked Session", func(t *testing.T) {
+		token := sign(t, jwtClaims{
+			SessionID: "revoked",
+			RegisteredClaims: jwt.RegisteredClaims{
+				Subject:   "user123",
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+			},
+		}, "test_secret_for_validate")
+		mockRepo.On("FindSession", ctx, "revoked").Return(nil, ErrSessionNotFound).Once()
+
+		claims, err := service.ValidateToken(ctx, token)
+
+		assert.Nil(t, claims)
+		assert.ErrorIs(t, err, ErrTokenInvalid)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Session Of Another User", func(t *testing.T) {
+		token := sign(t, jwtClaims{
+			SessionID: "session1",
+			RegisteredClaims: jwt.RegisteredClaims{
+				Subject:   "user123",
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+			},
+		}, "test_secret_for_validate")
+		mockRepo.On("FindSession", ctx, "session1").Return(&Session{ID: "session1", UserID: "user456"}, nil).Once()
+
+		claims, err := service.ValidateToken(ctx, token)
+
+		assert.Nil(t, claims)
+		assert.ErrorIs(t, err, ErrTokenInvalid)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Missing Session", func(t *testing.T) {
+		token := sign(t, jwt.RegisteredClaims{
+			Subject:   "user123",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		}, "test_secret_for_validate")
+
+		claims, err := service.ValidateToken(ctx, token)
+
+		assert.Nil(t, claims)
+		assert.ErrorIs(t, err, ErrTokenInvalid)
+	})
+
+	t.Run("Garbage", func(t *testing.T) {
+		claims, err := service.ValidateToken(ctx, "not-a-token")
+
+		assert.Nil(t, claims)
+		assert.ErrorIs(t, err, ErrTokenInvalid)
+	})
+}
+
+func TestAuthService_GetProfile(t *testing.T) {
+	mockRepo := new(MockAuthRepository)
+	service := NewAuthService(mockRepo)
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		user := &User{ID: "user123", Username: "testuser", Password: "hash"}
+		mockRepo.On("FindByID", ctx, "user123").Return(user, nil).Once()
+
+		profile, err := service.GetProfile(ctx, "user123")
+
+		require.NoError(t, err)
+		assert.Equal(t, "testuser", profile.Username)
+		assert.Empty(t, profile.Password)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("User Not Found", func(t *testing.T) {
+		mockRepo.On("FindByID", ctx, "missing").Return(nil, ErrUserNotFound).Once()
+
+		profile, err := service.GetProfile(ctx, "missing")
+
+		assert.Nil(t, profile)
+		assert.ErrorIs(t, err, ErrUserNotFound)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestAuthService_SetUserDisabled(t *testing.T) {
+	mockRepo := new(MockAuthRepository)
+	service := NewAuthService(mockRepo)
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		user := &User{ID: "user123", Username: "testuser", Password: "hash"}
+		mockRepo.On("FindByID", ctx, "user123").Return(user, nil).Once()
+		mockRepo.On("UpdateUser", ctx, mock.MatchedBy(func(u *User) bool { return u.Disabled })).Return(nil).Once()
+		mockRepo.On("DeleteSessionsByUser", ctx, "user123").Return(nil).Once()
+
+		updated, err := service.SetUserDisabled(ctx, "admin1", "user123", true)
+
+		require.NoError(t, err)
+		assert.True(t, updated.Disabled)
+		assert.Empty(t, updated.Password)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Cannot Disable Self", func(t *testing.T) {
+		updated, err := service.SetUserDisabled(ctx, "admin1", "admin1", true)
+
+		assert.Nil(t, updated)
+		assert.ErrorIs(t, err, ErrCannotModifySelf)
+	})
+
+	t.Run("User Not Found", func(t *testing.T) {
+		mockRepo.On("FindByID", ctx, "missing").Return(nil, ErrUserNotFound).Once()
+
+		updated, err := service.SetUserDisabled(ctx, "admin1", "missing", false)
+
+		assert.Nil(t, updated)
+		assert.ErrorIs(t, err, ErrUserNotFound)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestAuthService_ListUsers(t *testing.T) {
+	mockRepo := new(MockAuthRepository)
+	service := NewAuthService(mockRepo)
+	ctx := context.Background()
+
+	t.Run("Success - Defaults", func(t *testing.T) {
+		verified := true
+		cursor := pagination.Cursor{CreatedAt: 1700000000, ID: "user9"}.Encode()
+		expectedFilter := UserFilter{Search: "ali", Role: RoleAdmin, Verified: &verified, Cursor: cursor, Limit: defaultUserPageSize}
+		page := &UserPage{Users: []*User{{ID: "user1", Password: "hash"}}, NextCursor: "next"}
+		mockRepo.On("ListUsers", ctx, expectedFilter).Return(page, nil).Once()
+
+		result, err := service.ListUsers(ctx, v1.ListUsersRequest{Query: " ali ", Role: RoleAdmin, Verified: &verified, Cursor: cursor})
+
+		require.NoError(t, err)
+		require.Len(t, result.Users, 1)
+		assert.Empty(t, result.Users[0].Password)
+		assert.Equal(t, "next", result.NextCursor)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Validation Failed - Unknown Role", func(t *testing.T) {
+		result, err := service.ListUsers(ctx, v1.ListUsersRequest{Role: "superuser"})
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, ErrValidationFailed)
+	})
+
+	t.Run("Validation Failed - Limit Too Large", func(t *testing.T) {
+		result, err := service.ListUsers(ctx, v1.ListUsersRequest{Limit: 1000})
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, ErrValidationFailed)
+	})
+
+	t.Run("Validation Failed - Empty Date Range", func(t *testing.T) {
+		result, err := service.ListUsers(ctx, v1.ListUsersRequest{CreatedAfter: 200, CreatedBefore: 100})
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, ErrValidationFailed)
+	})
+
+	t.Run("Invalid Cursor", func(t *testing.T) {
+		result, err := service.ListUsers(ctx, v1.ListUsersRequest{Cursor: "%%%"})
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, pagination.ErrInvalidCursor)
+	})
+}
+
+func TestAuthService_PersonalTokens(t *testing.T) {
+	mockRepo := new(MockAuthRepository)
+	service := NewAuthService(mockRepo)
+	ctx := context.Background()
+	author := &User{ID: "user123", Username: "testuser", Roles: []string{RoleAuthor}}
+
+	t.Run("Create And Validate", func(t *testing.T) {
+		var stored *PersonalAccessToken
+		mockRepo.On("FindByID", ctx, author.ID).Return(author, nil).Twice()
+		mockRepo.On("CreatePersonalToken", ctx, mock.AnythingOfType("*auth.PersonalAccessToken")).Run(func(args mock.Arguments) {
+			stored = args.Get(1).(*PersonalAccessToken)
+			stored.ID = "pat1"
+		}).Return(nil).Once()
+
+		secret, token, err := service.CreatePersonalToken(ctx, author.ID, v1.CreatePersonalTokenRequest{
+			Name:          "import script",
+			Scopes:        []string{PermContentRead, PermContentRead},
+			ExpiresInDays: 30,
+		})
+
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(secret, personalTokenPrefix))
+		assert.Equal(t, []string{PermContentRead}, token.Scopes)
+		assert.Equal(t, hashSecret(secret), stored.Hash, "only the hash must be stored")
+		assert.True(t, strings.HasPrefix(secret, token.Prefix))
+		assert.Greater(t, token.ExpiresAt, time.Now().Unix())
+
+		mockRepo.On("FindPersonalTokenByHash", ctx, stored.Hash).Return(stored, nil).Once()
+		mockRepo.On("TouchPersonalToken", ctx, "pat1", mock.AnythingOfType("int64")).Return(nil).Once()
+
+		claims, err := service.ValidateToken(ctx, secret)
+
+		require.NoError(t, err)
+		assert.Equal(t, author.ID, claims.UserID)
+		assert.Equal(t, "pat1", claims.PersonalTokenID)
+		assert.Equal(t, []string{PermContentRead}, claims.Permissions)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Create - Scope Not Granted", func(t *testing.T) {
+		mockRepo.On("FindByID", ctx, author.ID).Return(author, nil).Once()
+
+		_, _, err := service.CreatePersonalToken(ctx, author.ID, v1.CreatePersonalTokenRequest{
+			Name:   "escalate",
+			Scopes: []string{PermUsersManage},
+		})
+
+		assert.ErrorIs(t, err, ErrInvalidScope)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Validate - Unknown Token", func(t *testing.T) {
+		mockRepo.On("FindPersonalTokenByHash", ctx, mock.AnythingOfType("string")).Return(nil, ErrTokenNotFound).Once()
+
+		claims, err := service.ValidateToken(ctx, personalTokenPrefix+"unknown")
+
+		assert.Nil(t, claims)
+		assert.ErrorIs(t, err, ErrTokenInvalid)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Validate - Expired Token", func(t *testing.T) {
+		expired := &PersonalAccessToken{ID: "pat2", UserID: author.ID, ExpiresAt: time.Now().Add(-time.Hour).Unix()}
+		mockRepo.On("FindPersonalTokenByHash", ctx, mock.AnythingOfType("string")).Return(expired, nil).Once()
+
+		claims, err := service.ValidateToken(ctx, personalTokenPrefix+"expired")
+
+		assert.Nil(t, claims)
+		assert.ErrorIs(t, err, ErrTokenExpired)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Validate - Disabled Owner", func(t *testing.T) {
+		disabled := &User{ID: "user456", Disabled: true}
+		token := &PersonalAccessToken{ID: "pat3", UserID: disabled.ID, Scopes: []string{PermContentRead}}
+		mockRepo.On("FindPersonalTokenByHash", ctx, mock.AnythingOfType("string")).Return(token, nil).Once()
+		mockRepo.On("FindByID", ctx, disabled.ID).Return(disabled, nil).Once()
+
+		claims, err := service.ValidateToken(ctx, personalTokenPrefix+"disabled")
+
+		assert.Nil(t, claims)
+		assert.ErrorIs(t, err, ErrTokenInvalid)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestAuthService_OIDCLogin(t *testing.T) {
+	mockRepo := new(MockAuthRepository)
+	provider := new(MockIdentityProvider)
+	service := NewAuthService(mockRepo, WithIdentityProviders(provider))
+	ctx := context.Background()
+
+	loginState := func() *OIDCLoginState {
+		return &OIDCLoginState{
+			State:        "state1",
+			Provider:     "test",
+			Nonce:        "nonce1",
+			CodeVerifier: "verifier1",
+			ExpiresAt:    time.Now().Add(time.Minute).Unix(),
+		}
+	}
+
+	t.Run("Begin", func(t *testing.T) {
+		var stored *OIDCLoginState
+		mockRepo.On("CreateLoginState", ctx, mock.AnythingOfType("*auth.OIDCLoginState")).Run(func(args mock.Arguments) {
+			stored = args.Get(1).(*OIDCLoginState)
+		}).Return(nil).Once()
+
+		url, err := service.BeginOIDCLogin(ctx, "test")
+
+		require.NoError(t, err)
+		assert.Equal(t, "test", stored.Provider)
+		assert.NotEmpty(t, stored.Nonce)
+		assert.Contains(t, url, "state="+stored.State)
+		assert.Contains(t, url, "challenge="+pkceChallenge(stored.CodeVerifier))
+		assert.Equal(t, []string{"test"}, service.IdentityProviders())
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Begin - Unknown Provider", func(t *testing.T) {
+		_, err := service.BeginOIDCLogin(ctx, "nope")
+		assert.ErrorIs(t, err, ErrUnknownProvider)
+	})
+
+	t.Run("Complete - Linked Identity", func(t *testing.T) {
+		linked := &User{ID: "user123", Username: "writer"}
+		mockRepo.On("ConsumeLoginState", ctx, "state1").Return(loginState(), nil).Once()
+		provider.On("Exchange", ctx, "code1", "verifier1", "nonce1").Return(&ExternalIdentity{Subject: "ext1"}, nil).Once()
+		mockRepo.On("FindByIdentity", ctx, "test", "ext1").Return(linked, nil).Once()
+		session := expectSession(mockRepo, ctx, "user123", "session1")
+
+		token, err := service.CompleteOIDCLogin(ctx, "test", "state1", "code1")
+
+		require.NoError(t, err)
+		mockRepo.On("FindSession", ctx, "session1").Return(session, nil).Once()
+		claims, err := service.ValidateToken(ctx, token)
+		require.NoError(t, err)
+		assert.Equal(t, "user123", claims.UserID)
+		mockRepo.AssertExpectations(t)
+		provider.AssertExpectations(t)
+	})
+
+	t.Run("Complete - Cancels Pending Deletion", func(t *testing.T) {
+		now := time.Now().Unix()
+		deleted := &User{ID: "user123", Username: "writer", DeletedAt: now, PurgeAt: now + 3600}
+		mockRepo.On("ConsumeLoginState", ctx, "state1").Return(loginState(), nil).Once()
+		provider.On("Exchange", ctx, "code1", "verifier1", "nonce1").Return(&ExternalIdentity{Subject: "ext1"}, nil).Once()
+		mockRepo.On("FindByIdentity", ctx, "test", "ext1").Return(deleted, nil).Once()
+		mockRepo.On("UpdateUser", ctx, mock.MatchedBy(func(u *User) bool { return u.DeletedAt == 0 && u.PurgeAt == 0 })).Return(nil).Once()
+		expectSession(mockRepo, ctx, "user123", "session3")
+
+		token, err := service.CompleteOIDCLogin(ctx, "test", "state1", "code1")
+
+		require.NoError(t, err)
+		assert.NotEmpty(t, token)
+		mockRepo.AssertExpectations(t)
+		provider.AssertExpectations(t)
+	})
+
+	t.Run("Complete - Links Verified Email", func(t *testing.T) {
+		existing := &User{ID: "user123", Username: "writer", Email: "writer@example.com"}
+		mockRepo.On("ConsumeLoginState", ctx, "state1").Return(loginState(), nil).Once()
+		provider.On("Exchange", ctx, "code1", "verifier1", "nonce1").Return(&ExternalIdentity{
+			Subject: "ext1", Email: "writer@example.com", EmailVerified: true,
+		}, nil).Once()
+		mockRepo.On("FindByIdentity", ctx, "test", "ext1").Return(nil, ErrUserNotFound).Once()
+		mockRepo.On("FindByEmail", ctx, "writer@example.com").Return(existing, nil).Once()
+		mockRepo.On("UpdateUser", ctx, mock.MatchedBy(func(u *User) bool {
+			return u.Verified && len(u.Identities) == 1 && u.Identities[0].Subject == "ext1"
+		})).Return(nil).Once()
+		expectSession(mockRepo, ctx, "user123", "session2")
+
+		token, err := service.CompleteOIDCLogin(ctx, "test", "state1", "code1")
+
+		require.NoError(t, err)
+		assert.NotEmpty(t, token)
+		mockRepo.AssertExpectations(t)
+		provider.AssertExpectations(t)
+	})
+
+	t.Run("Complete - Unverified Email Conflict", func(t *testing.T) {
+		mockRepo.On("ConsumeLoginState", ctx, "state1").Return(loginState(), nil).Once()
+		provider.On("Exchange", ctx, "code1", "verifier1", "nonce1").Return(&ExternalIdentity{
+			Subject: "ext1", Email: "writer@example.com", EmailVerified: false,
+		}, nil).Once()
+		mockRepo.On("FindByIdentity", ctx, "test", "ext1").Return(nil, ErrUserNotFound).Once()
+		mockRepo.On("FindByEmail", ctx, "writer@example.com").Return(&User{ID: "user123"}, nil).Once()
+
+		token, err := service.CompleteOIDCLogin(ctx, "test", "state1", "code1")
+
+		assert.Empty(t, token)
+		assert.ErrorIs(t, err, ErrIdentityConflict)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Complete - Creates Account", func(t *testing.T) {
+		mockRepo.On("ConsumeLoginState", ctx, "state1").Return(loginState(), nil).Once()
+		provider.On("Exchange", ctx, "code1", "verifier1", "nonce1").Return(&ExternalIdentity{
+			Subject: "ext2", Email: "New.Writer@example.com", EmailVerified: true,
+		}, nil).Once()
+		mockRepo.On("FindByIdentity", ctx, "test", "ext2").Return(nil, ErrUserNotFound).Once()
+		mockRepo.On("FindByEmail", ctx, "New.Writer@example.com").Return(nil, ErrUserNotFound).Once()
+		mockRepo.On("FindByUsername", ctx, "new.writer").Return(&User{ID: "taken"}, nil).Once()
+		mockRepo.On("FindByUsername", ctx, mock.AnythingOfType("string")).Return(nil, ErrUserNotFound).Once()
+		mockRepo.On("CreateUser", ctx, mock.MatchedBy(func(u *User) bool {
+			return strings.HasPrefix(u.Username, "new.writer-") && u.Password == "" && u.Verified &&
+				len(u.Identities) == 1 && u.Identities[0].Provider == "test"
+		})).Run(func(args mock.Arguments) {
+			args.Get(1).(*User).ID = "user789"
+		}).Return(nil).Once()
+		expectSession(mockRepo, ctx, "user789", "session3")
+
+		token, err := service.CompleteOIDCLogin(ctx, "test", "state1", "code1")
+
+		require.NoError(t, err)
+		assert.NotEmpty(t, token)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Complete - Replayed State", func(t *testing.T) {
+		mockRepo.On("ConsumeLoginState", ctx, "state1").Return(nil, ErrInvalidLoginState).Once()
+
+		_, err := service.CompleteOIDCLogin(ctx, "test", "state1", "code1")
+
+		assert.ErrorIs(t, err, ErrInvalidLoginState)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Complete - Expired State", func(t *testing.T) {
+		expired := loginState()
+		expired.ExpiresAt = time.Now().Add(-time.Minute).Unix()
+		mockRepo.On("ConsumeLoginState", ctx, "state1").Return(expired, nil).Once()
+
+		_, err := service.CompleteOIDCLogin(ctx, "test", "state1", "code1")
+
+		assert.ErrorIs(t, err, ErrInvalidLoginState)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Complete - Exchange Fails", func(t *testing.T) {
+		mockRepo.On("ConsumeLoginState", ctx, "state1").Return(loginState(), nil).Once()
+		provider.On("Exchange", ctx, "code1", "verifier1", "nonce1").Return(nil, errors.New("nonce mismatch")).Once()
+
+		_, err := service.CompleteOIDCLogin(ctx, "test", "state1", "code1")
+
+		assert.ErrorIs(t, err, ErrExternalAuthFailed)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestAuthService_Sessions(t *testing.T) {
+	mockRepo := new(MockAuthRepository)
+	service := NewAuthService(mockRepo)
+	ctx := context.Background()
+
+	t.Run("List Marks Current", func(t *testing.T) {
+		mockRepo.On("ListSessions", ctx, "user123").Return([]*Session{{ID: "s1"}, {ID: "s2"}}, nil).Once()
+
+		sessions, err := service.ListSessions(ctx, "user123", "s2")
+
+		require.NoError(t, err)
+		assert.False(t, sessions[0].Current)
+		assert.True(t, sessions[1].Current)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Revoke", func(t *testing.T) {
+		mockRepo.On("DeleteSession", ctx, "user123", "s1").Return(ErrSessionNotFound).Once()
+
+		err := service.RevokeSession(ctx, "user123", "s1")
+
+		assert.ErrorIs(t, err, ErrSessionNotFound)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestDeviceLabel(t *testing.T) {
+	tests := map[string]string{
+		"": "Unknown device",
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36 Edg/124.0.0.0": "Edge on Windows",
+		"Mozilla/5.0 (Macintosh; Intel Mac OS X 14_4) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Safari/605.1.15":            "Safari on macOS",
+		"Mozilla/5.0 (iPhone; CPU iPhone OS 17_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Mobile/15E148 Safari/604.1":    "Safari on iOS",
+		"curl/8.5.0": "curl",
+	}
+	for userAgent, want := range tests {
+		assert.Equal(t, want, deviceLabel(userAgent), userAgent)
+	}
+}
+
+func TestAuthService_Audit(t *testing.T) {
+	mockRepo := new(MockAuthRepository)
+	auditLog := new(MockAuditLog)
+	service := NewAuthService(mockRepo, WithAuditLog(auditLog))
+	ctx := ContextWithClientInfo(context.Background(), ClientInfo{IP: "198.51.100.4", UserAgent: "curl/8.5.0"})
+
+	t.Run("Failed Login Of Unknown User", func(t *testing.T) {
+		mockRepo.On("FindByUsername", ctx, "ghost").Return(nil, ErrUserNotFound).Once()
+		auditLog.On("Append", ctx, mock.MatchedBy(func(e *AuditEvent) bool {
+			return e.Action == AuditLogin && e.Outcome == AuditFailure && e.Username == "ghost" &&
+				e.ActorID == "" && e.Reason == ErrUserNotFound.Error() && e.IP == "198.51.100.4" && e.CreatedAt != 0
+		})).Return(nil).Once()
+
+		_, err := service.Login(ctx, v1.LoginRequest{Username: "ghost", Password: "password123"})
+
+		assert.ErrorIs(t, err, ErrInvalidCredentials)
+		mockRepo.AssertExpectations(t)
+		auditLog.AssertExpectations(t)
+	})
+
+	t.Run("Audit Failure Does Not Block Login", func(t *testing.T) {
+		user := &User{ID: "user123", Username: "testuser", Password: hashPasswordForTest(t, "password123")}
+		mockRepo.On("FindByUsername", ctx, "testuser").Return(user, nil).Once()
+		expectSession(mockRepo, ctx, user.ID, "session1")
+		auditLog.On("Append", ctx, mock.MatchedBy(func(e *AuditEvent) bool {
+			return e.Action == AuditLogin && e.Outcome == AuditSuccess && e.ActorID == user.ID
+		})).Return(errors.New("db down")).Once()
+
+		token, err := service.Login(ctx, v1.LoginRequest{Username: "testuser", Password: "password123"})
+
+		require.NoError(t, err)
+		assert.NotEmpty(t, token)
+		mockRepo.AssertExpectations(t)
+		auditLog.AssertExpectations(t)
+	})
+
+	t.Run("Disable Records Actor And Target", func(t *testing.T) {
+		user := &User{ID: "user456", Username: "target"}
+		mockRepo.On("FindByID", ctx, user.ID).Return(user, nil).Once()
+		mockRepo.On("UpdateUser", ctx, user).Return(nil).Once()
+		mockRepo.On("DeleteSessionsByUser", ctx, user.ID).Return(nil).Once()
+		auditLog.On("Append", ctx, mock.MatchedBy(func(e *AuditEvent) bool {
+			return e.Action == AuditUserDisable && e.ActorID == "admin1" && e.TargetID == user.ID
+		})).Return(nil).Once()
+
+		_, err := service.SetUserDisabled(ctx, "admin1", user.ID, true)
+
+		require.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+		auditLog.AssertExpectations(t)
+	})
+
+	t.Run("List Applies Default Limit", func(t *testing.T) {
+		page := &AuditPage{Events: []*AuditEvent{{ID: "e1"}}}
+		auditLog.On("List", ctx, AuditFilter{UserID: "user123", Outcome: AuditFailure, Limit: defaultAuditPageSize}).Return(page, nil).Once()
+
+		result, err := service.ListAuditEvents(ctx, v1.ListAuditEventsRequest{UserID: "user123", Outcome: AuditFailure})
+
+		require.NoError(t, err)
+		assert.Equal(t, page, result)
+		auditLog.AssertExpectations(t)
+	})
+
+	t.Run("List Rejects Invalid Filter", func(t *testing.T) {
+		_, err := service.ListAuditEvents(ctx, v1.ListAuditEventsRequest{Outcome: "maybe"})
+		assert.ErrorIs(t, err, ErrValidationFailed)
+
+		_, err = service.ListAuditEvents(ctx, v1.ListAuditEventsRequest{Cursor: "not-a-cursor"})
+		assert.ErrorIs(t, err, pagination.ErrInvalidCursor)
+	})
+}
+
+func TestAuthService_ChangePassword(t *testing.T) {
+	mockRepo := new(MockAuthRepository)
+	service := NewAuthService(mockRepo)
+	ctx := context.Background()
+
+	newUser := func() *User {
+		return &User{ID: "user123", Username: "testuser", Password: hashPasswordForTest(t, "password123")}
+	}
+
+	t.Run("Success - Revokes Other Sessions", func(t *testing.T) {
+		user := newUser()
+		mockRepo.On("FindByID", ctx, user.ID).Return(user, nil).Once()
+		mockRepo.On("UpdateUser", ctx, mock.MatchedBy(func(u *User) bool {
+			return bcrypt.CompareHashAndPassword([]byte(u.Password), []byte("tangerine-vortex-drizzle")) == nil
+		})).Return(nil).Once()
+		mockRepo.On("ListSessions", ctx, user.ID).Return([]*Session{{ID: "current"}, {ID: "other"}}, nil).Once()
+		mockRepo.On("DeleteSession", ctx, user.ID, "other").Return(nil).Once()
+
+		err := service.ChangePassword(ctx, user.ID, "current", v1.ChangePasswordRequest{
+			CurrentPassword: "password123",
+			NewPassword:     "tangerine-vortex-drizzle",
+		})
+
+		require.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+		mockRepo.AssertNotCalled(t, "DeleteSession", ctx, user.ID, "current")
+	})
+
+	t.Run("Wrong Current Password", func(t *testing.T) {
+		user := newUser()
+		mockRepo.On("FindByID", ctx, user.ID).Return(user, nil).Once()
+
+		err := service.ChangePassword(ctx, user.ID, "current", v1.ChangePasswordRequest{
+			CurrentPassword: "wrongpassword",
+			NewPassword:     "tangerine-vortex-drizzle",
+		})
+
+		assert.ErrorIs(t, err, ErrInvalidCredentials)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Validation Failure", func(t *testing.T) {
+		err := service.ChangePassword(ctx, "user123", "current", v1.ChangePasswordRequest{
+			CurrentPassword: "password123",
+		})
+
+		assert.ErrorIs(t, err, ErrValidationFailed)
+	})
+
+	t.Run("Password Policy", func(t *testing.T) {
+		user := newUser()
+		mockRepo.On("FindByID", ctx, user.ID).Return(user, nil).Once()
+
+		err := service.ChangePassword(ctx, user.ID, "current", v1.ChangePasswordRequest{
+			CurrentPassword: "password123",
+			NewPassword:     "testuser2024",
+		})
+
+		assert.ErrorIs(t, err, ErrWeakPassword)
+		assert.Contains(t, passwordReasonCodes(err), PasswordContainsUsername)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestAuthService_RegistrationPolicy(t *testing.T) {
+	ctx := context.Background()
+	registerReq := v1.RegisterRequest{
+		Username: "newuser",
+		Password: "tangerine-vortex-drizzle",
+		Email:    "new@example.com",
+	}
+
+	t.Run("Closed", func(t *testing.T) {
+		t.Setenv("REGISTRATION_MODE", "closed")
+		mockRepo := new(MockAuthRepository)
+		service := NewAuthService(mockRepo)
+
+		_, err := service.Register(ctx, registerReq)
+
+		assert.ErrorIs(t, err, ErrRegistrationClosed)
+		mockRepo.AssertNotCalled(t, "CreateUser", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Unknown Mode Is Closed", func(t *testing.T) {
+		t.Setenv("REGISTRATION_MODE", "opne")
+		service := NewAuthService(new(MockAuthRepository))
+
+		_, err := service.Register(ctx, registerReq)
+
+		assert.ErrorIs(t, err, ErrRegistrationClosed)
+	})
+
+	t.Run("Domain Not Allowed", func(t *testing.T) {
+		t.Setenv("REGISTRATION_ALLOWED_DOMAINS", "corp.example, @Partner.example")
+		service := NewAuthService(new(MockAuthRepository))
+
+		_, err := service.Register(ctx, registerReq)
+
+		assert.ErrorIs(t, err, ErrDomainNotAllowed)
+	})
+
+	t.Run("Domain Allowed", func(t *testing.T) {
+		t.Setenv("REGISTRATION_ALLOWED_DOMAINS", "corp.example, @Partner.example")
+		mockRepo := new(MockAuthRepository)
+		service := NewAuthService(mockRepo)
+		req := registerReq
+		req.Email = "new@partner.EXAMPLE"
+		mockRepo.On("FindByEmailOrUsername", ctx, req.Email, req.Username).Return(nil, ErrUserNotFound).Once()
+		mockRepo.On("CreateUser", ctx, mock.AnythingOfType("*auth.User")).Return(nil).Once()
+
+		_, err := service.Register(ctx, req)
+
+		require.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Invite Required", func(t *testing.T) {
+		t.Setenv("REGISTRATION_MODE", "invite")
+		service := NewAuthService(new(MockAuthRepository))
+
+		_, err := service.Register(ctx, registerReq)
+
+		assert.ErrorIs(t, err, ErrInviteRequired)
+	})
+
+	t.Run("Invalid Invite", func(t *testing.T) {
+		t.Setenv("REGISTRATION_MODE", "invite")
+		mockRepo := new(MockAuthRepository)
+		service := NewAuthService(mockRepo)
+		req := registerReq
+		req.InviteCode = "used-up"
+		mockRepo.On("FindByEmailOrUsername", ctx, req.Email, req.Username).Return(nil, ErrUserNotFound).Once()
+		mockRepo.On("RedeemInvite", ctx, hashSecret("used-up"), mock.AnythingOfType("int64")).Return(nil, ErrInvalidInvite).Once()
+
+		_, err := service.Register(ctx, req)
+
+		assert.ErrorIs(t, err, ErrInvalidInvite)
+		mockRepo.AssertExpectations(t)
+		mockRepo.AssertNotCalled(t, "CreateUser", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Existing User Does Not Use Up Invite", func(t *testing.T) {
+		t.Setenv("REGISTRATION_MODE", "invite")
+		mockRepo := new(MockAuthRepository)
+		service := NewAuthService(mockRepo)
+		req := registerReq
+		req.InviteCode = "valid"
+		mockRepo.On("FindByEmailOrUsername", ctx, req.Email, req.Username).Return(&User{ID: "existing"}, nil).Once()
+
+		_, err := service.Register(ctx, req)
+
+		assert.ErrorIs(t, err, ErrUserAlreadyExists)
+		mockRepo.AssertNotCalled(t, "RedeemInvite", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Valid Invite", func(t *testing.T) {
+		t.Setenv("REGISTRATION_MODE", "invite")
+		mockRepo := new(MockAuthRepository)
+		service := NewAuthService(mockRepo)
+		req := registerReq
+		req.InviteCode = "valid"
+		mockRepo.On("FindByEmailOrUsername", ctx, req.Email, req.Username).Return(nil, ErrUserNotFound).Once()
+		mockRepo.On("RedeemInvite", ctx, hashSecret("valid"), mock.AnythingOfType("int64")).Return(&InviteCode{ID: "invite1", MaxUses: 1, Uses: 1}, nil).Once()
+		mockRepo.On("CreateUser", ctx, mock.AnythingOfType("*auth.User")).Return(nil).Once()
+
+		user, err := service.Register(ctx, req)
+
+		require.NoError(t, err)
+		assert.Equal(t, req.Username, user.Username)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("OIDC Sign Up Needs Open Registration", func(t *testing.T) {
+		t.Setenv("REGISTRATION_MODE", "invite")
+		mockRepo := new(MockAuthRepository)
+		s := NewAuthService(mockRepo).(*authService)
+		identity := &ExternalIdentity{Provider: "test", Subject: "sub1", Email: "new@example.com", EmailVerified: true}
+		mockRepo.On("FindByIdentity", ctx, "test", "sub1").Return(nil, ErrUserNotFound).Once()
+		mockRepo.On("FindByEmail", ctx, identity.Email).Return(nil, ErrUserNotFound).Once()
+
+		_, err := s.resolveExternalUser(ctx, identity)
+
+		assert.ErrorIs(t, err, ErrInviteRequired)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestAuthService_CreateInvite(t *testing.T) {
+	mockRepo := new(MockAuthRepository)
+	service := NewAuthService(mockRepo)
+	ctx := context.Background()
+
+	t.Run("Defaults To Single Use For A Week", func(t *testing.T) {
+		var stored *InviteCode
+		mockRepo.On("CreateInvite", ctx, mock.AnythingOfType("*auth.InviteCode")).Run(func(args mock.Arguments) {
+			stored = args.Get(1).(*InviteCode)
+		}).Return(nil).Once()
+
+		code, invite, err := service.CreateInvite(ctx, "admin1", v1.CreateInviteRequest{})
+
+		require.NoError(t, err)
+		assert.Equal(t, hashSecret(code), stored.Hash, "only the hash must be stored")
+		assert.True(t, strings.HasPrefix(code, invite.Prefix))
+		assert.Equal(t, 1, invite.MaxUses)
+		assert.Equal(t, "admin1", invite.CreatedBy)
+		assert.Equal(t, int64(defaultInviteTTL.Seconds()), invite.ExpiresAt-invite.CreatedAt)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Validation Failure", func(t *testing.T) {
+		_, _, err := service.CreateInvite(ctx, "admin1", v1.CreateInviteRequest{MaxUses: 5000})
+		assert.ErrorIs(t, err, ErrValidationFailed)
+	})
+}
+
+// MockBreachedPasswords is a mock implementation of BreachedPasswords
+type MockBreachedPasswords struct {
+	mock.Mock
+}
+
+func (m *MockBreachedPasswords) Range(ctx context.Context, prefix string) ([]string, error) {
+	args := m.Called(ctx, prefix)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+// passwordReasonCodes returns the reason codes of a PasswordPolicyError.
+func passwordReasonCodes(err error) []string {
+	var policyErr *PasswordPolicyError
+	if !errors.As(err, &policyErr) {
+		return nil
+	}
+	codes := []string{}
+	for _, reason := range policyErr.Reasons {
+		codes = append(codes, reason.Code)
+	}
+	return codes
+}
+
+func TestPasswordPolicy(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Accepts Strong Password", func(t *testing.T) {
+		policy := loadPasswordPolicy()
+		assert.NoError(t, policy.check(ctx, "tangerine-vortex-drizzle", "testuser", "test@example.com"))
+	})
+
+	t.Run("Reports Every Reason", func(t *testing.T) {
+		policy := loadPasswordPolicy()
+		err := policy.check(ctx, "Alice", "alice", "alice@example.com")
+
+		assert.ErrorIs(t, err, ErrWeakPassword)
+		assert.Equal(t, []string{PasswordTooShort, PasswordContainsUsername, PasswordContainsEmail, PasswordTooWeak}, passwordReasonCodes(err))
+	})
+
+	t.Run("Rejects Common Password", func(t *testing.T) {
+		policy := loadPasswordPolicy()
+		err := policy.check(ctx, "password123", "testuser", "test@example.com")
+
+		assert.Equal(t, []string{PasswordTooWeak}, passwordReasonCodes(err))
+	})
+
+	t.Run("Rejects Password Longer Than 72 Bytes", func(t *testing.T) {
+		policy := loadPasswordPolicy()
+		// 25 characters, but 73 bytes.
+		err := policy.check(ctx, strings.Repeat("漢", 24)+"-", "testuser", "test@example.com")
+
+		assert.Contains(t, passwordReasonCodes(err), PasswordTooLong)
+		assert.NoError(t, policy.check(ctx, strings.Repeat("tangerine-vortex-", 4)+"dri", "testuser", "test@example.com"))
+	})
+
+	t.Run("Configurable", func(t *testing.T) {
+		t.Setenv("PASSWORD_MIN_LENGTH", "30")
+		t.Setenv("PASSWORD_MIN_STRENGTH", "0")
+		policy := loadPasswordPolicy()
+		err := policy.check(ctx, "password123", "testuser", "test@example.com")
+
+		assert.Equal(t, []string{PasswordTooShort}, passwordReasonCodes(err))
+	})
+
+	t.Run("Breached Password", func(t *testing.T) {
+		breached := new(MockBreachedPasswords)
+		policy := loadPasswordPolicy()
+		policy.breached = breached
+		// SHA-1 of "tangerine-vortex-drizzle"; only the first five characters are sent.
+		sum := sha1.Sum([]byte("tangerine-vortex-drizzle"))
+		hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+		breached.On("Range", ctx, hash[:5]).Return([]string{"0000000000000000000000000000000000A", hash[5:]}, nil).Once()
+
+		err := policy.check(ctx, "tangerine-vortex-drizzle", "testuser", "test@example.com")
+
+		assert.Equal(t, []string{PasswordBreached}, passwordReasonCodes(err))
+		breached.AssertExpectations(t)
+	})
+
+	t.Run("Unavailable Breached List Is Ignored", func(t *testing.T) {
+		breached := new(MockBreachedPasswords)
+		policy := loadPasswordPolicy()
+		policy.breached = breached
+		breached.On("Range", ctx, mock.AnythingOfType("string")).Return(nil, errors.New("disk error")).Once()
+
+		assert.NoError(t, policy.check(ctx, "tangerine-vortex-drizzle", "testuser", "test@example.com"))
+	})
+}
+
+// MockMetrics is a mock implementation of Metrics
+type MockMetrics struct {
+	mock.Mock
+}
+
+func (m *MockMetrics) AuthEvent(action, outcome string) {
+	m.Called(action, outcome)
+}
+
+func (m *MockMetrics) TokenValidated(kind, result string) {
+	m.Called(kind, result)
+}
+
+func TestAuthService_Metrics(t *testing.T) {
+	mockRepo := new(MockAuthRepository)
+	metrics := new(MockMetrics)
+	service := NewAuthService(mockRepo, WithMetrics(metrics))
+	ctx := context.Background()
+
+	t.Run("Counts Failed Login", func(t *testing.T) {
+		mockRepo.On("FindByUsername", ctx, "ghost").Return(nil, ErrUserNotFound).Once()
+		metrics.On("AuthEvent", AuditLogin, AuditFailure).Once()
+
+		_, err := service.Login(ctx, v1.LoginRequest{Username: "ghost", Password: "password123"})
+
+		assert.ErrorIs(t, err, ErrInvalidCredentials)
+		metrics.AssertExpectations(t)
+	})
+
+	t.Run("Counts Token Validations", func(t *testing.T) {
+		metrics.On("TokenValidated", TokenKindLogin, TokenInvalid).Once()
+		_, err := service.ValidateToken(ctx, "garbage")
+		assert.ErrorIs(t, err, ErrTokenInvalid)
+
+		mockRepo.On("FindPersonalTokenByHash", ctx, hashSecret("anp_unknown")).Return(nil, ErrTokenNotFound).Once()
+		metrics.On("TokenValidated", TokenKindPersonal, TokenInvalid).Once()
+		_, err = service.ValidateToken(ctx, "anp_unknown")
+		assert.ErrorIs(t, err, ErrTokenInvalid)
+
+		mockRepo.On("FindPersonalTokenByHash", ctx, hashSecret("anp_broken")).Return(nil, errors.New("db down")).Once()
+		metrics.On("TokenValidated", TokenKindPersonal, TokenError).Once()
+		_, err = service.ValidateToken(ctx, "anp_broken")
+		assert.Error(t, err)
+
+		metrics.AssertExpectations(t)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestTracedAuthService(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	mockRepo := new(MockAuthRepository)
+	service := NewTracedAuthService(NewAuthService(mockRepo))
+	ctx := context.Background()
+
+	user := &User{ID: "user123", Username: "testuser", Password: hashPasswordForTest(t, "password123")}
+	mockRepo.On("FindByUsername", mock.Anything, "testuser").Return(user, nil).Once()
+
+	_, err := service.Login(ctx, v1.LoginRequest{Username: "testuser", Password: "wrongpassword"})
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	bcryptSpan, loginSpan := spans[0], spans[1]
+	assert.Equal(t, "bcrypt.CompareHashAndPassword", bcryptSpan.Name())
+	assert.Equal(t, "AuthService.Login", loginSpan.Name())
+	assert.Equal(t, loginSpan.SpanContext().SpanID(), bcryptSpan.Parent().SpanID(), "bcrypt must be a child of the service span")
+	assert.Equal(t, codes.Error, loginSpan.Status().Code)
+	mockRepo.AssertExpectations(t)
+}
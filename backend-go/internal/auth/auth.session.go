@@ -0,0 +1,119 @@
+package auth
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// sessionTouchInterval limits how often LastSeenAt is written.
+const sessionTouchInterval = time.Minute
+
+// startSession records a new session for the user on the requesting client
+// and returns a token bound to it.
+func (s *authService) startSession(ctx context.Context, user *User, deviceName string) (string, error) {
+	client := ClientInfoFromContext(ctx)
+	if deviceName == "" {
+		deviceName = deviceLabel(client.UserAgent)
+	}
+
+	now := time.Now()
+	session := &Session{
+		UserID:      user.ID,
+		DeviceLabel: deviceName,
+		UserAgent:   client.UserAgent,
+		IP:          client.IP,
+		CreatedAt:   now.Unix(),
+		LastSeenAt:  now.Unix(),
+		ExpiresAt:   now.Add(loginTokenTTL).Unix(),
+	}
+	if err := s.repo.CreateSession(ctx, session); err != nil {
+		return "", err
+	}
+
+	return s.generateJWT(user, session)
+}
+
+// touchSession records activity on a session, at most once per sessionTouchInterval.
+func (s *authService) touchSession(ctx context.Context, session *Session) {
+	now := time.Now().Unix()
+	if now-session.LastSeenAt < int64(sessionTouchInterval.Seconds()) {
+		return
+	}
+	// Best effort: failing to record activity must not fail the request.
+	_ = s.repo.TouchSession(ctx, session.ID, now, ClientInfoFromContext(ctx).IP)
+}
+
+// ListSessions returns the active sessions of the user.
+func (s *authService) ListSessions(ctx context.Context, userID, currentSessionID string) ([]*Session, error) {
+	sessions, err := s.repo.ListSessions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, session := range sessions {
+		session.Current = session.ID == currentSessionID
+	}
+	return sessions, nil
+}
+
+// RevokeSession deletes a session of the user.
+func (s *authService) RevokeSession(ctx context.Context, userID, sessionID string) error {
+	if err := s.repo.DeleteSession(ctx, userID, sessionID); err != nil {
+		return err
+	}
+	s.audit(ctx, AuditEvent{ActorID: userID, Action: AuditSessionRevoke})
+	return nil
+}
+
+// deviceLabel derives a human readable label such as "Firefox on Linux" from a user agent.
+func deviceLabel(userAgent string) string {
+	if userAgent == "" {
+		return "Unknown device"
+	}
+
+	browser := ""
+	for _, b := range []struct{ token, name string }{
+		// Order matters: most browsers also claim to be Chrome and Safari.
+		{"Edg/", "Edge"},
+		{"OPR/", "Opera"},
+		{"Firefox/", "Firefox"},
+		{"Chrome/", "Chrome"},
+		{"Safari/", "Safari"},
+		{"curl/", "curl"},
+		{"PostmanRuntime/", "Postman"},
+	} {
+		if strings.Contains(userAgent, b.token) {
+			browser = b.name
+			break
+		}
+	}
+
+	os := ""
+	for _, o := range []struct{ token, name string }{
+		{"iPhone", "iOS"},
+		{"iPad", "iPadOS"},
+		{"Android", "Android"},
+		{"Windows", "Windows"},
+		{"Mac OS X", "macOS"},
+		{"CrOS", "ChromeOS"},
+		{"Linux", "Linux"},
+	} {
+		if strings.Contains(userAgent, o.token) {
+			os = o.name
+			break
+		}
+	}
+
+	switch {
+	case browser != "" && os != "":
+		return browser + " on " + os
+	case browser != "":
+		return browser
+	case os != "":
+		return "Browser on " + os
+	}
+	if len(userAgent) > 64 {
+		return userAgent[:64]
+	}
+	return userAgent
+}
@@ -0,0 +1,156 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	v1 "github.com/AldiandyaIrsyad/author-notes/api/v1/auth"
+)
+
+const (
+	// personalTokenPrefix distinguishes personal access tokens from JWTs.
+	personalTokenPrefix = "anp_"
+	// personalTokenTouchInterval limits how often LastUsedAt is written.
+	personalTokenTouchInterval = time.Minute
+)
+
+// CreatePersonalToken creates a personal access token for the user.
+func (s *authService) CreatePersonalToken(ctx context.Context, userID string, req v1.CreatePersonalTokenRequest) (string, *PersonalAccessToken, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return "", nil, ErrValidationFailed
+	}
+
+	user, err := s.repo.FindByID(ctx, userID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// A token can never do more than its owner.
+	granted := PermissionsForRoles(userRoles(user))
+	scopes := []string{}
+	seen := make(map[string]bool)
+	for _, scope := range req.Scopes {
+		if !containsString(granted, scope) {
+			return "", nil, ErrInvalidScope
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+
+	secret, err := generatePersonalTokenSecret()
+	if err != nil {
+		return "", nil, errors.New("failed to generate token")
+	}
+
+	now := time.Now()
+	token := &PersonalAccessToken{
+		UserID:    userID,
+		Name:      req.Name,
+		Scopes:    scopes,
+		Hash:      hashSecret(secret),
+		Prefix:    secret[:len(personalTokenPrefix)+4],
+		CreatedAt: now.Unix(),
+	}
+	if req.ExpiresInDays > 0 {
+		token.ExpiresAt = now.AddDate(0, 0, req.ExpiresInDays).Unix()
+	}
+
+	if err := s.repo.CreatePersonalToken(ctx, token); err != nil {
+		return "", nil, err
+	}
+	s.audit(ctx, AuditEvent{ActorID: userID, Username: user.Username, Action: AuditTokenCreate})
+	return secret, token, nil
+}
+
+// ListPersonalTokens returns the personal access tokens of the user.
+func (s *authService) ListPersonalTokens(ctx context.Context, userID string) ([]*PersonalAccessToken, error) {
+	return s.repo.ListPersonalTokens(ctx, userID)
+}
+
+// RevokePersonalToken deletes a personal access token of the user.
+func (s *authService) RevokePersonalToken(ctx context.Context, userID, tokenID string) error {
+	if err := s.repo.DeletePersonalToken(ctx, userID, tokenID); err != nil {
+		return err
+	}
+	s.audit(ctx, AuditEvent{ActorID: userID, Action: AuditTokenRevoke})
+	return nil
+}
+
+// validatePersonalToken resolves a personal access token to claims limited to
+// the token scopes that the owner still holds.
+func (s *authService) validatePersonalToken(ctx context.Context, secret string) (*Claims, error) {
+	token, err := s.repo.FindPersonalTokenByHash(ctx, hashSecret(secret))
+	if err != nil {
+		if errors.Is(err, ErrTokenNotFound) {
+			return nil, ErrTokenInvalid
+		}
+		return nil, err
+	}
+
+	now := time.Now().Unix()
+	if token.ExpiresAt != 0 && now >= token.ExpiresAt {
+		return nil, ErrTokenExpired
+	}
+
+	user, err := s.repo.FindByID(ctx, token.UserID)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return nil, ErrTokenInvalid
+		}
+		return nil, err
+	}
+	if user.Disabled || user.DeletedAt != 0 {
+		return nil, ErrTokenInvalid
+	}
+
+	if now-token.LastUsedAt >= int64(personalTokenTouchInterval.Seconds()) {
+		// Best effort: failing to record usage must not fail the request.
+		_ = s.repo.TouchPersonalToken(ctx, token.ID, now)
+	}
+
+	roles := userRoles(user)
+	permissions := []string{}
+	for _, p := range PermissionsForRoles(roles) {
+		if containsString(token.Scopes, p) {
+			permissions = append(permissions, p)
+		}
+	}
+
+	return &Claims{
+		UserID:          user.ID,
+		Username:        user.Username,
+		Roles:           roles,
+		Permissions:     permissions,
+		PersonalTokenID: token.ID,
+	}, nil
+}
+
+// generatePersonalTokenSecret returns a new random token secret.
+func generatePersonalTokenSecret() (string, error) {
+	secret, err := randomURLString(32)
+	if err != nil {
+		return "", err
+	}
+	return personalTokenPrefix + secret, nil
+}
+
+// hashSecret returns the stored form of a token secret or invite code. Both have
+// enough entropy that a fast hash is sufficient, and it allows lookups by hash.
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
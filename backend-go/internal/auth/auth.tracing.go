@@ -0,0 +1,174 @@
+package auth
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	v1 "github.com/AldiandyaIrsyad/author-notes/api/v1/auth"
+)
+
+// tracer creates the spans of the auth package. It uses the global tracer
+// provider, which records nothing until tracing is set up.
+var tracer = otel.Tracer("github.com/AldiandyaIrsyad/author-notes/internal/auth")
+
+// tracedAuthService wraps an AuthService with one span per method call.
+type tracedAuthService struct {
+	next AuthService
+}
+
+// NewTracedAuthService returns an AuthService recording a span for every call
+// to next. Repository calls made by next become children of that span.
+func NewTracedAuthService(next AuthService) AuthService {
+	return &tracedAuthService{next: next}
+}
+
+// startSpan starts a span named after an AuthService method.
+func startSpan(ctx context.Context, method string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracer.Start(ctx, "AuthService."+method, trace.WithAttributes(attrs...))
+}
+
+// endSpan records err, if any, and ends span.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func (t *tracedAuthService) Register(ctx context.Context, req v1.RegisterRequest) (*User, error) {
+	ctx, span := startSpan(ctx, "Register")
+	user, err := t.next.Register(ctx, req)
+	endSpan(span, err)
+	return user, err
+}
+
+func (t *tracedAuthService) Login(ctx context.Context, req v1.LoginRequest) (string, error) {
+	ctx, span := startSpan(ctx, "Login")
+	token, err := t.next.Login(ctx, req)
+	endSpan(span, err)
+	return token, err
+}
+
+func (t *tracedAuthService) ValidateToken(ctx context.Context, token string) (*Claims, error) {
+	ctx, span := startSpan(ctx, "ValidateToken")
+	claims, err := t.next.ValidateToken(ctx, token)
+	if err == nil {
+		span.SetAttributes(attribute.String("enduser.id", claims.UserID))
+	}
+	endSpan(span, err)
+	return claims, err
+}
+
+func (t *tracedAuthService) GetProfile(ctx context.Context, userID string) (*User, error) {
+	ctx, span := startSpan(ctx, "GetProfile")
+	user, err := t.next.GetProfile(ctx, userID)
+	endSpan(span, err)
+	return user, err
+}
+
+func (t *tracedAuthService) ListUsers(ctx context.Context, req v1.ListUsersRequest) (*UserPage, error) {
+	ctx, span := startSpan(ctx, "ListUsers")
+	page, err := t.next.ListUsers(ctx, req)
+	endSpan(span, err)
+	return page, err
+}
+
+func (t *tracedAuthService) SetUserDisabled(ctx context.Context, actorID, userID string, disabled bool) (*User, error) {
+	ctx, span := startSpan(ctx, "SetUserDisabled", attribute.Bool("disabled", disabled))
+	user, err := t.next.SetUserDisabled(ctx, actorID, userID, disabled)
+	endSpan(span, err)
+	return user, err
+}
+
+func (t *tracedAuthService) CreateInvite(ctx context.Context, actorID string, req v1.CreateInviteRequest) (string, *InviteCode, error) {
+	ctx, span := startSpan(ctx, "CreateInvite")
+	code, invite, err := t.next.CreateInvite(ctx, actorID, req)
+	endSpan(span, err)
+	return code, invite, err
+}
+
+func (t *tracedAuthService) ListInvites(ctx context.Context) ([]*InviteCode, error) {
+	ctx, span := startSpan(ctx, "ListInvites")
+	invites, err := t.next.ListInvites(ctx)
+	endSpan(span, err)
+	return invites, err
+}
+
+func (t *tracedAuthService) RevokeInvite(ctx context.Context, actorID, inviteID string) error {
+	ctx, span := startSpan(ctx, "RevokeInvite")
+	err := t.next.RevokeInvite(ctx, actorID, inviteID)
+	endSpan(span, err)
+	return err
+}
+
+func (t *tracedAuthService) ChangePassword(ctx context.Context, userID, currentSessionID string, req v1.ChangePasswordRequest) error {
+	ctx, span := startSpan(ctx, "ChangePassword")
+	err := t.next.ChangePassword(ctx, userID, currentSessionID, req)
+	endSpan(span, err)
+	return err
+}
+
+func (t *tracedAuthService) ListAuditEvents(ctx context.Context, req v1.ListAuditEventsRequest) (*AuditPage, error) {
+	ctx, span := startSpan(ctx, "ListAuditEvents")
+	page, err := t.next.ListAuditEvents(ctx, req)
+	endSpan(span, err)
+	return page, err
+}
+
+func (t *tracedAuthService) ListSessions(ctx context.Context, userID, currentSessionID string) ([]*Session, error) {
+	ctx, span := startSpan(ctx, "ListSessions")
+	sessions, err := t.next.ListSessions(ctx, userID, currentSessionID)
+	endSpan(span, err)
+	return sessions, err
+}
+
+func (t *tracedAuthService) RevokeSession(ctx context.Context, userID, sessionID string) error {
+	ctx, span := startSpan(ctx, "RevokeSession")
+	err := t.next.RevokeSession(ctx, userID, sessionID)
+	endSpan(span, err)
+	return err
+}
+
+func (t *tracedAuthService) CreatePersonalToken(ctx context.Context, userID string, req v1.CreatePersonalTokenRequest) (string, *PersonalAccessToken, error) {
+	ctx, span := startSpan(ctx, "CreatePersonalToken")
+	secret, token, err := t.next.CreatePersonalToken(ctx, userID, req)
+	endSpan(span, err)
+	return secret, token, err
+}
+
+func (t *tracedAuthService) ListPersonalTokens(ctx context.Context, userID string) ([]*PersonalAccessToken, error) {
+	ctx, span := startSpan(ctx, "ListPersonalTokens")
+	tokens, err := t.next.ListPersonalTokens(ctx, userID)
+	endSpan(span, err)
+	return tokens, err
+}
+
+func (t *tracedAuthService) RevokePersonalToken(ctx context.Context, userID, tokenID string) error {
+	ctx, span := startSpan(ctx, "RevokePersonalToken")
+	err := t.next.RevokePersonalToken(ctx, userID, tokenID)
+	endSpan(span, err)
+	return err
+}
+
+func (t *tracedAuthService) IdentityProviders() []string {
+	return t.next.IdentityProviders()
+}
+
+func (t *tracedAuthService) BeginOIDCLogin(ctx context.Context, provider string) (string, error) {
+	ctx, span := startSpan(ctx, "BeginOIDCLogin", attribute.String("oidc.provider", provider))
+	url, err := t.next.BeginOIDCLogin(ctx, provider)
+	endSpan(span, err)
+	return url, err
+}
+
+func (t *tracedAuthService) CompleteOIDCLogin(ctx context.Context, provider, state, code string) (string, error) {
+	ctx, span := startSpan(ctx, "CompleteOIDCLogin", attribute.String("oidc.provider", provider))
+	token, err := t.next.CompleteOIDCLogin(ctx, provider, state, code)
+	endSpan(span, err)
+	return token, err
+}
@@ -0,0 +1,225 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testIdentityProvider is a minimal local OpenID Connect provider that issues
+// codes from /authorize and RS256 ID tokens from /token.
+type testIdentityProvider struct {
+	t      *testing.T
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	mu    sync.Mutex
+	codes map[string]pendingCode
+
+	// Claims overrides applied to issued ID tokens.
+	overrideClaims jwt.MapClaims
+}
+
+type pendingCode struct {
+	challenge string
+	nonce     string
+}
+
+func newTestIdentityProvider(t *testing.T) *testIdentityProvider {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	idp := &testIdentityProvider{t: t, key: key, codes: map[string]pendingCode{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{
+			"issuer":                 idp.server.URL,
+			"authorization_endpoint": idp.server.URL + "/authorize",
+			"token_endpoint":         idp.server.URL + "/token",
+			"jwks_uri":               idp.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]any{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "test-key",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", idp.handleToken)
+	idp.server = httptest.NewServer(mux)
+	t.Cleanup(idp.server.Close)
+	return idp
+}
+
+// authorize simulates the user approving the sign in at authURL and returns the issued code.
+func (idp *testIdentityProvider) authorize(authURL string) (code, state string) {
+	u, err := url.Parse(authURL)
+	require.NoError(idp.t, err)
+	q := u.Query()
+	require.Equal(idp.t, "S256", q.Get("code_challenge_method"))
+
+	code = "code-" + q.Get("state")
+	idp.mu.Lock()
+	idp.codes[code] = pendingCode{challenge: q.Get("code_challenge"), nonce: q.Get("nonce")}
+	idp.mu.Unlock()
+	return code, q.Get("state")
+}
+
+func (idp *testIdentityProvider) handleToken(w http.ResponseWriter, r *http.Request) {
+	require.NoError(idp.t, r.ParseForm())
+	idp.mu.Lock()
+	pending, ok := idp.codes[r.PostForm.Get("code")]
+	delete(idp.codes, r.PostForm.Get("code"))
+	idp.mu.Unlock()
+	if !ok {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if base64.RawURLEncoding.EncodeToString(sum[:]) != pending.challenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant", "error_description": "PKCE verification failed"})
+		return
+	}
+
+	claims := jwt.MapClaims{
+		"iss":            idp.server.URL,
+		"sub":            "external-123",
+		"aud":            "test-client",
+		"exp":            time.Now().Add(time.Hour).Unix(),
+		"iat":            time.Now().Unix(),
+		"nonce":          pending.nonce,
+		"email":          "writer@example.com",
+		"email_verified": true,
+	}
+	for k, v := range idp.overrideClaims {
+		claims[k] = v
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = "test-key"
+	signed, err := token.SignedString(idp.key)
+	require.NoError(idp.t, err)
+	writeJSON(w, http.StatusOK, map[string]string{"id_token": signed, "access_token": "unused", "token_type": "Bearer"})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func pkceChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func TestProvider_AuthorizationCodeFlow(t *testing.T) {
+	idp := newTestIdentityProvider(t)
+	ctx := context.Background()
+
+	p, err := NewProvider(ctx, Config{
+		Name:        "test",
+		Issuer:      idp.server.URL,
+		ClientID:    "test-client",
+		RedirectURL: "http://localhost:5173/oidc/callback",
+	})
+	require.NoError(t, err)
+
+	t.Run("Success", func(t *testing.T) {
+		idp.overrideClaims = nil
+		code, state := idp.authorize(p.AuthCodeURL("state1", "nonce1", pkceChallenge("verifier1")))
+		assert.Equal(t, "state1", state)
+
+		identity, err := p.Exchange(ctx, code, "verifier1", "nonce1")
+
+		require.NoError(t, err)
+		assert.Equal(t, "test", identity.Provider)
+		assert.Equal(t, "external-123", identity.Subject)
+		assert.Equal(t, "writer@example.com", identity.Email)
+		assert.True(t, identity.EmailVerified)
+	})
+
+	t.Run("Wrong Code Verifier", func(t *testing.T) {
+		idp.overrideClaims = nil
+		code, _ := idp.authorize(p.AuthCodeURL("state2", "nonce2", pkceChallenge("verifier2")))
+
+		identity, err := p.Exchange(ctx, code, "another-verifier", "nonce2")
+
+		assert.Nil(t, identity)
+		assert.ErrorContains(t, err, "PKCE")
+	})
+
+	t.Run("Nonce Mismatch", func(t *testing.T) {
+		idp.overrideClaims = nil
+		code, _ := idp.authorize(p.AuthCodeURL("state3", "nonce3", pkceChallenge("verifier3")))
+
+		identity, err := p.Exchange(ctx, code, "verifier3", "replayed-nonce")
+
+		assert.Nil(t, identity)
+		assert.ErrorContains(t, err, "nonce")
+	})
+
+	t.Run("Wrong Audience", func(t *testing.T) {
+		idp.overrideClaims = jwt.MapClaims{"aud": "another-client"}
+		code, _ := idp.authorize(p.AuthCodeURL("state4", "nonce4", pkceChallenge("verifier4")))
+
+		identity, err := p.Exchange(ctx, code, "verifier4", "nonce4")
+
+		assert.Nil(t, identity)
+		assert.ErrorContains(t, err, "invalid id_token")
+	})
+
+	t.Run("Expired ID Token", func(t *testing.T) {
+		idp.overrideClaims = jwt.MapClaims{"exp": time.Now().Add(-time.Hour).Unix()}
+		code, _ := idp.authorize(p.AuthCodeURL("state5", "nonce5", pkceChallenge("verifier5")))
+
+		identity, err := p.Exchange(ctx, code, "verifier5", "nonce5")
+
+		assert.Nil(t, identity)
+		assert.ErrorContains(t, err, "invalid id_token")
+	})
+
+	t.Run("Email Verified As String", func(t *testing.T) {
+		idp.overrideClaims = jwt.MapClaims{"email_verified": "false"}
+		code, _ := idp.authorize(p.AuthCodeURL("state6", "nonce6", pkceChallenge("verifier6")))
+
+		identity, err := p.Exchange(ctx, code, "verifier6", "nonce6")
+
+		require.NoError(t, err)
+		assert.False(t, identity.EmailVerified)
+	})
+}
+
+func TestNewProvider_IssuerMismatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{
+			"issuer":                 "https://evil.example.com",
+			"authorization_endpoint": "https://evil.example.com/authorize",
+			"token_endpoint":         "https://evil.example.com/token",
+			"jwks_uri":               "https://evil.example.com/jwks",
+		})
+	}))
+	defer server.Close()
+
+	p, err := NewProvider(context.Background(), Config{Name: "test", Issuer: server.URL, ClientID: "test-client"})
+
+	assert.Nil(t, p)
+	assert.ErrorContains(t, err, "issuer mismatch")
+}
@@ -0,0 +1,195 @@
+package field
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	v1 "github.com/AldiandyaIrsyad/author-notes/api/v1/field"
+	app_auth "github.com/AldiandyaIrsyad/author-notes/internal/auth"
+	auth_adapter "github.com/AldiandyaIrsyad/author-notes/internal/auth/adapter"
+	app_field "github.com/AldiandyaIrsyad/author-notes/internal/field"
+)
+
+type FieldHTTPHandler struct {
+	service app_field.FieldService
+	logger  *slog.Logger
+}
+
+func NewFieldHTTPHandler(service app_field.FieldService, logger *slog.Logger) *FieldHTTPHandler {
+	return &FieldHTTPHandler{service: service, logger: logger}
+}
+
+// internalError logs err and responds with a generic 500 error that does not leak it.
+func (h *FieldHTTPHandler) internalError(c *gin.Context, message string, err error) {
+	h.logger.ErrorContext(c.Request.Context(), message, "route", c.FullPath(), "error", err)
+	c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+}
+
+// RegisterRoutes registers the field routes on rg, which must be authenticated.
+func (h *FieldHTTPHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	read := auth_adapter.RequirePermission(app_auth.PermContentRead)
+	write := auth_adapter.RequirePermission(app_auth.PermContentWrite)
+
+	fieldsGroup := rg.Group("/fields")
+	fieldsGroup.GET("", read, h.ListFields)
+	fieldsGroup.POST("", write, h.CreateField)
+	fieldsGroup.GET("/:id", read, h.GetField)
+	fieldsGroup.PUT("/:id", write, h.UpdateField)
+	fieldsGroup.DELETE("/:id", write, h.DeleteField)
+}
+
+// ListFields handles the request for the caller's fields.
+// @Summary List my fields
+// @Description Returns every field definition of the caller, sorted by name.
+// @Tags fields
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} field.Field "Fields"
+// @Failure 401 {object} map[string]string "Missing or invalid token"
+// @Failure 403 {object} map[string]string "Missing permission"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/fields [get]
+func (h *FieldHTTPHandler) ListFields(c *gin.Context) {
+	claims, _ := app_auth.ClaimsFromContext(c.Request.Context())
+
+	fields, err := h.service.ListFields(c.Request.Context(), claims.UserID)
+	if err != nil {
+		h.internalError(c, "Failed to list fields", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, fields)
+}
+
+// CreateField handles the request to create a field.
+// @Summary Create a field
+// @Description Creates a field definition. Only the options that apply to the kind may be set: min_length, max_length and pattern for text; min, max and integer for numbers; min_date and max_date for dates; choices, min_selected and max_selected for selects; schemes for URLs.
+// @Tags fields
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param field body v1.CreateFieldRequest true "Field definition"
+// @Success 201 {object} field.Field "Field created"
+// @Failure 400 {object} map[string]string "Validation error or options invalid for the kind"
+// @Failure 401 {object} map[string]string "Missing or invalid token"
+// @Failure 403 {object} map[string]string "Missing permission"
+// @Failure 409 {object} map[string]string "Field name already used"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/fields [post]
+func (h *FieldHTTPHandler) CreateField(c *gin.Context) {
+	var req v1.CreateFieldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	claims, _ := app_auth.ClaimsFromContext(c.Request.Context())
+
+	field, err := h.service.CreateField(c.Request.Context(), claims.UserID, req)
+	if err != nil {
+		h.respondError(c, "Failed to create field", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, field)
+}
+
+// GetField handles the request for a single field.
+// @Summary Get a field
+// @Tags fields
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Field ID"
+// @Success 200 {object} field.Field "Field"
+// @Failure 401 {object} map[string]string "Missing or invalid token"
+// @Failure 403 {object} map[string]string "Missing permission"
+// @Failure 404 {object} map[string]string "Field not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/fields/{id} [get]
+func (h *FieldHTTPHandler) GetField(c *gin.Context) {
+	claims, _ := app_auth.ClaimsFromContext(c.Request.Context())
+
+	field, err := h.service.GetField(c.Request.Context(), claims.UserID, c.Param("id"))
+	if err != nil {
+		h.respondError(c, "Failed to get field", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, field)
+}
+
+// UpdateField handles the request to update a field.
+// @Summary Update a field
+// @Description Replaces the name, description and options of a field. The kind of a field cannot be changed.
+// @Tags fields
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Field ID"
+// @Param field body v1.UpdateFieldRequest true "New field definition"
+// @Success 200 {object} field.Field "Updated field"
+// @Failure 400 {object} map[string]string "Validation error or options invalid for the kind"
+// @Failure 401 {object} map[string]string "Missing or invalid token"
+// @Failure 403 {object} map[string]string "Missing permission"
+// @Failure 404 {object} map[string]string "Field not found"
+// @Failure 409 {object} map[string]string "Field name already used"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/fields/{id} [put]
+func (h *FieldHTTPHandler) UpdateField(c *gin.Context) {
+	var req v1.UpdateFieldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	claims, _ := app_auth.ClaimsFromContext(c.Request.Context())
+
+	field, err := h.service.UpdateField(c.Request.Context(), claims.UserID, c.Param("id"), req)
+	if err != nil {
+		h.respondError(c, "Failed to update field", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, field)
+}
+
+// DeleteField handles the request to delete a field.
+// @Summary Delete a field
+// @Description Deletes a field definition. A field that templates still place cannot be deleted.
+// @Tags fields
+// @Security BearerAuth
+// @Param id path string true "Field ID"
+// @Success 204 "Field deleted"
+// @Failure 401 {object} map[string]string "Missing or invalid token"
+// @Failure 403 {object} map[string]string "Missing permission"
+// @Failure 404 {object} map[string]string "Field not found"
+// @Failure 409 {object} map[string]string "Field is still used by templates"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/fields/{id} [delete]
+func (h *FieldHTTPHandler) DeleteField(c *gin.Context) {
+	claims, _ := app_auth.ClaimsFromContext(c.Request.Context())
+
+	if err := h.service.DeleteField(c.Request.Context(), claims.UserID, c.Param("id")); err != nil {
+		h.respondError(c, "Failed to delete field", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// respondError maps the errors of the field service to HTTP responses.
+func (h *FieldHTTPHandler) respondError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, app_field.ErrValidationFailed),
+		errors.Is(err, app_field.ErrInvalidOptions):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, app_field.ErrFieldNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, app_field.ErrFieldNameTaken),
+		errors.Is(err, app_field.ErrFieldInUse):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		h.internalError(c, message, err)
+	}
+}
@@ -0,0 +1,102 @@
+package field
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	app_field "github.com/AldiandyaIrsyad/author-notes/internal/field"
+)
+
+// mongoFieldRepository implements the FieldRepository interface using MongoDB.
+type mongoFieldRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoFieldRepository creates a new instance of mongoFieldRepository.
+func NewMongoFieldRepository(db *mongo.Database) app_field.FieldRepository {
+	return &mongoFieldRepository{
+		collection: db.Collection("fields"),
+	}
+}
+
+// CreateField inserts a new field.
+func (r *mongoFieldRepository) CreateField(ctx context.Context, field *app_field.Field) error {
+	if field.ID == "" {
+		field.ID = uuid.NewString()
+	}
+	_, err := r.collection.InsertOne(ctx, field)
+	return err
+}
+
+// UpdateField replaces a stored field with the given one.
+func (r *mongoFieldRepository) UpdateField(ctx context.Context, field *app_field.Field) error {
+	result, err := r.collection.ReplaceOne(ctx, bson.M{"_id": field.ID, "owner_id": field.OwnerID}, field)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return app_field.ErrFieldNotFound
+	}
+	return nil
+}
+
+// FindFieldByID retrieves a field by its ID.
+func (r *mongoFieldRepository) FindFieldByID(ctx context.Context, id string) (*app_field.Field, error) {
+	return r.findOne(ctx, bson.M{"_id": id})
+}
+
+// FindFieldByName retrieves the field of an owner with the given name.
+func (r *mongoFieldRepository) FindFieldByName(ctx context.Context, ownerID, name string) (*app_field.Field, error) {
+	return r.findOne(ctx, bson.M{"owner_id": ownerID, "name": name})
+}
+
+func (r *mongoFieldRepository) findOne(ctx context.Context, filter bson.M) (*app_field.Field, error) {
+	var field app_field.Field
+	err := r.collection.FindOne(ctx, filter).Decode(&field)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, app_field.ErrFieldNotFound
+		}
+		return nil, err
+	}
+	return &field, nil
+}
+
+// ListFieldsByOwner returns every field of an owner, sorted by name.
+func (r *mongoFieldRepository) ListFieldsByOwner(ctx context.Context, ownerID string) ([]*app_field.Field, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"owner_id": ownerID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	fields := []*app_field.Field{}
+	if err := cursor.All(ctx, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// DeleteField removes a field of an owner.
+func (r *mongoFieldRepository) DeleteField(ctx context.Context, ownerID, id string) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id, "owner_id": ownerID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return app_field.ErrFieldNotFound
+	}
+	return nil
+}
+
+// DeleteFieldsByOwner removes every field of an owner.
+func (r *mongoFieldRepository) DeleteFieldsByOwner(ctx context.Context, ownerID string) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"owner_id": ownerID})
+	return err
+}
@@ -0,0 +1,12 @@
+package field
+
+import "errors"
+
+var (
+	ErrFieldNotFound    = errors.New("field not found")
+	ErrFieldNameTaken   = errors.New("a field with this name already exists")
+	ErrValidationFailed = errors.New("input validation failed")
+	ErrInvalidOptions   = errors.New("invalid field options")
+	ErrInvalidValue     = errors.New("invalid field value")
+	ErrFieldInUse       = errors.New("field is still used by templates")
+)
@@ -0,0 +1,440 @@
+package field
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// Limits of text values, also used when a field sets no MaxLength.
+	maxShortTextLength = 255
+	maxLongTextLength  = 100_000
+	maxURLLength       = 2048
+	maxReferenceLength = 64
+	maxChoices         = 100
+
+	dateLayout = "2006-01-02"
+)
+
+var (
+	defaultSchemes = []string{"http", "https"}
+	schemePattern  = regexp.MustCompile(`^[a-z][a-z0-9+.-]*$`)
+)
+
+// kindRules describe a kind: the options that apply to it besides Required,
+// how those options are checked, and what is wrong with a non-empty value.
+type kindRules struct {
+	options      []string
+	checkOptions func(o *Options) error
+	checkValue   func(o *Options, value any) string
+}
+
+var kinds = map[Kind]kindRules{
+	KindShortText: {
+		options:      []string{"min_length", "max_length", "pattern"},
+		checkOptions: textOptions(maxShortTextLength),
+		checkValue: func(o *Options, value any) string {
+			return textProblem(o, value, maxShortTextLength, true)
+		},
+	},
+	KindLongText: {
+		options:      []string{"min_length", "max_length"},
+		checkOptions: textOptions(maxLongTextLength),
+		checkValue: func(o *Options, value any) string {
+			return textProblem(o, value, maxLongTextLength, false)
+		},
+	},
+	KindNumber: {
+		options:      []string{"min", "max", "integer"},
+		checkOptions: numberOptions,
+		checkValue:   numberProblem,
+	},
+	KindDate: {
+		options:      []string{"min_date", "max_date"},
+		checkOptions: dateOptions,
+		checkValue:   dateProblem,
+	},
+	KindBoolean: {
+		checkValue: func(_ *Options, value any) string {
+			if _, ok := value.(bool); !ok {
+				return "must be true or false"
+			}
+			return ""
+		},
+	},
+	KindSingleSelect: {
+		options:      []string{"choices"},
+		checkOptions: selectOptions,
+		checkValue: func(o *Options, value any) string {
+			s, ok := value.(string)
+			if !ok || !slices.Contains(o.Choices, s) {
+				return "must be one of the choices"
+			}
+			return ""
+		},
+	},
+	KindMultiSelect: {
+		options:      []string{"choices", "min_selected", "max_selected"},
+		checkOptions: multiSelectOptions,
+		checkValue:   multiSelectProblem,
+	},
+	KindURL: {
+		options:      []string{"schemes"},
+		checkOptions: urlOptions,
+		checkValue:   urlProblem,
+	},
+	KindReference: {
+		options: []string{"target_template_id"},
+		checkValue: func(_ *Options, value any) string {
+			s, ok := value.(string)
+			if !ok || len(s) > maxReferenceLength {
+				return "must be the ID of a note"
+			}
+			return ""
+		},
+	},
+}
+
+// Kinds returns every field kind.
+func Kinds() []Kind {
+	all := make([]Kind, 0, len(kinds))
+	for kind := range kinds {
+		all = append(all, kind)
+	}
+	slices.Sort(all)
+	return all
+}
+
+// IsValid reports whether k is a known kind.
+func (k Kind) IsValid() bool {
+	_, ok := kinds[k]
+	return ok
+}
+
+// ValidateOptions checks that options only set rules that apply to kind and
+// that those rules are consistent. It normalises the options in place.
+func ValidateOptions(kind Kind, options *Options) error {
+	rules, ok := kinds[kind]
+	if !ok {
+		return fmt.Errorf("%w: unknown kind %q", ErrInvalidOptions, kind)
+	}
+	for _, name := range options.set() {
+		if !slices.Contains(rules.options, name) {
+			return fmt.Errorf("%w: %s does not apply to %s fields", ErrInvalidOptions, name, kind)
+		}
+	}
+	if rules.checkOptions == nil {
+		return nil
+	}
+	return rules.checkOptions(options)
+}
+
+// set returns the JSON names of the options other than Required that are set.
+func (o *Options) set() []string {
+	var names []string
+	add := func(name string, isSet bool) {
+		if isSet {
+			names = append(names, name)
+		}
+	}
+	add("min_length", o.MinLength != 0)
+	add("max_length", o.MaxLength != 0)
+	add("pattern", o.Pattern != "")
+	add("min", o.Min != nil)
+	add("max", o.Max != nil)
+	add("integer", o.Integer)
+	add("min_date", o.MinDate != "")
+	add("max_date", o.MaxDate != "")
+	add("choices", len(o.Choices) > 0)
+	add("min_selected", o.MinSelected != 0)
+	add("max_selected", o.MaxSelected != 0)
+	add("schemes", len(o.Schemes) > 0)
+	add("target_template_id", o.TargetTemplateID != "")
+	return names
+}
+
+// ValidateValue checks a value, as decoded from JSON, against the kind and
+// options of the field. Empty values are only rejected by required fields.
+func (f *Field) ValidateValue(value any) error {
+	if IsEmptyValue(value) {
+		if f.Options.Required {
+			return fmt.Errorf("%w: %s is required", ErrInvalidValue, f.Name)
+		}
+		return nil
+	}
+	rules, ok := kinds[f.Kind]
+	if !ok {
+		return fmt.Errorf("%w: %s has unknown kind %q", ErrInvalidValue, f.Name, f.Kind)
+	}
+	if problem := rules.checkValue(&f.Options, value); problem != "" {
+		return fmt.Errorf("%w: %s %s", ErrInvalidValue, f.Name, problem)
+	}
+	return nil
+}
+
+// IsEmptyValue reports whether value counts as not filled in: null, blank
+// text or an empty list.
+func IsEmptyValue(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(v) == ""
+	}
+	items, ok := ListValue(value)
+	return ok && len(items) == 0
+}
+
+// ListValue returns the items of a list value, such as a multi select value.
+// Lists decoded from JSON are []any, but lists decoded from BSON have their
+// own slice type.
+func ListValue(value any) ([]any, bool) {
+	if items, ok := value.([]any); ok {
+		return items, true
+	}
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Slice {
+		return nil, false
+	}
+	items := make([]any, v.Len())
+	for i := range items {
+		items[i] = v.Index(i).Interface()
+	}
+	return items, true
+}
+
+func textOptions(limit int) func(o *Options) error {
+	return func(o *Options) error {
+		if o.MinLength < 0 || o.MaxLength < 0 {
+			return fmt.Errorf("%w: lengths must not be negative", ErrInvalidOptions)
+		}
+		if o.MaxLength > limit || o.MinLength > limit {
+			return fmt.Errorf("%w: lengths must not exceed %d", ErrInvalidOptions, limit)
+		}
+		if o.MaxLength != 0 && o.MinLength > o.MaxLength {
+			return fmt.Errorf("%w: min_length must not exceed max_length", ErrInvalidOptions)
+		}
+		if o.Pattern != "" {
+			if _, err := compilePattern(o.Pattern); err != nil {
+				return fmt.Errorf("%w: pattern is not a valid regular expression", ErrInvalidOptions)
+			}
+		}
+		return nil
+	}
+}
+
+// compilePattern compiles a pattern that must match a whole value.
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile(`^(?:` + pattern + `)$`)
+}
+
+func textProblem(o *Options, value any, limit int, singleLine bool) string {
+	s, ok := value.(string)
+	if !ok {
+		return "must be text"
+	}
+	if singleLine && strings.ContainsAny(s, "\r\n") {
+		return "must be a single line"
+	}
+	n := utf8.RuneCountInString(s)
+	if n < o.MinLength {
+		return fmt.Sprintf("must be at least %d characters long", o.MinLength)
+	}
+	if o.MaxLength != 0 {
+		limit = o.MaxLength
+	}
+	if n > limit {
+		return fmt.Sprintf("must be at most %d characters long", limit)
+	}
+	if o.Pattern != "" {
+		re, err := compilePattern(o.Pattern)
+		if err != nil || !re.MatchString(s) {
+			return "does not match the expected format"
+		}
+	}
+	return ""
+}
+
+func numberOptions(o *Options) error {
+	for _, bound := range []*float64{o.Min, o.Max} {
+		if bound != nil && (math.IsNaN(*bound) || math.IsInf(*bound, 0)) {
+			return fmt.Errorf("%w: min and max must be finite", ErrInvalidOptions)
+		}
+	}
+	if o.Min != nil && o.Max != nil && *o.Min > *o.Max {
+		return fmt.Errorf("%w: min must not exceed max", ErrInvalidOptions)
+	}
+	return nil
+}
+
+func numberProblem(o *Options, value any) string {
+	var n float64
+	switch v := value.(type) {
+	case float64:
+		n = v
+	case int:
+		n = float64(v)
+	case int32:
+		n = float64(v)
+	case int64:
+		n = float64(v)
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return "must be a number"
+		}
+		n = f
+	default:
+		return "must be a number"
+	}
+	switch {
+	case math.IsNaN(n) || math.IsInf(n, 0):
+		return "must be a finite number"
+	case o.Integer && n != math.Trunc(n):
+		return "must be a whole number"
+	case o.Min != nil && n < *o.Min:
+		return fmt.Sprintf("must be at least %v", *o.Min)
+	case o.Max != nil && n > *o.Max:
+		return fmt.Sprintf("must be at most %v", *o.Max)
+	}
+	return ""
+}
+
+func dateOptions(o *Options) error {
+	for _, bound := range []string{o.MinDate, o.MaxDate} {
+		if _, err := time.Parse(dateLayout, bound); bound != "" && err != nil {
+			return fmt.Errorf("%w: min_date and max_date must be dates as YYYY-MM-DD", ErrInvalidOptions)
+		}
+	}
+	if o.MinDate != "" && o.MaxDate != "" && o.MinDate > o.MaxDate {
+		return fmt.Errorf("%w: min_date must not be after max_date", ErrInvalidOptions)
+	}
+	return nil
+}
+
+func dateProblem(o *Options, value any) string {
+	s, ok := value.(string)
+	if !ok {
+		return "must be a date as YYYY-MM-DD"
+	}
+	if _, err := time.Parse(dateLayout, s); err != nil {
+		return "must be a date as YYYY-MM-DD"
+	}
+	// Dates in this layout sort chronologically as strings.
+	if o.MinDate != "" && s < o.MinDate {
+		return "must not be before " + o.MinDate
+	}
+	if o.MaxDate != "" && s > o.MaxDate {
+		return "must not be after " + o.MaxDate
+	}
+	return ""
+}
+
+func selectOptions(o *Options) error {
+	if len(o.Choices) == 0 {
+		return fmt.Errorf("%w: select fields need at least one choice", ErrInvalidOptions)
+	}
+	if len(o.Choices) > maxChoices {
+		return fmt.Errorf("%w: select fields have at most %d choices", ErrInvalidOptions, maxChoices)
+	}
+	seen := make(map[string]bool, len(o.Choices))
+	for i, choice := range o.Choices {
+		choice = strings.TrimSpace(choice)
+		if choice == "" {
+			return fmt.Errorf("%w: choices must not be blank", ErrInvalidOptions)
+		}
+		if seen[choice] {
+			return fmt.Errorf("%w: choice %q is listed twice", ErrInvalidOptions, choice)
+		}
+		seen[choice] = true
+		o.Choices[i] = choice
+	}
+	return nil
+}
+
+func multiSelectOptions(o *Options) error {
+	if err := selectOptions(o); err != nil {
+		return err
+	}
+	if o.MinSelected < 0 || o.MaxSelected < 0 {
+		return fmt.Errorf("%w: min_selected and max_selected must not be negative", ErrInvalidOptions)
+	}
+	if o.MinSelected > len(o.Choices) || o.MaxSelected > len(o.Choices) {
+		return fmt.Errorf("%w: min_selected and max_selected must not exceed the number of choices", ErrInvalidOptions)
+	}
+	if o.MaxSelected != 0 && o.MinSelected > o.MaxSelected {
+		return fmt.Errorf("%w: min_selected must not exceed max_selected", ErrInvalidOptions)
+	}
+	return nil
+}
+
+func multiSelectProblem(o *Options, value any) string {
+	items, ok := ListValue(value)
+	if !ok {
+		return "must be a list of choices"
+	}
+	selected := make([]string, len(items))
+	for i, item := range items {
+		s, ok := item.(string)
+		if !ok {
+			return "must be a list of choices"
+		}
+		selected[i] = s
+	}
+
+	seen := make(map[string]bool, len(selected))
+	for _, s := range selected {
+		if !slices.Contains(o.Choices, s) {
+			return fmt.Sprintf("has %q, which is not one of the choices", s)
+		}
+		if seen[s] {
+			return fmt.Sprintf("has %q twice", s)
+		}
+		seen[s] = true
+	}
+	if len(selected) < o.MinSelected {
+		return fmt.Sprintf("must have at least %d choices", o.MinSelected)
+	}
+	if o.MaxSelected != 0 && len(selected) > o.MaxSelected {
+		return fmt.Sprintf("must have at most %d choices", o.MaxSelected)
+	}
+	return ""
+}
+
+func urlOptions(o *Options) error {
+	for i, scheme := range o.Schemes {
+		scheme = strings.ToLower(strings.TrimSpace(scheme))
+		if !schemePattern.MatchString(scheme) {
+			return fmt.Errorf("%w: %q is not a URL scheme", ErrInvalidOptions, scheme)
+		}
+		o.Schemes[i] = scheme
+	}
+	return nil
+}
+
+func urlProblem(o *Options, value any) string {
+	s, ok := value.(string)
+	if !ok || len(s) > maxURLLength {
+		return "must be a URL"
+	}
+	u, err := url.Parse(s)
+	if err != nil || u.Host == "" {
+		return "must be an absolute URL"
+	}
+	schemes := o.Schemes
+	if len(schemes) == 0 {
+		schemes = defaultSchemes
+	}
+	if !slices.Contains(schemes, strings.ToLower(u.Scheme)) {
+		return "must start with " + strings.Join(schemes, ":// or ") + "://"
+	}
+	return ""
+}
@@ -0,0 +1,49 @@
+package field
+
+// Kind is the type of value a field holds.
+type Kind string
+
+const (
+	KindShortText    Kind = "short_text"    // single line of text
+	KindLongText     Kind = "long_text"     // multi-line CommonMark Markdown
+	KindNumber       Kind = "number"        // integer or decimal number
+	KindDate         Kind = "date"          // calendar date as YYYY-MM-DD
+	KindBoolean      Kind = "boolean"       // yes or no
+	KindSingleSelect Kind = "single_select" // one of a list of choices
+	KindMultiSelect  Kind = "multi_select"  // any number of a list of choices
+	KindURL          Kind = "url"           // absolute web address
+	KindReference    Kind = "reference"     // ID of another note
+)
+
+// Field is a user-owned field definition, such as "Eye colour" or "Birthday".
+// Templates arrange fields, and notes hold one value per field.
+type Field struct {
+	ID          string  `bson:"_id,omitempty" json:"id"`
+	OwnerID     string  `bson:"owner_id" json:"owner_id"`
+	Name        string  `bson:"name" json:"name"`
+	Description string  `bson:"description,omitempty" json:"description,omitempty"`
+	Kind        Kind    `bson:"kind" json:"kind"`
+	Options     Options `bson:"options" json:"options"`
+	CreatedAt   int64   `bson:"created_at" json:"created_at"`
+	UpdatedAt   int64   `bson:"updated_at" json:"updated_at"`
+}
+
+// Options are the validation rules of a field. Only the rules that apply to
+// the kind of the field may be set (see kindRules).
+type Options struct {
+	Required    bool     `bson:"required,omitempty" json:"required,omitempty"`
+	MinLength   int      `bson:"min_length,omitempty" json:"min_length,omitempty"`
+	MaxLength   int      `bson:"max_length,omitempty" json:"max_length,omitempty"`
+	Pattern     string   `bson:"pattern,omitempty" json:"pattern,omitempty"` // must match the whole value
+	Min         *float64 `bson:"min,omitempty" json:"min,omitempty"`
+	Max         *float64 `bson:"max,omitempty" json:"max,omitempty"`
+	Integer     bool     `bson:"integer,omitempty" json:"integer,omitempty"`
+	MinDate     string   `bson:"min_date,omitempty" json:"min_date,omitempty"` // YYYY-MM-DD
+	MaxDate     string   `bson:"max_date,omitempty" json:"max_date,omitempty"` // YYYY-MM-DD
+	Choices     []string `bson:"choices,omitempty" json:"choices,omitempty"`
+	MinSelected int      `bson:"min_selected,omitempty" json:"min_selected,omitempty"`
+	MaxSelected int      `bson:"max_selected,omitempty" json:"max_selected,omitempty"`
+	Schemes     []string `bson:"schemes,omitempty" json:"schemes,omitempty"` // http and https when empty
+	// TargetTemplateID restricts reference values to notes of this template.
+	TargetTemplateID string `bson:"target_template_id,omitempty" json:"target_template_id,omitempty"`
+}
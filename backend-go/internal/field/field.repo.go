@@ -0,0 +1,31 @@
+package field
+
+import "context"
+
+// FieldRepository defines the interface for field definition database operations.
+type FieldRepository interface {
+	// CreateField inserts a new field, assigning its ID.
+	CreateField(ctx context.Context, field *Field) error
+	// UpdateField replaces a stored field with the given one.
+	// Returns ErrFieldNotFound if the field does not exist.
+	UpdateField(ctx context.Context, field *Field) error
+	// FindFieldByID retrieves a field by its ID.
+	// Returns ErrFieldNotFound if the field does not exist.
+	FindFieldByID(ctx context.Context, id string) (*Field, error)
+	// FindFieldByName retrieves the field of an owner with the given name.
+	// Returns ErrFieldNotFound if the owner has no such field.
+	FindFieldByName(ctx context.Context, ownerID, name string) (*Field, error)
+	// ListFieldsByOwner returns every field of an owner, sorted by name.
+	ListFieldsByOwner(ctx context.Context, ownerID string) ([]*Field, error)
+	// DeleteField removes a field of an owner.
+	// Returns ErrFieldNotFound if the owner has no such field.
+	DeleteField(ctx context.Context, ownerID, id string) error
+	// DeleteFieldsByOwner removes every field of an owner.
+	DeleteFieldsByOwner(ctx context.Context, ownerID string) error
+}
+
+// TemplateStore is the subset of template.TemplateRepository used to keep
+// fields that templates still place.
+type TemplateStore interface {
+	CountTemplatesByField(ctx context.Context, ownerID, fieldID string) (int64, error)
+}
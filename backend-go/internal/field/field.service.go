@@ -0,0 +1,169 @@
+package field
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+
+	v1 "github.com/AldiandyaIrsyad/author-notes/api/v1/field"
+	"github.com/AldiandyaIrsyad/author-notes/internal/account"
+)
+
+// FieldService defines the interface for managing field definitions. Every
+// method is scoped to the owner: fields of other users are reported as not found.
+type FieldService interface {
+	// CreateField creates a field of the given kind.
+	CreateField(ctx context.Context, ownerID string, req v1.CreateFieldRequest) (*Field, error)
+	// GetField returns a field of the owner.
+	GetField(ctx context.Context, ownerID, id string) (*Field, error)
+	// ListFields returns every field of the owner, sorted by name.
+	ListFields(ctx context.Context, ownerID string) ([]*Field, error)
+	// UpdateField replaces the name, description and options of a field.
+	UpdateField(ctx context.Context, ownerID, id string, req v1.UpdateFieldRequest) (*Field, error)
+	// DeleteField deletes a field of the owner. It must first be removed
+	// from the templates that place it.
+	DeleteField(ctx context.Context, ownerID, id string) error
+
+	account.ContentProvider
+}
+
+type fieldService struct {
+	repo      FieldRepository
+	templates TemplateStore
+	validator *validator.Validate
+}
+
+// NewFieldService creates a new instance of FieldService.
+func NewFieldService(repo FieldRepository, templates TemplateStore) FieldService {
+	return &fieldService{
+		repo:      repo,
+		templates: templates,
+		validator: validator.New(),
+	}
+}
+
+// CreateField validates and stores a new field.
+func (s *fieldService) CreateField(ctx context.Context, ownerID string, req v1.CreateFieldRequest) (*Field, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, ErrValidationFailed
+	}
+
+	now := time.Now().Unix()
+	field := &Field{
+		OwnerID:     ownerID,
+		Name:        strings.TrimSpace(req.Name),
+		Description: strings.TrimSpace(req.Description),
+		Kind:        Kind(req.Kind),
+		Options:     Options(req.Options),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.check(ctx, field); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.CreateField(ctx, field); err != nil {
+		return nil, err
+	}
+	return field, nil
+}
+
+// GetField returns a field if it belongs to the owner.
+func (s *fieldService) GetField(ctx context.Context, ownerID, id string) (*Field, error) {
+	field, err := s.repo.FindFieldByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if field.OwnerID != ownerID {
+		return nil, ErrFieldNotFound
+	}
+	return field, nil
+}
+
+// ListFields returns the fields of the owner.
+func (s *fieldService) ListFields(ctx context.Context, ownerID string) ([]*Field, error) {
+	return s.repo.ListFieldsByOwner(ctx, ownerID)
+}
+
+// UpdateField validates and stores the new name, description and options of a field.
+func (s *fieldService) UpdateField(ctx context.Context, ownerID, id string, req v1.UpdateFieldRequest) (*Field, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, ErrValidationFailed
+	}
+
+	field, err := s.GetField(ctx, ownerID, id)
+	if err != nil {
+		return nil, err
+	}
+	field.Name = strings.TrimSpace(req.Name)
+	field.Description = strings.TrimSpace(req.Description)
+	field.Options = Options(req.Options)
+	field.UpdatedAt = time.Now().Unix()
+	if err := s.check(ctx, field); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateField(ctx, field); err != nil {
+		return nil, err
+	}
+	return field, nil
+}
+
+// DeleteField removes a field of the owner.
+// Returns ErrFieldInUse while templates of the owner place it.
+func (s *fieldService) DeleteField(ctx context.Context, ownerID, id string) error {
+	count, err := s.templates.CountTemplatesByField(ctx, ownerID, id)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrFieldInUse
+	}
+	return s.repo.DeleteField(ctx, ownerID, id)
+}
+
+// check validates the options of a field and that its name is not used by
+// another field of the same owner.
+func (s *fieldService) check(ctx context.Context, field *Field) error {
+	if field.Name == "" {
+		return ErrValidationFailed
+	}
+	if err := ValidateOptions(field.Kind, &field.Options); err != nil {
+		return err
+	}
+
+	existing, err := s.repo.FindFieldByName(ctx, field.OwnerID, field.Name)
+	if err != nil && !errors.Is(err, ErrFieldNotFound) {
+		return err
+	}
+	if existing != nil && existing.ID != field.ID {
+		return ErrFieldNameTaken
+	}
+	return nil
+}
+
+// Name identifies fields in data exports.
+func (s *fieldService) Name() string {
+	return "fields"
+}
+
+// ExportUserContent returns every field of the user.
+func (s *fieldService) ExportUserContent(ctx context.Context, userID string) ([]account.ExportDocument, error) {
+	fields, err := s.repo.ListFieldsByOwner(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	docs := make([]account.ExportDocument, len(fields))
+	for i, field := range fields {
+		docs[i] = account.ExportDocument{ID: field.ID, Data: field}
+	}
+	return docs, nil
+}
+
+// DeleteUserContent removes every field of the user.
+func (s *fieldService) DeleteUserContent(ctx context.Context, userID string) error {
+	return s.repo.DeleteFieldsByOwner(ctx, userID)
+}
@@ -0,0 +1,294 @@
+package field
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	v1 "github.com/AldiandyaIrsyad/author-notes/api/v1/field"
+)
+
+// MockFieldRepository is a mock implementation of FieldRepository
+type MockFieldRepository struct {
+	mock.Mock
+}
+
+func (m *MockFieldRepository) CreateField(ctx context.Context, field *Field) error {
+	args := m.Called(ctx, field)
+	return args.Error(0)
+}
+
+func (m *MockFieldRepository) UpdateField(ctx context.Context, field *Field) error {
+	args := m.Called(ctx, field)
+	return args.Error(0)
+}
+
+func (m *MockFieldRepository) FindFieldByID(ctx context.Context, id string) (*Field, error) {
+	args := m.Called(ctx, id)
+	if field := args.Get(0); field != nil {
+		return field.(*Field), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockFieldRepository) FindFieldByName(ctx context.Context, ownerID, name string) (*Field, error) {
+	args := m.Called(ctx, ownerID, name)
+	if field := args.Get(0); field != nil {
+		return field.(*Field), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockFieldRepository) ListFieldsByOwner(ctx context.Context, ownerID string) ([]*Field, error) {
+	args := m.Called(ctx, ownerID)
+	if fields := args.Get(0); fields != nil {
+		return fields.([]*Field), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockFieldRepository) DeleteField(ctx context.Context, ownerID, id string) error {
+	args := m.Called(ctx, ownerID, id)
+	return args.Error(0)
+}
+
+func (m *MockFieldRepository) DeleteFieldsByOwner(ctx context.Context, ownerID string) error {
+	args := m.Called(ctx, ownerID)
+	return args.Error(0)
+}
+
+func float(f float64) *float64 {
+	return &f
+}
+
+// stubTemplateStore serves the number of templates placing each field.
+type stubTemplateStore map[string]int64
+
+func (s stubTemplateStore) CountTemplatesByField(_ context.Context, _, fieldID string) (int64, error) {
+	return s[fieldID], nil
+}
+
+var testTemplates = stubTemplateStore{"used": 1}
+
+func TestFieldService_CreateField(t *testing.T) {
+	mockRepo := new(MockFieldRepository)
+	service := NewFieldService(mockRepo, testTemplates)
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		req := v1.CreateFieldRequest{
+			Name: " Eye colour ",
+			Kind: "single_select",
+			Options: v1.FieldOptions{
+				Required: true,
+				Choices:  []string{"blue ", "green"},
+			},
+		}
+		mockRepo.On("FindFieldByName", ctx, "user123", "Eye colour").Return(nil, ErrFieldNotFound).Once()
+		mockRepo.On("CreateField", ctx, mock.AnythingOfType("*field.Field")).Return(nil).Once()
+
+		field, err := service.CreateField(ctx, "user123", req)
+
+		require.NoError(t, err)
+		assert.Equal(t, "user123", field.OwnerID)
+		assert.Equal(t, "Eye colour", field.Name)
+		assert.Equal(t, KindSingleSelect, field.Kind)
+		assert.Equal(t, []string{"blue", "green"}, field.Options.Choices)
+		assert.NotZero(t, field.CreatedAt)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Unknown Kind", func(t *testing.T) {
+		_, err := service.CreateField(ctx, "user123", v1.CreateFieldRequest{Name: "Mood", Kind: "emoji"})
+
+		assert.ErrorIs(t, err, ErrValidationFailed)
+	})
+
+	t.Run("Blank Name", func(t *testing.T) {
+		_, err := service.CreateField(ctx, "user123", v1.CreateFieldRequest{Name: "   ", Kind: "boolean"})
+
+		assert.ErrorIs(t, err, ErrValidationFailed)
+	})
+
+	t.Run("Option Not Applicable To Kind", func(t *testing.T) {
+		req := v1.CreateFieldRequest{
+			Name:    "Age",
+			Kind:    "number",
+			Options: v1.FieldOptions{MaxLength: 10},
+		}
+
+		_, err := service.CreateField(ctx, "user123", req)
+
+		assert.ErrorIs(t, err, ErrInvalidOptions)
+	})
+
+	t.Run("Select Without Choices", func(t *testing.T) {
+		_, err := service.CreateField(ctx, "user123", v1.CreateFieldRequest{Name: "Tags", Kind: "multi_select"})
+
+		assert.ErrorIs(t, err, ErrInvalidOptions)
+	})
+
+	t.Run("Name Taken", func(t *testing.T) {
+		existing := &Field{ID: "field1", OwnerID: "user123", Name: "Age", Kind: KindNumber}
+		mockRepo.On("FindFieldByName", ctx, "user123", "Age").Return(existing, nil).Once()
+
+		_, err := service.CreateField(ctx, "user123", v1.CreateFieldRequest{Name: "Age", Kind: "number"})
+
+		assert.ErrorIs(t, err, ErrFieldNameTaken)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestFieldService_GetField(t *testing.T) {
+	mockRepo := new(MockFieldRepository)
+	service := NewFieldService(mockRepo, testTemplates)
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		mockRepo.On("FindFieldByID", ctx, "field1").Return(&Field{ID: "field1", OwnerID: "user123"}, nil).Once()
+
+		field, err := service.GetField(ctx, "user123", "field1")
+
+		require.NoError(t, err)
+		assert.Equal(t, "field1", field.ID)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Other Owner", func(t *testing.T) {
+		mockRepo.On("FindFieldByID", ctx, "field1").Return(&Field{ID: "field1", OwnerID: "other"}, nil).Once()
+
+		field, err := service.GetField(ctx, "user123", "field1")
+
+		assert.Nil(t, field)
+		assert.ErrorIs(t, err, ErrFieldNotFound)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestFieldService_UpdateField(t *testing.T) {
+	mockRepo := new(MockFieldRepository)
+	service := NewFieldService(mockRepo, testTemplates)
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		stored := &Field{ID: "field1", OwnerID: "user123", Name: "Age", Kind: KindNumber}
+		mockRepo.On("FindFieldByID", ctx, "field1").Return(stored, nil).Once()
+		mockRepo.On("FindFieldByName", ctx, "user123", "Age").Return(stored, nil).Once()
+		mockRepo.On("UpdateField", ctx, stored).Return(nil).Once()
+
+		req := v1.UpdateFieldRequest{Name: "Age", Options: v1.FieldOptions{Min: float(0), Integer: true}}
+		field, err := service.UpdateField(ctx, "user123", "field1", req)
+
+		require.NoError(t, err)
+		assert.Equal(t, KindNumber, field.Kind)
+		assert.True(t, field.Options.Integer)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Invalid Options For Existing Kind", func(t *testing.T) {
+		stored := &Field{ID: "field1", OwnerID: "user123", Name: "Age", Kind: KindNumber}
+		mockRepo.On("FindFieldByID", ctx, "field1").Return(stored, nil).Once()
+
+		req := v1.UpdateFieldRequest{Name: "Age", Options: v1.FieldOptions{Min: float(10), Max: float(1)}}
+		_, err := service.UpdateField(ctx, "user123", "field1", req)
+
+		assert.ErrorIs(t, err, ErrInvalidOptions)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestFieldService_DeleteField(t *testing.T) {
+	mockRepo := new(MockFieldRepository)
+	service := NewFieldService(mockRepo, testTemplates)
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		mockRepo.On("DeleteField", ctx, "user123", "field1").Return(nil).Once()
+
+		require.NoError(t, service.DeleteField(ctx, "user123", "field1"))
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Field Used By Templates", func(t *testing.T) {
+		err := service.DeleteField(ctx, "user123", "used")
+
+		assert.ErrorIs(t, err, ErrFieldInUse)
+		mockRepo.AssertNotCalled(t, "DeleteField", ctx, "user123", "used")
+	})
+}
+
+func TestFieldService_UserContent(t *testing.T) {
+	mockRepo := new(MockFieldRepository)
+	service := NewFieldService(mockRepo, testTemplates)
+	ctx := context.Background()
+
+	t.Run("Export", func(t *testing.T) {
+		fields := []*Field{{ID: "field1"}, {ID: "field2"}}
+		mockRepo.On("ListFieldsByOwner", ctx, "user123").Return(fields, nil).Once()
+
+		docs, err := service.ExportUserContent(ctx, "user123")
+
+		require.NoError(t, err)
+		require.Len(t, docs, 2)
+		assert.Equal(t, "field1", docs[0].ID)
+		assert.Equal(t, "fields", service.Name())
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		mockRepo.On("DeleteFieldsByOwner", ctx, "user123").Return(nil).Once()
+
+		require.NoError(t, service.DeleteUserContent(ctx, "user123"))
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestField_ValidateValue(t *testing.T) {
+	tests := []struct {
+		name  string
+		field Field
+		value any
+		valid bool
+	}{
+		{"optional empty", Field{Kind: KindShortText}, "", true},
+		{"required empty", Field{Kind: KindShortText, Options: Options{Required: true}}, nil, false},
+		{"short text", Field{Kind: KindShortText}, "Alice", true},
+		{"short text with newline", Field{Kind: KindShortText}, "a\nb", false},
+		{"short text too long", Field{Kind: KindShortText, Options: Options{MaxLength: 3}}, "Alice", false},
+		{"short text pattern", Field{Kind: KindShortText, Options: Options{Pattern: `[A-Z]{3}`}}, "ABC", true},
+		{"short text partial pattern", Field{Kind: KindShortText, Options: Options{Pattern: `[A-Z]{3}`}}, "ABCD", false},
+		{"long text", Field{Kind: KindLongText}, "line one\nline two", true},
+		{"number", Field{Kind: KindNumber, Options: Options{Min: float(0)}}, 41.5, true},
+		{"number below min", Field{Kind: KindNumber, Options: Options{Min: float(0)}}, -1.0, false},
+		{"number not integer", Field{Kind: KindNumber, Options: Options{Integer: true}}, 1.5, false},
+		{"number as text", Field{Kind: KindNumber}, "42", false},
+		{"date", Field{Kind: KindDate}, "2024-02-29", true},
+		{"date invalid", Field{Kind: KindDate}, "2023-02-29", false},
+		{"date before min", Field{Kind: KindDate, Options: Options{MinDate: "2000-01-01"}}, "1999-12-31", false},
+		{"boolean", Field{Kind: KindBoolean}, false, true},
+		{"boolean as text", Field{Kind: KindBoolean}, "yes", false},
+		{"single select", Field{Kind: KindSingleSelect, Options: Options{Choices: []string{"a", "b"}}}, "b", true},
+		{"single select unknown", Field{Kind: KindSingleSelect, Options: Options{Choices: []string{"a", "b"}}}, "c", false},
+		{"multi select", Field{Kind: KindMultiSelect, Options: Options{Choices: []string{"a", "b"}}}, []any{"a", "b"}, true},
+		{"multi select duplicate", Field{Kind: KindMultiSelect, Options: Options{Choices: []string{"a", "b"}}}, []any{"a", "a"}, false},
+		{"multi select too many", Field{Kind: KindMultiSelect, Options: Options{Choices: []string{"a", "b"}, MaxSelected: 1}}, []any{"a", "b"}, false},
+		{"url", Field{Kind: KindURL}, "https://example.com/page", true},
+		{"url relative", Field{Kind: KindURL}, "/page", false},
+		{"url scheme", Field{Kind: KindURL}, "ftp://example.com", false},
+		{"reference", Field{Kind: KindReference}, "0b7d6c2e-4d0f-4f43-9a56-8f1d1f0c2a11", true},
+		{"reference as number", Field{Kind: KindReference}, 7.0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.field.ValidateValue(tt.value)
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrInvalidValue)
+			}
+		})
+	}
+}
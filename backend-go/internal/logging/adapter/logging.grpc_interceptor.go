@@ -0,0 +1,59 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	app_logging "github.com/AldiandyaIrsyad/author-notes/internal/logging"
+)
+
+// UnaryServerInterceptor is the gRPC counterpart of the RequestID, AccessLog
+// and Recovery middleware: it assigns every call a request ID, reusing a
+// well-formed x-request-id metadata value and echoing it in the response
+// header, logs one record per call and turns panics into Internal errors.
+func UnaryServerInterceptor(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	header := strings.ToLower(RequestIDHeader)
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		start := time.Now()
+
+		requestID := ""
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get(header); len(values) > 0 {
+				requestID = values[0]
+			}
+		}
+		if !validRequestID(requestID) {
+			requestID = uuid.NewString()
+		}
+		ctx = app_logging.ContextWithRequestID(ctx, requestID)
+		_ = grpc.SetHeader(ctx, metadata.Pairs(header, requestID))
+
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				logger.ErrorContext(ctx, "panic while handling request", "panic", recovered, "method", info.FullMethod)
+				resp, err = nil, status.Error(codes.Internal, "Internal server error")
+			}
+
+			code := status.Code(err)
+			level := slog.LevelInfo
+			switch code {
+			case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unavailable:
+				level = slog.LevelError
+			}
+			logger.LogAttrs(ctx, level, "rpc",
+				slog.String("method", info.FullMethod),
+				slog.String("code", code.String()),
+				slog.Duration("duration", time.Since(start)),
+			)
+		}()
+		return handler(ctx, req)
+	}
+}
@@ -0,0 +1,69 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestLogger(t *testing.T) {
+	t.Run("Adds Request ID From Context", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := New(&buf, slog.LevelInfo).With("component", "test")
+		ctx := ContextWithRequestID(context.Background(), "req-123")
+
+		logger.InfoContext(ctx, "hello", "user_id", "user1")
+
+		var record map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+		assert.Equal(t, "hello", record["msg"])
+		assert.Equal(t, "req-123", record["request_id"])
+		assert.Equal(t, "test", record["component"])
+		assert.Equal(t, "user1", record["user_id"])
+	})
+
+	t.Run("Adds Trace Context", func(t *testing.T) {
+		var buf bytes.Buffer
+		spanContext := trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID:    trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+			SpanID:     trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+			TraceFlags: trace.FlagsSampled,
+		})
+		ctx := trace.ContextWithSpanContext(context.Background(), spanContext)
+
+		New(&buf, slog.LevelInfo).InfoContext(ctx, "hello")
+
+		var record map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", record["trace_id"])
+		assert.Equal(t, "00f067aa0ba902b7", record["span_id"])
+	})
+
+	t.Run("Without Request", func(t *testing.T) {
+		var buf bytes.Buffer
+		New(&buf, slog.LevelInfo).Info("hello")
+
+		var record map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+		assert.NotContains(t, record, "request_id")
+	})
+
+	t.Run("Respects Level", func(t *testing.T) {
+		var buf bytes.Buffer
+		New(&buf, ParseLevel("warn")).Info("hidden")
+		assert.Empty(t, buf.String())
+	})
+}
+
+func TestParseLevel(t *testing.T) {
+	assert.Equal(t, slog.LevelDebug, ParseLevel("debug"))
+	assert.Equal(t, slog.LevelError, ParseLevel(" ERROR "))
+	assert.Equal(t, slog.LevelInfo, ParseLevel(""))
+	assert.Equal(t, slog.LevelInfo, ParseLevel("verbose"))
+}
@@ -0,0 +1,103 @@
+package markdown
+
+import (
+	"bytes"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// Resolver returns the URL of the note a wiki link refers to by title, or
+// false if there is no such note.
+type Resolver func(target string) (href string, ok bool)
+
+// commonMark parses CommonMark with wiki links. Raw HTML is not rendered.
+var commonMark = goldmark.New(
+	goldmark.WithParserOptions(parser.WithInlineParsers(
+		// The link parser has priority 200.
+		util.Prioritized(wikiLinkParser{}, 199),
+	)),
+)
+
+// policy sanitizes rendered HTML, keeping what users may format notes with.
+var policy = func() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^wiki-link( missing)?$`)).OnElements("a", "span")
+	return p
+}()
+
+// Document is a parsed Markdown value.
+type Document struct {
+	source []byte
+	root   ast.Node
+}
+
+// Parse parses CommonMark source. Every source is valid Markdown.
+func Parse(source string) *Document {
+	src := []byte(source)
+	return &Document{source: src, root: commonMark.Parser().Parse(text.NewReader(src))}
+}
+
+// WikiLinks returns the targets of the wiki links of the document, in order
+// of appearance and without repeats.
+func (d *Document) WikiLinks() []string {
+	var targets []string
+	_ = ast.Walk(d.root, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if link, ok := n.(*wikiLink); ok && entering && !slices.Contains(targets, link.Target) {
+			targets = append(targets, link.Target)
+		}
+		return ast.WalkContinue, nil
+	})
+	return targets
+}
+
+// WordCount returns the number of words of the text of the document, leaving
+// out markup, code blocks and raw HTML.
+func (d *Document) WordCount() int {
+	var buf strings.Builder
+	_ = ast.Walk(d.root, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n := n.(type) {
+		case *ast.CodeBlock, *ast.FencedCodeBlock, *ast.HTMLBlock, *ast.RawHTML:
+			return ast.WalkSkipChildren, nil
+		case *wikiLink:
+			buf.WriteString(n.Label)
+		case *ast.Text:
+			buf.Write(n.Segment.Value(d.source))
+			if n.SoftLineBreak() || n.HardLineBreak() {
+				buf.WriteByte('\n')
+			}
+		default:
+			if n.Type() == ast.TypeBlock {
+				buf.WriteByte('\n')
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+	return len(strings.Fields(buf.String()))
+}
+
+// HTML renders the document to sanitized HTML. Wiki links that resolve
+// become links, the others plain text.
+func (d *Document) HTML(resolve Resolver) (string, error) {
+	r := renderer.NewRenderer(renderer.WithNodeRenderers(
+		util.Prioritized(html.NewRenderer(), 1000),
+		util.Prioritized(&wikiLinkRenderer{resolve: resolve}, 500),
+	))
+	var buf bytes.Buffer
+	if err := r.Render(&buf, d.source, d.root); err != nil {
+		return "", err
+	}
+	return policy.Sanitize(buf.String()), nil
+}
@@ -0,0 +1,55 @@
+package markdown
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocument(t *testing.T) {
+	resolve := func(target string) (string, bool) {
+		if target == "Alice Liddell" {
+			return "/notes/alice", true
+		}
+		return "", false
+	}
+
+	t.Run("Wiki Links", func(t *testing.T) {
+		doc := Parse("Met [[Alice  Liddell]] and [[Alice Liddell|her]] at [[The Harbor]].\n\n" +
+			"`[[Not A Link]]` and [a link](https://example.com) and [[ ]].\n\n" +
+			"    [[Code Block]]\n")
+
+		assert.Equal(t, []string{"Alice Liddell", "The Harbor"}, doc.WikiLinks())
+	})
+
+	t.Run("Word Count", func(t *testing.T) {
+		doc := Parse("# The *first* chapter\n\nIt was a **dark**-ish night,\nsaid [[Alice Liddell|she]].\n\n" +
+			"```\nignored code\n```\n\n<div>raw html</div>\n\n- one\n- two words\n")
+
+		assert.Equal(t, 3+7+1+2, doc.WordCount())
+		assert.Zero(t, Parse("").WordCount())
+	})
+
+	t.Run("HTML", func(t *testing.T) {
+		doc := Parse("Hello **[[Alice Liddell]]** and [[Bob]].")
+
+		html, err := doc.HTML(resolve)
+
+		require.NoError(t, err)
+		assert.Equal(t, `<p>Hello <strong><a class="wiki-link" href="/notes/alice" rel="nofollow">Alice Liddell</a></strong>`+
+			` and <span class="wiki-link missing">Bob</span>.</p>`+"\n", html)
+	})
+
+	t.Run("HTML Is Sanitized", func(t *testing.T) {
+		doc := Parse("<script>alert(1)</script>\n\n[click](javascript:alert(1)) <img src=x onerror=alert(1)>\n\n[[<b>Bob</b>]]")
+
+		html, err := doc.HTML(resolve)
+
+		require.NoError(t, err)
+		assert.NotContains(t, html, "<script")
+		assert.NotContains(t, html, "javascript:")
+		assert.NotContains(t, html, "onerror")
+		assert.Contains(t, html, "&lt;b&gt;Bob&lt;/b&gt;")
+	})
+}
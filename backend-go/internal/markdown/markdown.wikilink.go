@@ -0,0 +1,98 @@
+package markdown
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// kindWikiLink is the AST kind of wiki links.
+var kindWikiLink = ast.NewNodeKind("WikiLink")
+
+// wikiLink is a [[target]] or [[target|label]] link to another note by title.
+type wikiLink struct {
+	ast.BaseInline
+	Target string
+	Label  string
+}
+
+func (n *wikiLink) Kind() ast.NodeKind {
+	return kindWikiLink
+}
+
+func (n *wikiLink) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"Target": n.Target, "Label": n.Label}, nil)
+}
+
+// wikiLinkParser parses wiki links. It must run before the link parser, which
+// also triggers on '['.
+type wikiLinkParser struct{}
+
+func (wikiLinkParser) Trigger() []byte {
+	return []byte{'['}
+}
+
+func (wikiLinkParser) Parse(_ ast.Node, block text.Reader, _ parser.Context) ast.Node {
+	line, _ := block.PeekLine()
+	if !bytes.HasPrefix(line, []byte("[[")) {
+		return nil
+	}
+	end := bytes.Index(line[2:], []byte("]]"))
+	if end < 0 {
+		return nil
+	}
+	inner := string(line[2 : 2+end])
+	if strings.ContainsAny(inner, "[]") {
+		return nil
+	}
+	target, label, _ := strings.Cut(inner, "|")
+	target = strings.Join(strings.Fields(target), " ")
+	label = strings.TrimSpace(label)
+	if target == "" {
+		return nil
+	}
+	if label == "" {
+		label = target
+	}
+
+	block.Advance(end + 4)
+	return &wikiLink{Target: target, Label: label}
+}
+
+// wikiLinkRenderer renders resolved wiki links as links to their note and the
+// others as plain text marked as missing.
+type wikiLinkRenderer struct {
+	resolve Resolver
+}
+
+func (r *wikiLinkRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(kindWikiLink, r.render)
+}
+
+func (r *wikiLinkRenderer) render(w util.BufWriter, _ []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	n := node.(*wikiLink)
+	href, ok := "", false
+	if r.resolve != nil {
+		href, ok = r.resolve(n.Target)
+	}
+	if ok {
+		_, _ = w.WriteString(`<a class="wiki-link" href="`)
+		_, _ = w.Write(util.EscapeHTML(util.URLEscape([]byte(href), true)))
+		_, _ = w.WriteString(`">`)
+		_, _ = w.Write(util.EscapeHTML([]byte(n.Label)))
+		_, _ = w.WriteString(`</a>`)
+	} else {
+		_, _ = w.WriteString(`<span class="wiki-link missing">`)
+		_, _ = w.Write(util.EscapeHTML([]byte(n.Label)))
+		_, _ = w.WriteString(`</span>`)
+	}
+	return ast.WalkSkipChildren, nil
+}
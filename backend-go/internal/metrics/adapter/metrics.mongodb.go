@@ -0,0 +1,65 @@
+package metrics
+
+import (
+	"context"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+// NewMongoCommandMonitor returns a command monitor observing the latency of
+// every MongoDB command by command name, collection and outcome. Set on the
+// client options, it covers the operations of all repository adapters.
+func NewMongoCommandMonitor(reg prometheus.Registerer) *event.CommandMonitor {
+	duration := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "mongodb_command_duration_seconds",
+		Help:    "MongoDB command latency by command, collection and outcome.",
+		Buckets: []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"command", "collection", "outcome"})
+	reg.MustRegister(duration)
+
+	// The collection is only part of the started event; remember it by request
+	// ID until the command finishes.
+	var collections sync.Map
+
+	finished := func(e event.CommandFinishedEvent, outcome string) {
+		collection := ""
+		if v, ok := collections.LoadAndDelete(e.RequestID); ok {
+			collection = v.(string)
+		}
+		duration.WithLabelValues(e.CommandName, collection, outcome).Observe(e.Duration.Seconds())
+	}
+
+	return &event.CommandMonitor{
+		Started: func(_ context.Context, e *event.CommandStartedEvent) {
+			collections.Store(e.RequestID, commandCollection(e))
+		},
+		Succeeded: func(_ context.Context, e *event.CommandSucceededEvent) {
+			finished(e.CommandFinishedEvent, "success")
+		},
+		Failed: func(_ context.Context, e *event.CommandFailedEvent) {
+			finished(e.CommandFinishedEvent, "failure")
+		},
+	}
+}
+
+// commandCollection returns the collection a command operates on. For CRUD
+// commands it is the value of the command's first element, e.g. {find: "users"}.
+func commandCollection(e *event.CommandStartedEvent) string {
+	switch e.CommandName {
+	case "find", "insert", "update", "delete", "findAndModify", "aggregate",
+		"count", "distinct", "createIndexes", "listIndexes", "dropIndexes":
+	default:
+		return ""
+	}
+	elem, err := e.Command.IndexErr(0)
+	if err != nil {
+		return ""
+	}
+	collection, ok := elem.Value().StringValueOK()
+	if !ok {
+		return ""
+	}
+	return collection
+}
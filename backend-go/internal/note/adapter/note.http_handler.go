@@ -0,0 +1,299 @@
+package note
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	v1 "github.com/AldiandyaIrsyad/author-notes/api/v1/note"
+	app_auth "github.com/AldiandyaIrsyad/author-notes/internal/auth"
+	auth_adapter "github.com/AldiandyaIrsyad/author-notes/internal/auth/adapter"
+	app_field "github.com/AldiandyaIrsyad/author-notes/internal/field"
+	app_note "github.com/AldiandyaIrsyad/author-notes/internal/note"
+	"github.com/AldiandyaIrsyad/author-notes/internal/pagination"
+	app_project "github.com/AldiandyaIrsyad/author-notes/internal/project"
+	app_template "github.com/AldiandyaIrsyad/author-notes/internal/template"
+)
+
+type NoteHTTPHandler struct {
+	service app_note.NoteService
+	logger  *slog.Logger
+}
+
+func NewNoteHTTPHandler(service app_note.NoteService, logger *slog.Logger) *NoteHTTPHandler {
+	return &NoteHTTPHandler{service: service, logger: logger}
+}
+
+// internalError logs err and responds with a generic 500 error that does not leak it.
+func (h *NoteHTTPHandler) internalError(c *gin.Context, message string, err error) {
+	h.logger.ErrorContext(c.Request.Context(), message, "route", c.FullPath(), "error", err)
+	c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+}
+
+// RegisterRoutes registers the note routes on rg, which must be authenticated.
+func (h *NoteHTTPHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	read := auth_adapter.RequirePermission(app_auth.PermContentRead)
+	write := auth_adapter.RequirePermission(app_auth.PermContentWrite)
+
+	rg.GET("/projects/:id/notes", read, h.ListNotes)
+	rg.POST("/projects/:id/notes", write, h.CreateNote)
+
+	notesGroup := rg.Group("/notes")
+	notesGroup.POST("/upgrade", write, h.UpgradeNotes)
+	notesGroup.GET("/:id", read, h.GetNote)
+	notesGroup.GET("/:id/backlinks", read, h.ListBacklinks)
+	notesGroup.GET("/:id/render", read, h.RenderNote)
+	notesGroup.PUT("/:id", write, h.UpdateNote)
+	notesGroup.DELETE("/:id", write, h.DeleteNote)
+}
+
+// ListNotes handles the request for the notes of a project.
+// @Summary List the notes of a project
+// @Description Returns one page of the notes of a project of the caller, newest first, optionally filtered by title, template or the value of a field.
+// @Tags notes
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Param q query string false "Part of the title"
+// @Param template_id query string false "Only notes of this template"
+// @Param field_id query string false "Only notes whose value of this field equals value"
+// @Param value query string false "Value of field_id; for multi select fields, one of the choices"
+// @Param cursor query string false "Cursor returned as next_cursor by the previous page"
+// @Param limit query int false "Page size (1-100, default 20)"
+// @Success 200 {object} note.NotePage "Notes"
+// @Failure 400 {object} map[string]string "Invalid filter or cursor"
+// @Failure 401 {object} map[string]string "Missing or invalid token"
+// @Failure 403 {object} map[string]string "Missing permission"
+// @Failure 404 {object} map[string]string "Project not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/projects/{id}/notes [get]
+func (h *NoteHTTPHandler) ListNotes(c *gin.Context) {
+	var req v1.ListNotesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query parameters: " + err.Error()})
+		return
+	}
+	claims, _ := app_auth.ClaimsFromContext(c.Request.Context())
+
+	page, err := h.service.ListNotes(c.Request.Context(), claims.UserID, c.Param("id"), req)
+	if err != nil {
+		h.respondError(c, "Failed to list notes", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// CreateNote handles the request to create a note.
+// @Summary Create a note
+// @Description Fills in a template of a project of the caller. Values are keyed by field ID and must be valid values of their field; fields left out start with the default of their slot. Reference values must be IDs of notes of the same project, of the target template of the field if it has one.
+// @Tags notes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Param note body v1.CreateNoteRequest true "Template, title and values"
+// @Success 201 {object} note.Note "Note created"
+// @Failure 400 {object} map[string]string "Validation error or invalid value"
+// @Failure 401 {object} map[string]string "Missing or invalid token"
+// @Failure 403 {object} map[string]string "Missing permission"
+// @Failure 404 {object} map[string]string "Project or template not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/projects/{id}/notes [post]
+func (h *NoteHTTPHandler) CreateNote(c *gin.Context) {
+	var req v1.CreateNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	claims, _ := app_auth.ClaimsFromContext(c.Request.Context())
+
+	note, err := h.service.CreateNote(c.Request.Context(), claims.UserID, c.Param("id"), req)
+	if err != nil {
+		h.respondError(c, "Failed to create note", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, note)
+}
+
+// GetNote handles the request for a single note.
+// @Summary Get a note
+// @Tags notes
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Note ID"
+// @Success 200 {object} note.Note "Note"
+// @Failure 401 {object} map[string]string "Missing or invalid token"
+// @Failure 403 {object} map[string]string "Missing permission"
+// @Failure 404 {object} map[string]string "Note not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/notes/{id} [get]
+func (h *NoteHTTPHandler) GetNote(c *gin.Context) {
+	claims, _ := app_auth.ClaimsFromContext(c.Request.Context())
+
+	note, err := h.service.GetNote(c.Request.Context(), claims.UserID, c.Param("id"))
+	if err != nil {
+		h.respondError(c, "Failed to get note", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, note)
+}
+
+// RenderNote handles the request for the rendered long text values of a note.
+// @Summary Render the long text values of a note
+// @Description Renders the long text values of a note, stored as CommonMark Markdown, to sanitized HTML, along with their word counts. Raw HTML is left out. [[Title]] and [[Title|label]] wiki links become links to the note of the project with that title, or plain text marked as missing.
+// @Tags notes
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Note ID"
+// @Success 200 {object} note.RenderedNote "Rendered values by field ID"
+// @Failure 401 {object} map[string]string "Missing or invalid token"
+// @Failure 403 {object} map[string]string "Missing permission"
+// @Failure 404 {object} map[string]string "Note, its template or its template version not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/notes/{id}/render [get]
+func (h *NoteHTTPHandler) RenderNote(c *gin.Context) {
+	claims, _ := app_auth.ClaimsFromContext(c.Request.Context())
+
+	rendered, err := h.service.RenderNote(c.Request.Context(), claims.UserID, c.Param("id"))
+	if err != nil {
+		h.respondError(c, "Failed to render note", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rendered)
+}
+
+// ListBacklinks handles the request for the notes that refer to a note.
+// @Summary List the backlinks of a note
+// @Description Returns the notes that refer to a note through a reference field or a wiki link in a long text field, sorted by title, once per referring field.
+// @Tags notes
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Note ID"
+// @Success 200 {array} note.Backlink "Notes referring to the note"
+// @Failure 401 {object} map[string]string "Missing or invalid token"
+// @Failure 403 {object} map[string]string "Missing permission"
+// @Failure 404 {object} map[string]string "Note not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/notes/{id}/backlinks [get]
+func (h *NoteHTTPHandler) ListBacklinks(c *gin.Context) {
+	claims, _ := app_auth.ClaimsFromContext(c.Request.Context())
+
+	backlinks, err := h.service.ListBacklinks(c.Request.Context(), claims.UserID, c.Param("id"))
+	if err != nil {
+		h.respondError(c, "Failed to list backlinks", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, backlinks)
+}
+
+// UpdateNote handles the request to update a note.
+// @Summary Update a note
+// @Description Replaces the title and values of a note. Values are checked against the template version the note is pinned to; upgrade the note to use a newer version. Reference values must be IDs of notes of the same project; dangling references to deleted notes may be kept as they are.
+// @Tags notes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Note ID"
+// @Param note body v1.UpdateNoteRequest true "New title and values"
+// @Success 200 {object} note.Note "Updated note"
+// @Failure 400 {object} map[string]string "Validation error or invalid value"
+// @Failure 401 {object} map[string]string "Missing or invalid token"
+// @Failure 403 {object} map[string]string "Missing permission"
+// @Failure 404 {object} map[string]string "Note, its template or its template version not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/notes/{id} [put]
+func (h *NoteHTTPHandler) UpdateNote(c *gin.Context) {
+	var req v1.UpdateNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	claims, _ := app_auth.ClaimsFromContext(c.Request.Context())
+
+	note, err := h.service.UpdateNote(c.Request.Context(), claims.UserID, c.Param("id"), req)
+	if err != nil {
+		h.respondError(c, "Failed to update note", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, note)
+}
+
+// UpgradeNotes handles the request to move notes to the latest version of their template.
+// @Summary Upgrade notes to the latest template version
+// @Description Moves the outdated notes of a template, or the listed ones, to its latest version. Values follow the field renames recorded by the versions in between, and values of fields the latest version no longer places move to the archived section of the note. Notes with values that do not fit the latest version are reported as conflicts and left alone, unless force is set: then the offending values are archived too. With dry_run, nothing is saved.
+// @Tags notes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param upgrade body v1.UpgradeNotesRequest true "Template and upgrade options"
+// @Success 200 {object} note.UpgradeReport "Upgraded notes and conflicts"
+// @Failure 400 {object} map[string]string "Validation error"
+// @Failure 401 {object} map[string]string "Missing or invalid token"
+// @Failure 403 {object} map[string]string "Missing permission"
+// @Failure 404 {object} map[string]string "Template not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/notes/upgrade [post]
+func (h *NoteHTTPHandler) UpgradeNotes(c *gin.Context) {
+	var req v1.UpgradeNotesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	claims, _ := app_auth.ClaimsFromContext(c.Request.Context())
+
+	report, err := h.service.UpgradeNotes(c.Request.Context(), claims.UserID, req)
+	if err != nil {
+		h.respondError(c, "Failed to upgrade notes", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// DeleteNote handles the request to delete a note.
+// @Summary Delete a note
+// @Description Deletes a note. References to it from other notes are kept but marked as dangling.
+// @Tags notes
+// @Security BearerAuth
+// @Param id path string true "Note ID"
+// @Success 204 "Note deleted"
+// @Failure 401 {object} map[string]string "Missing or invalid token"
+// @Failure 403 {object} map[string]string "Missing permission"
+// @Failure 404 {object} map[string]string "Note not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/notes/{id} [delete]
+func (h *NoteHTTPHandler) DeleteNote(c *gin.Context) {
+	claims, _ := app_auth.ClaimsFromContext(c.Request.Context())
+
+	if err := h.service.DeleteNote(c.Request.Context(), claims.UserID, c.Param("id")); err != nil {
+		h.respondError(c, "Failed to delete note", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// respondError maps the errors of the note service to HTTP responses.
+func (h *NoteHTTPHandler) respondError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, app_note.ErrValidationFailed),
+		errors.Is(err, app_field.ErrInvalidValue),
+		errors.Is(err, pagination.ErrInvalidCursor):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, app_note.ErrNoteNotFound),
+		errors.Is(err, app_template.ErrTemplateNotFound),
+		errors.Is(err, app_template.ErrVersionNotFound),
+		errors.Is(err, app_project.ErrProjectNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		h.internalError(c, message, err)
+	}
+}
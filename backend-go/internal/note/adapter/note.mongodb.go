@@ -0,0 +1,250 @@
+package note
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	app_note "github.com/AldiandyaIrsyad/author-notes/internal/note"
+	"github.com/AldiandyaIrsyad/author-notes/internal/pagination"
+)
+
+// mongoNoteRepository implements the NoteRepository interface using MongoDB.
+type mongoNoteRepository struct {
+	collection *mongo.Collection
+}
+
+// titleCollation compares titles ignoring case. FindNoteByTitle queries with
+// it so that the title index, built with it too, serves the query.
+var titleCollation = &options.Collation{Locale: "en", Strength: 2}
+
+// NewMongoNoteRepository creates a new instance of mongoNoteRepository.
+func NewMongoNoteRepository(ctx context.Context, db *mongo.Database) (app_note.NoteRepository, error) {
+	collection := db.Collection("notes")
+	_, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "owner_id", Value: 1}, {Key: "project_id", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}},
+		{
+			Keys:    bson.D{{Key: "owner_id", Value: 1}, {Key: "project_id", Value: 1}, {Key: "title", Value: 1}},
+			Options: options.Index().SetCollation(titleCollation),
+		},
+		{Keys: bson.D{{Key: "links.note_id", Value: 1}, {Key: "owner_id", Value: 1}}},
+		{Keys: bson.D{{Key: "template_id", Value: 1}, {Key: "owner_id", Value: 1}}},
+		{Keys: bson.D{{Key: "wiki_targets", Value: 1}, {Key: "project_id", Value: 1}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &mongoNoteRepository{collection: collection}, nil
+}
+
+// CreateNote inserts a new note.
+func (r *mongoNoteRepository) CreateNote(ctx context.Context, note *app_note.Note) error {
+	if note.ID == "" {
+		note.ID = uuid.NewString()
+	}
+	_, err := r.collection.InsertOne(ctx, note)
+	return err
+}
+
+// UpdateNote replaces a stored note with the given one.
+func (r *mongoNoteRepository) UpdateNote(ctx context.Context, note *app_note.Note) error {
+	result, err := r.collection.ReplaceOne(ctx, bson.M{"_id": note.ID, "owner_id": note.OwnerID}, note)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return app_note.ErrNoteNotFound
+	}
+	return nil
+}
+
+// FindNoteByID retrieves a note by its ID.
+func (r *mongoNoteRepository) FindNoteByID(ctx context.Context, id string) (*app_note.Note, error) {
+	var note app_note.Note
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&note)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, app_note.ErrNoteNotFound
+		}
+		return nil, err
+	}
+	return &note, nil
+}
+
+// FindNoteByTitle retrieves the oldest note of a project of an owner with a
+// title, ignoring case.
+func (r *mongoNoteRepository) FindNoteByTitle(ctx context.Context, ownerID, projectID, title string) (*app_note.Note, error) {
+	filter := bson.M{
+		"owner_id":   ownerID,
+		"project_id": projectID,
+		"title":      title,
+	}
+	opts := options.FindOne().
+		SetCollation(titleCollation).
+		SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}})
+	var note app_note.Note
+	err := r.collection.FindOne(ctx, filter, opts).Decode(&note)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, app_note.ErrNoteNotFound
+		}
+		return nil, err
+	}
+	return &note, nil
+}
+
+// ListNotes returns one page of notes matching filter, newest first.
+func (r *mongoNoteRepository) ListNotes(ctx context.Context, filter app_note.NoteFilter) (*app_note.NotePage, error) {
+	conditions := []bson.M{{"owner_id": filter.OwnerID}}
+	if filter.ProjectID != "" {
+		conditions = append(conditions, bson.M{"project_id": filter.ProjectID})
+	}
+	if filter.Search != "" {
+		conditions = append(conditions, bson.M{"title": primitive.Regex{Pattern: regexp.QuoteMeta(filter.Search), Options: "i"}})
+	}
+	if filter.TemplateID != "" {
+		conditions = append(conditions, bson.M{"template_id": filter.TemplateID})
+	}
+	if filter.FieldID != "" {
+		// Equality also matches one element of a multi select value.
+		conditions = append(conditions, bson.M{"values." + filter.FieldID: filter.Value})
+	}
+	if filter.Cursor != "" {
+		cursor, err := pagination.DecodeCursor(filter.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, bson.M{"$or": []bson.M{
+			{"created_at": bson.M{"$lt": cursor.CreatedAt}},
+			{"created_at": cursor.CreatedAt, "_id": bson.M{"$lt": cursor.ID}},
+		}})
+	}
+
+	// Fetch one extra document to find out whether another page exists.
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(int64(filter.Limit) + 1)
+	cursor, err := r.collection.Find(ctx, bson.M{"$and": conditions}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	notes := []*app_note.Note{}
+	if err := cursor.All(ctx, &notes); err != nil {
+		return nil, err
+	}
+
+	page := &app_note.NotePage{Notes: notes}
+	if len(notes) > filter.Limit {
+		page.Notes = notes[:filter.Limit]
+		last := page.Notes[filter.Limit-1]
+		page.NextCursor = pagination.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}.Encode()
+	}
+	return page, nil
+}
+
+// ListNotesByWikiTarget returns the notes of a project of an owner with a wiki
+// link to title, ignoring case. Targets are stored in lower case.
+func (r *mongoNoteRepository) ListNotesByWikiTarget(ctx context.Context, ownerID, projectID, title string) ([]*app_note.Note, error) {
+	filter := bson.M{"owner_id": ownerID, "project_id": projectID, "wiki_targets": strings.ToLower(title)}
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	notes := []*app_note.Note{}
+	if err := cursor.All(ctx, &notes); err != nil {
+		return nil, err
+	}
+	return notes, nil
+}
+
+// ListOutdatedNotes returns the notes of an owner pinned to a version of a
+// template older than version.
+func (r *mongoNoteRepository) ListOutdatedNotes(ctx context.Context, ownerID, templateID string, version int) ([]*app_note.Note, error) {
+	filter := bson.M{"owner_id": ownerID, "template_id": templateID, "template_version": bson.M{"$lt": version}}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	notes := []*app_note.Note{}
+	if err := cursor.All(ctx, &notes); err != nil {
+		return nil, err
+	}
+	return notes, nil
+}
+
+// ListOwnerIDs returns the IDs of every user with notes.
+func (r *mongoNoteRepository) ListOwnerIDs(ctx context.Context) ([]string, error) {
+	values, err := r.collection.Distinct(ctx, "owner_id", bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	ownerIDs := make([]string, 0, len(values))
+	for _, value := range values {
+		if ownerID, ok := value.(string); ok {
+			ownerIDs = append(ownerIDs, ownerID)
+		}
+	}
+	return ownerIDs, nil
+}
+
+// CountNotesByTemplate returns the number of notes of an owner filled in from
+// a template.
+func (r *mongoNoteRepository) CountNotesByTemplate(ctx context.Context, ownerID, templateID string) (int64, error) {
+	return r.collection.CountDocuments(ctx, bson.M{"owner_id": ownerID, "template_id": templateID})
+}
+
+// ListBacklinks returns the notes of an owner with a link to the note noteID,
+// sorted by title.
+func (r *mongoNoteRepository) ListBacklinks(ctx context.Context, ownerID, noteID string) ([]*app_note.Note, error) {
+	filter := bson.M{"owner_id": ownerID, "links.note_id": noteID}
+	opts := options.Find().SetSort(bson.D{{Key: "title", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	notes := []*app_note.Note{}
+	if err := cursor.All(ctx, &notes); err != nil {
+		return nil, err
+	}
+	return notes, nil
+}
+
+// DeleteNote removes a note of an owner.
+func (r *mongoNoteRepository) DeleteNote(ctx context.Context, ownerID, id string) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id, "owner_id": ownerID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return app_note.ErrNoteNotFound
+	}
+	return nil
+}
+
+// DeleteNotesByOwner removes every note of an owner.
+func (r *mongoNoteRepository) DeleteNotesByOwner(ctx context.Context, ownerID string) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"owner_id": ownerID})
+	return err
+}
+
+// DeleteNotesByProject removes every note of a project.
+func (r *mongoNoteRepository) DeleteNotesByProject(ctx context.Context, projectID string) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"project_id": projectID})
+	return err
+}
@@ -0,0 +1,8 @@
+package note
+
+import "errors"
+
+var (
+	ErrNoteNotFound     = errors.New("note not found")
+	ErrValidationFailed = errors.New("input validation failed")
+)
@@ -0,0 +1,16 @@
+package note
+
+import "context"
+
+// Indexer is kept up to date with the notes, such as a search index. Notes
+// are handed over once stored and removed once deleted.
+type Indexer interface {
+	// IndexNote adds a note or replaces its previous state.
+	IndexNote(ctx context.Context, note *Note) error
+	// RemoveNote removes a note of an owner.
+	RemoveNote(ctx context.Context, ownerID, id string) error
+	// RemoveProjectNotes removes every note of a project.
+	RemoveProjectNotes(ctx context.Context, projectID string) error
+	// RemoveOwnerNotes removes every note of an owner.
+	RemoveOwnerNotes(ctx context.Context, ownerID string) error
+}
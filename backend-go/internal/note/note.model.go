@@ -0,0 +1,83 @@
+package note
+
+// Note is a user-owned entry filled in from a template, such as a character
+// or a location. It belongs to the project of its template and is pinned to
+// the version of the template it was created with until it is upgraded.
+type Note struct {
+	ID              string         `bson:"_id,omitempty" json:"id"`
+	OwnerID         string         `bson:"owner_id" json:"owner_id"`
+	ProjectID       string         `bson:"project_id" json:"project_id"`
+	TemplateID      string         `bson:"template_id" json:"template_id"`
+	TemplateVersion int            `bson:"template_version" json:"template_version"`
+	Title           string         `bson:"title" json:"title"`
+	Values          map[string]any `bson:"values" json:"values"`                               // by field ID, without empty values
+	Archived        map[string]any `bson:"archived,omitempty" json:"archived,omitempty"`       // values moved out by upgrades, by field ID
+	Links           []Link         `bson:"links,omitempty" json:"links,omitempty"`             // reference values and wiki links, kept for backlinks
+	WordCounts      map[string]int `bson:"word_counts,omitempty" json:"word_counts,omitempty"` // of long text values, by field ID
+	WikiTargets     []string       `bson:"wiki_targets,omitempty" json:"-"`                    // targets of wiki links in lower case, resolved again when titles change
+	CreatedAt       int64          `bson:"created_at" json:"created_at"`
+	UpdatedAt       int64          `bson:"updated_at" json:"updated_at"`
+}
+
+// Link is a reference of a note to another note, through the value of a
+// reference field or a [[wiki link]] in a long text value. A link is dangling
+// once the note it refers to is deleted.
+type Link struct {
+	FieldID  string `bson:"field_id" json:"field_id"`
+	NoteID   string `bson:"note_id" json:"note_id"`
+	Dangling bool   `bson:"dangling,omitempty" json:"dangling,omitempty"`
+}
+
+// Backlink is a note that refers to another note through a field.
+type Backlink struct {
+	NoteID     string `json:"note_id"`
+	Title      string `json:"title"`
+	TemplateID string `json:"template_id"`
+	FieldID    string `json:"field_id"`
+}
+
+// RenderedNote holds the long text values of a note, stored as CommonMark
+// Markdown, rendered to sanitized HTML.
+type RenderedNote struct {
+	NoteID     string            `json:"note_id"`
+	HTML       map[string]string `json:"html"`        // by field ID
+	WordCounts map[string]int    `json:"word_counts"` // by field ID
+}
+
+// UpgradeReport is the outcome of upgrading the notes of a template to its
+// latest version.
+type UpgradeReport struct {
+	TemplateVersion int        `json:"template_version"`
+	DryRun          bool       `json:"dry_run"`
+	Upgraded        []string   `json:"upgraded"` // IDs of the notes moved to TemplateVersion
+	Conflicts       []Conflict `json:"conflicts"`
+}
+
+// Conflict is a value of a note that does not fit the latest version of its
+// template, such as a missing required value or a value of a renamed field
+// that the new field rejects.
+type Conflict struct {
+	NoteID  string `json:"note_id"`
+	FieldID string `json:"field_id"`
+	Problem string `json:"problem"`
+}
+
+// NoteFilter narrows down the notes returned by NoteRepository.ListNotes.
+// Zero values disable the corresponding filter.
+type NoteFilter struct {
+	OwnerID    string
+	ProjectID  string
+	Search     string // part of the title, case insensitive
+	TemplateID string
+	FieldID    string
+	Value      any // value of FieldID, typed like the field
+	Cursor     string
+	Limit      int
+}
+
+// NotePage is one page of notes, newest first.
+// NextCursor is empty when there are no more notes.
+type NotePage struct {
+	Notes      []*Note `json:"notes"`
+	NextCursor string  `json:"next_cursor,omitempty"`
+}
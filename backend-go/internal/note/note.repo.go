@@ -0,0 +1,68 @@
+package note
+
+import (
+	"context"
+
+	"github.com/AldiandyaIrsyad/author-notes/internal/field"
+	"github.com/AldiandyaIrsyad/author-notes/internal/project"
+	"github.com/AldiandyaIrsyad/author-notes/internal/template"
+)
+
+// NoteRepository defines the interface for note database operations.
+type NoteRepository interface {
+	// CreateNote inserts a new note, assigning its ID.
+	CreateNote(ctx context.Context, note *Note) error
+	// UpdateNote replaces a stored note with the given one.
+	// Returns ErrNoteNotFound if the note does not exist.
+	UpdateNote(ctx context.Context, note *Note) error
+	// FindNoteByID retrieves a note by its ID.
+	// Returns ErrNoteNotFound if the note does not exist.
+	FindNoteByID(ctx context.Context, id string) (*Note, error)
+	// FindNoteByTitle retrieves the oldest note of a project of an owner with
+	// a title, ignoring case.
+	// Returns ErrNoteNotFound if there is no such note.
+	FindNoteByTitle(ctx context.Context, ownerID, projectID, title string) (*Note, error)
+	// ListNotes returns one page of notes matching filter, newest first.
+	// Returns pagination.ErrInvalidCursor if filter.Cursor was not produced by a previous call.
+	ListNotes(ctx context.Context, filter NoteFilter) (*NotePage, error)
+	// ListNotesByWikiTarget returns the notes of a project of an owner with a
+	// wiki link to title, ignoring case.
+	ListNotesByWikiTarget(ctx context.Context, ownerID, projectID, title string) ([]*Note, error)
+	// ListOutdatedNotes returns the notes of an owner pinned to a version of
+	// a template older than version.
+	ListOutdatedNotes(ctx context.Context, ownerID, templateID string, version int) ([]*Note, error)
+	// ListOwnerIDs returns the IDs of every user with notes.
+	ListOwnerIDs(ctx context.Context) ([]string, error)
+	// CountNotesByTemplate returns the number of notes of an owner filled in
+	// from a template.
+	CountNotesByTemplate(ctx context.Context, ownerID, templateID string) (int64, error)
+	// ListBacklinks returns the notes of an owner with a link to the note
+	// noteID, sorted by title.
+	ListBacklinks(ctx context.Context, ownerID, noteID string) ([]*Note, error)
+	// DeleteNote removes a note of an owner.
+	// Returns ErrNoteNotFound if the owner has no such note.
+	DeleteNote(ctx context.Context, ownerID, id string) error
+	// DeleteNotesByOwner removes every note of an owner.
+	DeleteNotesByOwner(ctx context.Context, ownerID string) error
+	// DeleteNotesByProject removes every note of a project.
+	DeleteNotesByProject(ctx context.Context, projectID string) error
+}
+
+// TemplateStore is the subset of template.TemplateRepository used to fill in
+// templates and upgrade notes between their versions.
+type TemplateStore interface {
+	FindTemplateByID(ctx context.Context, id string) (*template.Template, error)
+	FindVersion(ctx context.Context, templateID string, version int) (*template.Version, error)
+	ListVersions(ctx context.Context, templateID string) ([]*template.Version, error)
+}
+
+// FieldStore is the subset of field.FieldRepository used to validate values.
+type FieldStore interface {
+	FindFieldByID(ctx context.Context, id string) (*field.Field, error)
+}
+
+// ProjectStore is the subset of project.ProjectRepository used to check the
+// project notes are created in and listed from.
+type ProjectStore interface {
+	FindProjectByID(ctx context.Context, id string) (*project.Project, error)
+}
@@ -0,0 +1,820 @@
+package note
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+
+	v1 "github.com/AldiandyaIrsyad/author-notes/api/v1/note"
+	"github.com/AldiandyaIrsyad/author-notes/internal/account"
+	"github.com/AldiandyaIrsyad/author-notes/internal/field"
+	"github.com/AldiandyaIrsyad/author-notes/internal/markdown"
+	"github.com/AldiandyaIrsyad/author-notes/internal/pagination"
+	"github.com/AldiandyaIrsyad/author-notes/internal/project"
+	"github.com/AldiandyaIrsyad/author-notes/internal/template"
+)
+
+// NoteService defines the interface for managing notes. Every method is
+// scoped to the owner: notes, templates and projects of other users are
+// reported as not found.
+type NoteService interface {
+	// CreateNote fills in a template of a project of the owner.
+	CreateNote(ctx context.Context, ownerID, projectID string, req v1.CreateNoteRequest) (*Note, error)
+	// GetNote returns a note of the owner.
+	GetNote(ctx context.Context, ownerID, id string) (*Note, error)
+	// ListNotes returns one page of the notes of a project of the owner, newest first.
+	ListNotes(ctx context.Context, ownerID, projectID string, req v1.ListNotesRequest) (*NotePage, error)
+	// UpdateNote replaces the title and values of a note.
+	UpdateNote(ctx context.Context, ownerID, id string, req v1.UpdateNoteRequest) (*Note, error)
+	// UpgradeNotes moves notes of a template to its latest version.
+	UpgradeNotes(ctx context.Context, ownerID string, req v1.UpgradeNotesRequest) (*UpgradeReport, error)
+	// RenderNote renders the long text values of a note of the owner to HTML.
+	RenderNote(ctx context.Context, ownerID, id string) (*RenderedNote, error)
+	// ListBacklinks returns the notes of the owner that refer to a note of the
+	// owner, sorted by title.
+	ListBacklinks(ctx context.Context, ownerID, id string) ([]Backlink, error)
+	// DeleteNote deletes a note of the owner, marks the references to it as
+	// dangling and resolves the wiki links to its title again.
+	DeleteNote(ctx context.Context, ownerID, id string) error
+
+	account.ContentProvider
+	project.ContentRemover
+}
+
+type noteService struct {
+	repo      NoteRepository
+	templates TemplateStore
+	fields    FieldStore
+	projects  ProjectStore
+	indexers  []Indexer
+	logger    *slog.Logger
+	validator *validator.Validate
+}
+
+// NewNoteService creates a new instance of NoteService. Every change to the
+// notes is passed on to indexers; their failures are logged with logger.
+func NewNoteService(repo NoteRepository, templates TemplateStore, fields FieldStore, projects ProjectStore, logger *slog.Logger, indexers ...Indexer) NoteService {
+	return &noteService{
+		repo:      repo,
+		templates: templates,
+		fields:    fields,
+		projects:  projects,
+		indexers:  indexers,
+		logger:    logger.With("component", "note"),
+		validator: validator.New(),
+	}
+}
+
+// CreateNote validates and stores a new note of the current template version.
+func (s *noteService) CreateNote(ctx context.Context, ownerID, projectID string, req v1.CreateNoteRequest) (*Note, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, ErrValidationFailed
+	}
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		return nil, ErrValidationFailed
+	}
+
+	if err := s.checkProject(ctx, ownerID, projectID); err != nil {
+		return nil, err
+	}
+	tpl, err := s.template(ctx, ownerID, req.TemplateID)
+	if err != nil {
+		return nil, err
+	}
+	if tpl.ProjectID != projectID {
+		return nil, template.ErrTemplateNotFound
+	}
+	values, fields, err := s.values(ctx, ownerID, projectID, tpl.Rows, req.Values, nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().Unix()
+	note := &Note{
+		OwnerID:         ownerID,
+		ProjectID:       projectID,
+		TemplateID:      tpl.ID,
+		TemplateVersion: tpl.Version,
+		Title:           title,
+		Values:          values,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	if err := s.index(ctx, note, fields, nil); err != nil {
+		return nil, err
+	}
+	if err := s.repo.CreateNote(ctx, note); err != nil {
+		return nil, err
+	}
+	s.indexNote(ctx, note)
+	if err := s.relink(ctx, note, note.Title); err != nil {
+		return nil, err
+	}
+	return note, nil
+}
+
+// GetNote returns a note if it belongs to the owner.
+func (s *noteService) GetNote(ctx context.Context, ownerID, id string) (*Note, error) {
+	note, err := s.repo.FindNoteByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if note.OwnerID != ownerID {
+		return nil, ErrNoteNotFound
+	}
+	return note, nil
+}
+
+// defaultNotePageSize is used by ListNotes when the request does not specify a limit.
+const defaultNotePageSize = 20
+
+// ListNotes returns one page of the notes of a project of the owner matching
+// the request.
+func (s *noteService) ListNotes(ctx context.Context, ownerID, projectID string, req v1.ListNotesRequest) (*NotePage, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, ErrValidationFailed
+	}
+	if err := s.checkProject(ctx, ownerID, projectID); err != nil {
+		return nil, err
+	}
+	if req.Cursor != "" {
+		if _, err := pagination.DecodeCursor(req.Cursor); err != nil {
+			return nil, err
+		}
+	}
+
+	filter := NoteFilter{
+		OwnerID:    ownerID,
+		ProjectID:  projectID,
+		Search:     strings.TrimSpace(req.Query),
+		TemplateID: req.TemplateID,
+		Cursor:     req.Cursor,
+		Limit:      req.Limit,
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultNotePageSize
+	}
+	if req.FieldID != "" {
+		value, err := s.filterValue(ctx, ownerID, req.FieldID, req.Value)
+		if err != nil {
+			return nil, err
+		}
+		filter.FieldID = req.FieldID
+		filter.Value = value
+	}
+	return s.repo.ListNotes(ctx, filter)
+}
+
+// filterValue converts a value from the query string to the type that the
+// field stores.
+func (s *noteService) filterValue(ctx context.Context, ownerID, fieldID, raw string) (any, error) {
+	f, err := s.fields.FindFieldByID(ctx, fieldID)
+	if errors.Is(err, field.ErrFieldNotFound) || err == nil && f.OwnerID != ownerID {
+		return nil, ErrValidationFailed
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch f.Kind {
+	case field.KindNumber:
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, ErrValidationFailed
+		}
+		return n, nil
+	case field.KindBoolean:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, ErrValidationFailed
+		}
+		return b, nil
+	}
+	return raw, nil
+}
+
+// UpdateNote validates and stores the new title and values of a note against
+// the template version it is pinned to.
+func (s *noteService) UpdateNote(ctx context.Context, ownerID, id string, req v1.UpdateNoteRequest) (*Note, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, ErrValidationFailed
+	}
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		return nil, ErrValidationFailed
+	}
+
+	note, err := s.GetNote(ctx, ownerID, id)
+	if err != nil {
+		return nil, err
+	}
+	version, err := s.pinned(ctx, ownerID, note)
+	if err != nil {
+		return nil, err
+	}
+	values, fields, err := s.values(ctx, ownerID, note.ProjectID, version.Rows, req.Values, note.Links, false)
+	if err != nil {
+		return nil, err
+	}
+
+	oldTitle := note.Title
+	note.Title = title
+	note.Values = values
+	if err := s.index(ctx, note, fields, note.Links); err != nil {
+		return nil, err
+	}
+	note.UpdatedAt = time.Now().Unix()
+	if err := s.repo.UpdateNote(ctx, note); err != nil {
+		return nil, err
+	}
+	s.indexNote(ctx, note)
+	if !strings.EqualFold(oldTitle, title) {
+		if err := s.relink(ctx, note, oldTitle, title); err != nil {
+			return nil, err
+		}
+	}
+	return note, nil
+}
+
+// RenderNote renders the long text values of a note of the owner, resolving
+// wiki links to the notes of its project.
+func (s *noteService) RenderNote(ctx context.Context, ownerID, id string) (*RenderedNote, error) {
+	note, err := s.GetNote(ctx, ownerID, id)
+	if err != nil {
+		return nil, err
+	}
+	version, err := s.pinned(ctx, ownerID, note)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := s.slotFields(ctx, ownerID, version.Rows)
+	if err != nil {
+		return nil, err
+	}
+
+	rendered := &RenderedNote{NoteID: note.ID, HTML: make(map[string]string), WordCounts: make(map[string]int)}
+	for _, sf := range fields {
+		value, ok := note.Values[sf.slot.FieldID].(string)
+		if sf.field.Kind != field.KindLongText || !ok {
+			continue
+		}
+		doc := markdown.Parse(value)
+		targets, err := s.resolve(ctx, ownerID, note.ProjectID, doc.WikiLinks())
+		if err != nil {
+			return nil, err
+		}
+		html, err := doc.HTML(func(target string) (string, bool) {
+			if n, ok := targets[target]; ok {
+				return "/notes/" + n.ID, true
+			}
+			return "", false
+		})
+		if err != nil {
+			return nil, err
+		}
+		rendered.HTML[sf.slot.FieldID] = html
+		rendered.WordCounts[sf.slot.FieldID] = doc.WordCount()
+	}
+	return rendered, nil
+}
+
+// ListBacklinks returns the notes of the owner with a link to a note of the
+// owner, once per referring field.
+func (s *noteService) ListBacklinks(ctx context.Context, ownerID, id string) ([]Backlink, error) {
+	if _, err := s.GetNote(ctx, ownerID, id); err != nil {
+		return nil, err
+	}
+	notes, err := s.repo.ListBacklinks(ctx, ownerID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	backlinks := []Backlink{}
+	for _, note := range notes {
+		for _, link := range note.Links {
+			if link.NoteID == id {
+				backlinks = append(backlinks, Backlink{
+					NoteID:     note.ID,
+					Title:      note.Title,
+					TemplateID: note.TemplateID,
+					FieldID:    link.FieldID,
+				})
+			}
+		}
+	}
+	return backlinks, nil
+}
+
+// DeleteNote removes a note of the owner. The links of the notes that refer
+// to it are kept but marked as dangling, so their values stay visible until
+// they are changed. Wiki links to its title are resolved again, to another
+// note with the same title if there is one.
+func (s *noteService) DeleteNote(ctx context.Context, ownerID, id string) error {
+	deleted, err := s.GetNote(ctx, ownerID, id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.DeleteNote(ctx, ownerID, id); err != nil {
+		return err
+	}
+	s.updateIndexers(ctx, func(indexer Indexer) error {
+		return indexer.RemoveNote(ctx, ownerID, id)
+	}, "note_id", id)
+	notes, err := s.repo.ListBacklinks(ctx, ownerID, id)
+	if err != nil {
+		return err
+	}
+	for _, note := range notes {
+		for i := range note.Links {
+			if note.Links[i].NoteID == id {
+				note.Links[i].Dangling = true
+			}
+		}
+		if err := s.repo.UpdateNote(ctx, note); err != nil {
+			return err
+		}
+	}
+	return s.relink(ctx, deleted, deleted.Title)
+}
+
+// indexNote passes a stored note on to the indexers.
+func (s *noteService) indexNote(ctx context.Context, note *Note) {
+	s.updateIndexers(ctx, func(indexer Indexer) error {
+		return indexer.IndexNote(ctx, note)
+	}, "note_id", note.ID)
+}
+
+// updateIndexers applies update to every indexer. The notes are already
+// stored, so a failure is only logged and does not fail the request; a
+// reindex brings the indexer back in line.
+func (s *noteService) updateIndexers(ctx context.Context, update func(Indexer) error, args ...any) {
+	for _, indexer := range s.indexers {
+		if err := update(indexer); err != nil {
+			s.logger.ErrorContext(ctx, "updating note indexer failed", append(args, "error", err)...)
+		}
+	}
+}
+
+// checkProject checks that a project exists and belongs to the owner.
+func (s *noteService) checkProject(ctx context.Context, ownerID, projectID string) error {
+	p, err := s.projects.FindProjectByID(ctx, projectID)
+	if err != nil {
+		return err
+	}
+	if p.OwnerID != ownerID {
+		return project.ErrProjectNotFound
+	}
+	return nil
+}
+
+// template returns a template if it belongs to the owner.
+func (s *noteService) template(ctx context.Context, ownerID, id string) (*template.Template, error) {
+	tpl, err := s.templates.FindTemplateByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if tpl.OwnerID != ownerID {
+		return nil, template.ErrTemplateNotFound
+	}
+	return tpl, nil
+}
+
+// pinned returns the template version a note is pinned to.
+func (s *noteService) pinned(ctx context.Context, ownerID string, note *Note) (*template.Version, error) {
+	tpl, err := s.template(ctx, ownerID, note.TemplateID)
+	if err != nil {
+		return nil, err
+	}
+	if tpl.Version == note.TemplateVersion {
+		return tpl.Snapshot(nil), nil
+	}
+	return s.templates.FindVersion(ctx, tpl.ID, note.TemplateVersion)
+}
+
+// slotField is a field as placed in a template.
+type slotField struct {
+	slot  template.Slot
+	field field.Field // required if the slot or the field definition says so
+}
+
+// slotFields returns the fields placed in rows. Fields deleted after they were
+// placed no longer hold values and are left out.
+func (s *noteService) slotFields(ctx context.Context, ownerID string, rows []template.Row) ([]slotField, error) {
+	var fields []slotField
+	for _, row := range rows {
+		for _, slot := range row.Slots {
+			f, err := s.fields.FindFieldByID(ctx, slot.FieldID)
+			if errors.Is(err, field.ErrFieldNotFound) || err == nil && f.OwnerID != ownerID {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			definition := *f
+			definition.Options.Required = f.Options.Required || slot.Required
+			fields = append(fields, slotField{slot: slot, field: definition})
+		}
+	}
+	return fields, nil
+}
+
+// values checks values against the slots of rows and returns them without
+// empty values, along with the fields placed in rows. Slots missing from
+// values start with their default when withDefaults is set. Reference values
+// must refer to notes of the project, except for those left dangling by
+// previous, which are kept as they are.
+func (s *noteService) values(ctx context.Context, ownerID, projectID string, rows []template.Row, values map[string]any, previous []Link, withDefaults bool) (map[string]any, []slotField, error) {
+	placed := make(map[string]bool)
+	for _, row := range rows {
+		for _, slot := range row.Slots {
+			placed[slot.FieldID] = true
+		}
+	}
+	for fieldID := range values {
+		if !placed[fieldID] {
+			return nil, nil, fmt.Errorf("%w: field %s is not part of the template", field.ErrInvalidValue, fieldID)
+		}
+	}
+
+	fields, err := s.slotFields(ctx, ownerID, rows)
+	if err != nil {
+		return nil, nil, err
+	}
+	result := make(map[string]any)
+	for _, sf := range fields {
+		value, ok := values[sf.slot.FieldID]
+		if !ok && withDefaults {
+			value = sf.slot.Default
+		}
+		if err := sf.field.ValidateValue(value); err != nil {
+			return nil, nil, err
+		}
+		if field.IsEmptyValue(value) {
+			continue
+		}
+		if sf.field.Kind == field.KindReference && !dangling(previous, sf.slot.FieldID, value.(string)) {
+			if err := s.checkReference(ctx, ownerID, projectID, &sf.field, value.(string)); err != nil {
+				return nil, nil, err
+			}
+		}
+		result[sf.slot.FieldID] = value
+	}
+	return result, fields, nil
+}
+
+// checkReference checks that a reference value refers to a note of the
+// project, of the target template of the field if it has one.
+func (s *noteService) checkReference(ctx context.Context, ownerID, projectID string, f *field.Field, noteID string) error {
+	target, err := s.repo.FindNoteByID(ctx, noteID)
+	if errors.Is(err, ErrNoteNotFound) || err == nil && (target.OwnerID != ownerID || target.ProjectID != projectID) {
+		return fmt.Errorf("%w: %s refers to a note that does not exist", field.ErrInvalidValue, f.Name)
+	}
+	if err != nil {
+		return err
+	}
+	if f.Options.TargetTemplateID != "" && target.TemplateID != f.Options.TargetTemplateID {
+		return fmt.Errorf("%w: %s must refer to a note of its target template", field.ErrInvalidValue, f.Name)
+	}
+	return nil
+}
+
+// index derives the links and word counts of a note from its values of
+// fields. Reference links that are dangling in previous stay dangling; wiki
+// links only count while their title matches a note of the project.
+func (s *noteService) index(ctx context.Context, note *Note, fields []slotField, previous []Link) error {
+	var links []Link
+	var targets []string
+	counts := make(map[string]int)
+	for _, sf := range fields {
+		fieldID := sf.slot.FieldID
+		value, ok := note.Values[fieldID].(string)
+		if !ok {
+			continue
+		}
+		switch sf.field.Kind {
+		case field.KindReference:
+			links = append(links, Link{FieldID: fieldID, NoteID: value, Dangling: dangling(previous, fieldID, value)})
+		case field.KindLongText:
+			doc := markdown.Parse(value)
+			counts[fieldID] = doc.WordCount()
+			resolved, err := s.resolve(ctx, note.OwnerID, note.ProjectID, doc.WikiLinks())
+			if err != nil {
+				return err
+			}
+			for _, target := range doc.WikiLinks() {
+				if lower := strings.ToLower(target); !slices.Contains(targets, lower) {
+					targets = append(targets, lower)
+				}
+				n, ok := resolved[target]
+				if !ok || slices.Contains(links, Link{FieldID: fieldID, NoteID: n.ID}) {
+					continue
+				}
+				links = append(links, Link{FieldID: fieldID, NoteID: n.ID})
+			}
+		}
+	}
+	if len(counts) == 0 {
+		counts = nil
+	}
+	note.Links = links
+	note.WikiTargets = targets
+	note.WordCounts = counts
+	return nil
+}
+
+// relink resolves the wiki links to titles again in the other notes of the
+// project of note, once note was created, renamed to or from one of them, or
+// deleted.
+func (s *noteService) relink(ctx context.Context, note *Note, titles ...string) error {
+	seen := map[string]bool{note.ID: true}
+	for _, title := range titles {
+		notes, err := s.repo.ListNotesByWikiTarget(ctx, note.OwnerID, note.ProjectID, title)
+		if err != nil {
+			return err
+		}
+		for _, n := range notes {
+			if seen[n.ID] {
+				continue
+			}
+			seen[n.ID] = true
+			version, err := s.pinned(ctx, n.OwnerID, n)
+			if err != nil {
+				return err
+			}
+			fields, err := s.slotFields(ctx, n.OwnerID, version.Rows)
+			if err != nil {
+				return err
+			}
+			if err := s.index(ctx, n, fields, n.Links); err != nil {
+				return err
+			}
+			if err := s.repo.UpdateNote(ctx, n); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// resolve returns the notes of a project that wiki link targets refer to by
+// title, by target. Targets without a note are left out.
+func (s *noteService) resolve(ctx context.Context, ownerID, projectID string, targets []string) (map[string]*Note, error) {
+	notes := make(map[string]*Note)
+	for _, target := range targets {
+		n, err := s.repo.FindNoteByTitle(ctx, ownerID, projectID, target)
+		if errors.Is(err, ErrNoteNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		notes[target] = n
+	}
+	return notes, nil
+}
+
+// dangling reports whether links has a dangling link from fieldID to noteID.
+func dangling(links []Link, fieldID, noteID string) bool {
+	return slices.ContainsFunc(links, func(l Link) bool {
+		return l.Dangling && l.FieldID == fieldID && l.NoteID == noteID
+	})
+}
+
+// UpgradeNotes moves the outdated notes of a template to its latest version.
+// Values follow the renames recorded by the versions in between, and values
+// of fields the latest version no longer places are archived.
+func (s *noteService) UpgradeNotes(ctx context.Context, ownerID string, req v1.UpgradeNotesRequest) (*UpgradeReport, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, ErrValidationFailed
+	}
+
+	tpl, err := s.template(ctx, ownerID, req.TemplateID)
+	if err != nil {
+		return nil, err
+	}
+	versions, err := s.templates.ListVersions(ctx, tpl.ID)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := s.slotFields(ctx, ownerID, tpl.Rows)
+	if err != nil {
+		return nil, err
+	}
+	notes, err := s.repo.ListOutdatedNotes(ctx, ownerID, tpl.ID, tpl.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &UpgradeReport{
+		TemplateVersion: tpl.Version,
+		DryRun:          req.DryRun,
+		Upgraded:        []string{},
+		Conflicts:       []Conflict{},
+	}
+	for _, note := range notes {
+		if len(req.NoteIDs) > 0 && !slices.Contains(req.NoteIDs, note.ID) {
+			continue
+		}
+		values, archived, conflicts := upgrade(note, tpl, versions, fields, req.Force)
+		report.Conflicts = append(report.Conflicts, conflicts...)
+		if len(conflicts) > 0 && !req.Force {
+			continue
+		}
+		report.Upgraded = append(report.Upgraded, note.ID)
+		if req.DryRun {
+			continue
+		}
+
+		note.Values = values
+		note.Archived = archived
+		if err := s.index(ctx, note, fields, note.Links); err != nil {
+			return nil, err
+		}
+		note.TemplateVersion = tpl.Version
+		note.UpdatedAt = time.Now().Unix()
+		if err := s.repo.UpdateNote(ctx, note); err != nil {
+			return nil, err
+		}
+		s.indexNote(ctx, note)
+	}
+	return report, nil
+}
+
+// upgrade returns the values and archived values of a note moved to the
+// latest version of its template, and the values that do not fit it. With
+// force, values that do not fit are archived.
+func upgrade(note *Note, latest *template.Template, versions []*template.Version, fields []slotField, force bool) (map[string]any, map[string]any, []Conflict) {
+	values := maps.Clone(note.Values)
+	if values == nil {
+		values = make(map[string]any)
+	}
+	archived := maps.Clone(note.Archived)
+	if archived == nil {
+		archived = make(map[string]any)
+	}
+
+	for _, v := range versions {
+		if v.Version <= note.TemplateVersion || v.Version > latest.Version {
+			continue
+		}
+		for _, from := range slices.Sorted(maps.Keys(v.Renames)) {
+			value, ok := values[from]
+			if !ok {
+				continue
+			}
+			delete(values, from)
+			if _, taken := values[v.Renames[from]]; taken {
+				archived[from] = value
+				continue
+			}
+			values[v.Renames[from]] = value
+		}
+	}
+
+	placed := make(map[string]bool)
+	for _, fieldID := range latest.FieldIDs() {
+		placed[fieldID] = true
+	}
+	for fieldID, value := range values {
+		if !placed[fieldID] {
+			archived[fieldID] = value
+			delete(values, fieldID)
+		}
+	}
+
+	var conflicts []Conflict
+	for _, sf := range fields {
+		value := values[sf.slot.FieldID]
+		if err := sf.field.ValidateValue(value); err != nil {
+			conflicts = append(conflicts, Conflict{NoteID: note.ID, FieldID: sf.slot.FieldID, Problem: err.Error()})
+			if force && !field.IsEmptyValue(value) {
+				archived[sf.slot.FieldID] = value
+				delete(values, sf.slot.FieldID)
+			}
+		}
+	}
+
+	if len(archived) == 0 {
+		archived = nil
+	}
+	return values, archived, conflicts
+}
+
+// Name identifies notes in data exports.
+func (s *noteService) Name() string {
+	return "notes"
+}
+
+// ExportUserContent returns every note of the user.
+func (s *noteService) ExportUserContent(ctx context.Context, userID string) ([]account.ExportDocument, error) {
+	var docs []account.ExportDocument
+	filter := NoteFilter{OwnerID: userID, Limit: 100}
+	for {
+		page, err := s.repo.ListNotes(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		for _, note := range page.Notes {
+			markdown, err := s.markdown(ctx, note)
+			if err != nil {
+				return nil, err
+			}
+			docs = append(docs, account.ExportDocument{ID: note.ID, Data: note, Markdown: markdown})
+		}
+		if page.NextCursor == "" {
+			return docs, nil
+		}
+		filter.Cursor = page.NextCursor
+	}
+}
+
+// markdown renders a note for data exports: its title, then one section per
+// value in the order of its template, titled by the name of its field.
+func (s *noteService) markdown(ctx context.Context, note *Note) (string, error) {
+	var order []string
+	version, err := s.pinned(ctx, note.OwnerID, note)
+	switch {
+	case err == nil:
+		order = version.FieldIDs()
+	case !errors.Is(err, template.ErrTemplateNotFound) && !errors.Is(err, template.ErrVersionNotFound):
+		return "", err
+	}
+	for _, fieldID := range slices.Sorted(maps.Keys(note.Values)) {
+		if !slices.Contains(order, fieldID) {
+			order = append(order, fieldID)
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n", note.Title)
+	for _, fieldID := range order {
+		value, ok := note.Values[fieldID]
+		if !ok {
+			continue
+		}
+		name := fieldID
+		f, err := s.fields.FindFieldByID(ctx, fieldID)
+		if err != nil && !errors.Is(err, field.ErrFieldNotFound) {
+			return "", err
+		}
+		if err == nil && f.OwnerID == note.OwnerID {
+			name = f.Name
+		}
+		fmt.Fprintf(&b, "\n## %s\n\n%s\n", name, valueMarkdown(value))
+	}
+	return b.String(), nil
+}
+
+// valueMarkdown renders a value as Markdown. Long text values already are.
+func valueMarkdown(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case bool:
+		if v {
+			return "Yes"
+		}
+		return "No"
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	if items, ok := field.ListValue(value); ok {
+		lines := make([]string, len(items))
+		for i, item := range items {
+			lines[i] = fmt.Sprintf("- %v", item)
+		}
+		return strings.Join(lines, "\n")
+	}
+	return fmt.Sprint(value)
+}
+
+// DeleteUserContent removes every note of the user.
+func (s *noteService) DeleteUserContent(ctx context.Context, userID string) error {
+	if err := s.repo.DeleteNotesByOwner(ctx, userID); err != nil {
+		return err
+	}
+	s.updateIndexers(ctx, func(indexer Indexer) error {
+		return indexer.RemoveOwnerNotes(ctx, userID)
+	}, "user_id", userID)
+	return nil
+}
+
+// DeleteProjectContent removes every note of the project.
+func (s *noteService) DeleteProjectContent(ctx context.Context, projectID string) error {
+	if err := s.repo.DeleteNotesByProject(ctx, projectID); err != nil {
+		return err
+	}
+	s.updateIndexers(ctx, func(indexer Indexer) error {
+		return indexer.RemoveProjectNotes(ctx, projectID)
+	}, "project_id", projectID)
+	return nil
+}
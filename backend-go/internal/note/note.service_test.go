@@ -0,0 +1,833 @@
+package note
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	v1 "github.com/AldiandyaIrsyad/author-notes/api/v1/note"
+	"github.com/AldiandyaIrsyad/author-notes/internal/field"
+	"github.com/AldiandyaIrsyad/author-notes/internal/pagination"
+	"github.com/AldiandyaIrsyad/author-notes/internal/project"
+	"github.com/AldiandyaIrsyad/author-notes/internal/template"
+)
+
+// MockNoteRepository is a mock implementation of NoteRepository
+type MockNoteRepository struct {
+	mock.Mock
+}
+
+func (m *MockNoteRepository) CreateNote(ctx context.Context, note *Note) error {
+	args := m.Called(ctx, note)
+	return args.Error(0)
+}
+
+func (m *MockNoteRepository) UpdateNote(ctx context.Context, note *Note) error {
+	args := m.Called(ctx, note)
+	return args.Error(0)
+}
+
+func (m *MockNoteRepository) FindNoteByID(ctx context.Context, id string) (*Note, error) {
+	args := m.Called(ctx, id)
+	if note := args.Get(0); note != nil {
+		return note.(*Note), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockNoteRepository) FindNoteByTitle(ctx context.Context, ownerID, projectID, title string) (*Note, error) {
+	args := m.Called(ctx, ownerID, projectID, title)
+	if note := args.Get(0); note != nil {
+		return note.(*Note), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockNoteRepository) ListNotes(ctx context.Context, filter NoteFilter) (*NotePage, error) {
+	args := m.Called(ctx, filter)
+	if page := args.Get(0); page != nil {
+		return page.(*NotePage), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockNoteRepository) ListNotesByWikiTarget(ctx context.Context, ownerID, projectID, title string) ([]*Note, error) {
+	args := m.Called(ctx, ownerID, projectID, title)
+	if notes := args.Get(0); notes != nil {
+		return notes.([]*Note), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockNoteRepository) ListOutdatedNotes(ctx context.Context, ownerID, templateID string, version int) ([]*Note, error) {
+	args := m.Called(ctx, ownerID, templateID, version)
+	if notes := args.Get(0); notes != nil {
+		return notes.([]*Note), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockNoteRepository) ListOwnerIDs(ctx context.Context) ([]string, error) {
+	args := m.Called(ctx)
+	if ownerIDs := args.Get(0); ownerIDs != nil {
+		return ownerIDs.([]string), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockNoteRepository) CountNotesByTemplate(ctx context.Context, ownerID, templateID string) (int64, error) {
+	args := m.Called(ctx, ownerID, templateID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockNoteRepository) ListBacklinks(ctx context.Context, ownerID, noteID string) ([]*Note, error) {
+	args := m.Called(ctx, ownerID, noteID)
+	if notes := args.Get(0); notes != nil {
+		return notes.([]*Note), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockNoteRepository) DeleteNote(ctx context.Context, ownerID, id string) error {
+	args := m.Called(ctx, ownerID, id)
+	return args.Error(0)
+}
+
+func (m *MockNoteRepository) DeleteNotesByOwner(ctx context.Context, ownerID string) error {
+	args := m.Called(ctx, ownerID)
+	return args.Error(0)
+}
+
+func (m *MockNoteRepository) DeleteNotesByProject(ctx context.Context, projectID string) error {
+	args := m.Called(ctx, projectID)
+	return args.Error(0)
+}
+
+// stubTemplateStore serves a fixed set of templates and versions.
+type stubTemplateStore struct {
+	templates map[string]*template.Template
+	versions  []*template.Version
+}
+
+func (s stubTemplateStore) FindTemplateByID(_ context.Context, id string) (*template.Template, error) {
+	if tpl, ok := s.templates[id]; ok {
+		return tpl, nil
+	}
+	return nil, template.ErrTemplateNotFound
+}
+
+func (s stubTemplateStore) FindVersion(_ context.Context, templateID string, version int) (*template.Version, error) {
+	for _, v := range s.versions {
+		if v.TemplateID == templateID && v.Version == version {
+			return v, nil
+		}
+	}
+	return nil, template.ErrVersionNotFound
+}
+
+func (s stubTemplateStore) ListVersions(_ context.Context, templateID string) ([]*template.Version, error) {
+	var versions []*template.Version
+	for _, v := range s.versions {
+		if v.TemplateID == templateID {
+			versions = append(versions, v)
+		}
+	}
+	return versions, nil
+}
+
+// stubFieldStore serves a fixed set of fields.
+type stubFieldStore map[string]*field.Field
+
+func (s stubFieldStore) FindFieldByID(_ context.Context, id string) (*field.Field, error) {
+	if f, ok := s[id]; ok {
+		return f, nil
+	}
+	return nil, field.ErrFieldNotFound
+}
+
+// stubProjectStore serves a fixed set of projects.
+type stubProjectStore map[string]*project.Project
+
+func (s stubProjectStore) FindProjectByID(_ context.Context, id string) (*project.Project, error) {
+	if p, ok := s[id]; ok {
+		return p, nil
+	}
+	return nil, project.ErrProjectNotFound
+}
+
+var testProjects = stubProjectStore{
+	"novel":  {ID: "novel", OwnerID: "user123", Title: "Novel"},
+	"sequel": {ID: "sequel", OwnerID: "user123", Title: "Sequel"},
+	"theirs": {ID: "theirs", OwnerID: "someone", Title: "Theirs"},
+}
+
+var testFields = stubFieldStore{
+	"name": {ID: "name", OwnerID: "user123", Name: "Name", Kind: field.KindShortText},
+	"age":  {ID: "age", OwnerID: "user123", Name: "Age", Kind: field.KindNumber, Options: field.Options{Integer: true}},
+	"tags": {ID: "tags", OwnerID: "user123", Name: "Tags", Kind: field.KindMultiSelect, Options: field.Options{Choices: []string{"hero", "villain"}}},
+	"dead": {ID: "dead", OwnerID: "user123", Name: "Dead", Kind: field.KindBoolean},
+	"pov":  {ID: "pov", OwnerID: "user123", Name: "Point of view", Kind: field.KindReference, Options: field.Options{TargetTemplateID: "character"}},
+	"at":   {ID: "at", OwnerID: "user123", Name: "Location", Kind: field.KindReference},
+	"text": {ID: "text", OwnerID: "user123", Name: "Text", Kind: field.KindLongText},
+}
+
+// characterRows are the rows of the latest version of the character template.
+// The field "gone" was deleted after it was placed.
+var characterRows = []template.Row{
+	{Slots: []template.Slot{{FieldID: "name", Width: 2, Required: true}, {FieldID: "age", Width: 1}}},
+	// Defaults loaded from MongoDB hold lists as primitive.A.
+	{Slots: []template.Slot{{FieldID: "tags", Width: 2, Default: primitive.A{"hero"}}, {FieldID: "gone", Width: 1}}},
+}
+
+var testTemplates = stubTemplateStore{
+	templates: map[string]*template.Template{
+		"character": {ID: "character", OwnerID: "user123", ProjectID: "novel", Version: 3, Rows: characterRows},
+		"secret":    {ID: "secret", OwnerID: "someone", ProjectID: "theirs", Version: 1},
+		"scene": {ID: "scene", OwnerID: "user123", ProjectID: "novel", Version: 1, Rows: []template.Row{
+			{Slots: []template.Slot{{FieldID: "pov", Width: 1}, {FieldID: "at", Width: 1}}},
+			{Slots: []template.Slot{{FieldID: "text", Width: 2}}},
+		}},
+	},
+	versions: []*template.Version{
+		// Version 1 had a free text role and a death flag.
+		{TemplateID: "character", OwnerID: "user123", Version: 1, Rows: []template.Row{
+			{Slots: []template.Slot{{FieldID: "name", Width: 1}, {FieldID: "role", Width: 1}, {FieldID: "dead", Width: 1}}},
+		}},
+		// Version 2 replaced the role by tags and dropped the death flag.
+		{TemplateID: "character", OwnerID: "user123", Version: 2, Renames: map[string]string{"role": "tags"}, Rows: []template.Row{
+			{Slots: []template.Slot{{FieldID: "name", Width: 1}, {FieldID: "tags", Width: 1}}},
+		}},
+		{TemplateID: "character", OwnerID: "user123", Version: 3, Rows: characterRows},
+	},
+}
+
+var testLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+func TestNoteService_CreateNote(t *testing.T) {
+	mockRepo := new(MockNoteRepository)
+	service := NewNoteService(mockRepo, testTemplates, testFields, testProjects, testLogger)
+	ctx := context.Background()
+
+	t.Run("Success - Applies Defaults", func(t *testing.T) {
+		req := v1.CreateNoteRequest{
+			TemplateID: "character",
+			Title:      " Alice ",
+			Values:     map[string]any{"name": "Alice", "age": 30.0},
+		}
+		mockRepo.On("CreateNote", ctx, mock.AnythingOfType("*note.Note")).Return(nil).Once()
+		mockRepo.On("ListNotesByWikiTarget", ctx, "user123", "novel", "Alice").Return([]*Note{}, nil).Once()
+
+		note, err := service.CreateNote(ctx, "user123", "novel", req)
+
+		require.NoError(t, err)
+		assert.Equal(t, "Alice", note.Title)
+		assert.Equal(t, "novel", note.ProjectID)
+		assert.Equal(t, 3, note.TemplateVersion)
+		assert.Equal(t, map[string]any{"name": "Alice", "age": 30.0, "tags": primitive.A{"hero"}}, note.Values)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Cleared Default Is Not Stored", func(t *testing.T) {
+		req := v1.CreateNoteRequest{
+			TemplateID: "character",
+			Title:      "Bob",
+			Values:     map[string]any{"name": "Bob", "tags": []any{}},
+		}
+		mockRepo.On("CreateNote", ctx, mock.AnythingOfType("*note.Note")).Return(nil).Once()
+		mockRepo.On("ListNotesByWikiTarget", ctx, "user123", "novel", "Bob").Return([]*Note{}, nil).Once()
+
+		note, err := service.CreateNote(ctx, "user123", "novel", req)
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"name": "Bob"}, note.Values)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Slot Makes Field Required", func(t *testing.T) {
+		req := v1.CreateNoteRequest{TemplateID: "character", Title: "Nobody"}
+
+		_, err := service.CreateNote(ctx, "user123", "novel", req)
+
+		assert.ErrorIs(t, err, field.ErrInvalidValue)
+	})
+
+	t.Run("Invalid Value", func(t *testing.T) {
+		req := v1.CreateNoteRequest{
+			TemplateID: "character",
+			Title:      "Alice",
+			Values:     map[string]any{"name": "Alice", "age": 30.5},
+		}
+
+		_, err := service.CreateNote(ctx, "user123", "novel", req)
+
+		assert.ErrorIs(t, err, field.ErrInvalidValue)
+	})
+
+	t.Run("Field Not In Template", func(t *testing.T) {
+		req := v1.CreateNoteRequest{
+			TemplateID: "character",
+			Title:      "Alice",
+			Values:     map[string]any{"name": "Alice", "dead": true},
+		}
+
+		_, err := service.CreateNote(ctx, "user123", "novel", req)
+
+		assert.ErrorIs(t, err, field.ErrInvalidValue)
+	})
+
+	t.Run("Template Of Other Owner", func(t *testing.T) {
+		_, err := service.CreateNote(ctx, "user123", "novel", v1.CreateNoteRequest{TemplateID: "secret", Title: "Alice"})
+
+		assert.ErrorIs(t, err, template.ErrTemplateNotFound)
+	})
+
+	t.Run("Template Of Other Project", func(t *testing.T) {
+		_, err := service.CreateNote(ctx, "user123", "sequel", v1.CreateNoteRequest{TemplateID: "character", Title: "Alice"})
+
+		assert.ErrorIs(t, err, template.ErrTemplateNotFound)
+	})
+
+	t.Run("Project Of Other Owner", func(t *testing.T) {
+		_, err := service.CreateNote(ctx, "user123", "theirs", v1.CreateNoteRequest{TemplateID: "secret", Title: "Alice"})
+
+		assert.ErrorIs(t, err, project.ErrProjectNotFound)
+	})
+}
+
+func TestNoteService_UpdateNote(t *testing.T) {
+	mockRepo := new(MockNoteRepository)
+	service := NewNoteService(mockRepo, testTemplates, testFields, testProjects, testLogger)
+	ctx := context.Background()
+
+	t.Run("Success - No Defaults", func(t *testing.T) {
+		stored := &Note{ID: "note1", OwnerID: "user123", TemplateID: "character", TemplateVersion: 3, Title: "Alice"}
+		mockRepo.On("FindNoteByID", ctx, "note1").Return(stored, nil).Once()
+		mockRepo.On("UpdateNote", ctx, stored).Return(nil).Once()
+		mockRepo.On("ListNotesByWikiTarget", ctx, "user123", "", "Alice").Return([]*Note{}, nil).Once()
+		mockRepo.On("ListNotesByWikiTarget", ctx, "user123", "", "Alice Liddell").Return([]*Note{}, nil).Once()
+
+		req := v1.UpdateNoteRequest{Title: "Alice Liddell", Values: map[string]any{"name": "Alice", "tags": []any{"villain"}}}
+		note, err := service.UpdateNote(ctx, "user123", "note1", req)
+
+		require.NoError(t, err)
+		assert.Equal(t, "Alice Liddell", note.Title)
+		assert.Equal(t, map[string]any{"name": "Alice", "tags": []any{"villain"}}, note.Values)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Validated Against Pinned Version", func(t *testing.T) {
+		stored := &Note{ID: "note1", OwnerID: "user123", TemplateID: "character", TemplateVersion: 1, Title: "Alice"}
+		mockRepo.On("FindNoteByID", ctx, "note1").Return(stored, nil).Once()
+		mockRepo.On("UpdateNote", ctx, stored).Return(nil).Once()
+
+		req := v1.UpdateNoteRequest{Title: "Alice", Values: map[string]any{"dead": true}}
+		note, err := service.UpdateNote(ctx, "user123", "note1", req)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, note.TemplateVersion)
+		assert.Equal(t, map[string]any{"dead": true}, note.Values)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Other Owner", func(t *testing.T) {
+		mockRepo.On("FindNoteByID", ctx, "note1").Return(&Note{ID: "note1", OwnerID: "someone"}, nil).Once()
+
+		_, err := service.UpdateNote(ctx, "user123", "note1", v1.UpdateNoteRequest{Title: "Alice"})
+
+		assert.ErrorIs(t, err, ErrNoteNotFound)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestNoteService_UpgradeNotes(t *testing.T) {
+	ctx := context.Background()
+	outdated := func() []*Note {
+		return []*Note{
+			// Renamed role fits the tags, the death flag is archived.
+			{ID: "note1", OwnerID: "user123", TemplateID: "character", TemplateVersion: 1,
+				Values: map[string]any{"name": "Alice", "role": primitive.A{"hero"}, "dead": true}},
+			// A free text role is not one of the choices of the tags.
+			{ID: "note2", OwnerID: "user123", TemplateID: "character", TemplateVersion: 1,
+				Values: map[string]any{"name": "Bob", "role": "sidekick"}},
+		}
+	}
+
+	t.Run("Conflicting Notes Are Left Alone", func(t *testing.T) {
+		mockRepo := new(MockNoteRepository)
+		service := NewNoteService(mockRepo, testTemplates, testFields, testProjects, testLogger)
+		notes := outdated()
+		mockRepo.On("ListOutdatedNotes", ctx, "user123", "character", 3).Return(notes, nil).Once()
+		mockRepo.On("UpdateNote", ctx, notes[0]).Return(nil).Once()
+
+		report, err := service.UpgradeNotes(ctx, "user123", v1.UpgradeNotesRequest{TemplateID: "character"})
+
+		require.NoError(t, err)
+		assert.Equal(t, 3, report.TemplateVersion)
+		assert.Equal(t, []string{"note1"}, report.Upgraded)
+		require.Len(t, report.Conflicts, 1)
+		assert.Equal(t, "note2", report.Conflicts[0].NoteID)
+		assert.Equal(t, "tags", report.Conflicts[0].FieldID)
+		assert.Equal(t, 3, notes[0].TemplateVersion)
+		assert.Equal(t, map[string]any{"name": "Alice", "tags": primitive.A{"hero"}}, notes[0].Values)
+		assert.Equal(t, map[string]any{"dead": true}, notes[0].Archived)
+		assert.Equal(t, 1, notes[1].TemplateVersion)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Force Archives Conflicting Values", func(t *testing.T) {
+		mockRepo := new(MockNoteRepository)
+		service := NewNoteService(mockRepo, testTemplates, testFields, testProjects, testLogger)
+		notes := outdated()
+		mockRepo.On("ListOutdatedNotes", ctx, "user123", "character", 3).Return(notes, nil).Once()
+		mockRepo.On("UpdateNote", ctx, notes[1]).Return(nil).Once()
+
+		req := v1.UpgradeNotesRequest{TemplateID: "character", NoteIDs: []string{"note2"}, Force: true}
+		report, err := service.UpgradeNotes(ctx, "user123", req)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"note2"}, report.Upgraded)
+		assert.Len(t, report.Conflicts, 1)
+		assert.Equal(t, map[string]any{"name": "Bob"}, notes[1].Values)
+		assert.Equal(t, map[string]any{"tags": "sidekick"}, notes[1].Archived)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Dry Run Saves Nothing", func(t *testing.T) {
+		mockRepo := new(MockNoteRepository)
+		service := NewNoteService(mockRepo, testTemplates, testFields, testProjects, testLogger)
+		notes := outdated()
+		mockRepo.On("ListOutdatedNotes", ctx, "user123", "character", 3).Return(notes, nil).Once()
+
+		report, err := service.UpgradeNotes(ctx, "user123", v1.UpgradeNotesRequest{TemplateID: "character", DryRun: true})
+
+		require.NoError(t, err)
+		assert.True(t, report.DryRun)
+		assert.Equal(t, []string{"note1"}, report.Upgraded)
+		assert.Equal(t, 1, notes[0].TemplateVersion)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestNoteService_ListNotes(t *testing.T) {
+	mockRepo := new(MockNoteRepository)
+	service := NewNoteService(mockRepo, testTemplates, testFields, testProjects, testLogger)
+	ctx := context.Background()
+
+	t.Run("Typed Field Filter", func(t *testing.T) {
+		expected := NoteFilter{OwnerID: "user123", ProjectID: "novel", TemplateID: "character", FieldID: "age", Value: 30.0, Limit: 20}
+		mockRepo.On("ListNotes", ctx, expected).Return(&NotePage{Notes: []*Note{}}, nil).Once()
+
+		_, err := service.ListNotes(ctx, "user123", "novel", v1.ListNotesRequest{TemplateID: "character", FieldID: "age", Value: "30"})
+
+		require.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Value Of Wrong Type", func(t *testing.T) {
+		_, err := service.ListNotes(ctx, "user123", "novel", v1.ListNotesRequest{FieldID: "dead", Value: "maybe"})
+
+		assert.ErrorIs(t, err, ErrValidationFailed)
+	})
+
+	t.Run("Field Without Value", func(t *testing.T) {
+		_, err := service.ListNotes(ctx, "user123", "novel", v1.ListNotesRequest{FieldID: "age"})
+
+		assert.ErrorIs(t, err, ErrValidationFailed)
+	})
+
+	t.Run("Invalid Cursor", func(t *testing.T) {
+		_, err := service.ListNotes(ctx, "user123", "novel", v1.ListNotesRequest{Cursor: "!!"})
+
+		assert.ErrorIs(t, err, pagination.ErrInvalidCursor)
+	})
+
+	t.Run("Project Of Other Owner", func(t *testing.T) {
+		_, err := service.ListNotes(ctx, "user123", "theirs", v1.ListNotesRequest{})
+
+		assert.ErrorIs(t, err, project.ErrProjectNotFound)
+	})
+}
+
+func TestNoteService_ExportUserContent(t *testing.T) {
+	mockRepo := new(MockNoteRepository)
+	service := NewNoteService(mockRepo, testTemplates, testFields, testProjects, testLogger)
+	ctx := context.Background()
+
+	mockRepo.On("ListNotes", ctx, NoteFilter{OwnerID: "user123", Limit: 100}).
+		Return(&NotePage{Notes: []*Note{{ID: "note2"}}, NextCursor: "next"}, nil).Once()
+	mockRepo.On("ListNotes", ctx, NoteFilter{OwnerID: "user123", Limit: 100, Cursor: "next"}).
+		Return(&NotePage{Notes: []*Note{{
+			ID: "note1", OwnerID: "user123", TemplateID: "character", TemplateVersion: 3, Title: "Alice",
+			Values: map[string]any{"tags": primitive.A{"hero"}, "name": "Alice", "age": 30.0, "gone": true},
+		}}}, nil).Once()
+
+	docs, err := service.ExportUserContent(ctx, "user123")
+
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+	assert.Equal(t, "note1", docs[1].ID)
+	assert.Equal(t, "# \n", docs[0].Markdown)
+	assert.Equal(t, "# Alice\n\n## Name\n\nAlice\n\n## Age\n\n30\n\n## Tags\n\n- hero\n\n## gone\n\nYes\n", docs[1].Markdown)
+	assert.Equal(t, "notes", service.Name())
+	mockRepo.AssertExpectations(t)
+}
+
+func TestNoteService_DeleteProjectContent(t *testing.T) {
+	mockRepo := new(MockNoteRepository)
+	service := NewNoteService(mockRepo, testTemplates, testFields, testProjects, testLogger)
+	ctx := context.Background()
+
+	mockRepo.On("DeleteNotesByProject", ctx, "novel").Return(nil).Once()
+
+	require.NoError(t, service.DeleteProjectContent(ctx, "novel"))
+	mockRepo.AssertExpectations(t)
+}
+
+func TestNoteService_References(t *testing.T) {
+	ctx := context.Background()
+	alice := &Note{ID: "alice", OwnerID: "user123", ProjectID: "novel", TemplateID: "character", Title: "Alice"}
+	harbor := &Note{ID: "harbor", OwnerID: "user123", ProjectID: "novel", TemplateID: "place", Title: "Harbor"}
+
+	t.Run("Success - Records Links", func(t *testing.T) {
+		mockRepo := new(MockNoteRepository)
+		service := NewNoteService(mockRepo, testTemplates, testFields, testProjects, testLogger)
+		req := v1.CreateNoteRequest{TemplateID: "scene", Title: "Arrival", Values: map[string]any{"pov": "alice", "at": "harbor"}}
+		mockRepo.On("FindNoteByID", ctx, "alice").Return(alice, nil).Once()
+		mockRepo.On("FindNoteByID", ctx, "harbor").Return(harbor, nil).Once()
+		mockRepo.On("CreateNote", ctx, mock.AnythingOfType("*note.Note")).Return(nil).Once()
+		mockRepo.On("ListNotesByWikiTarget", ctx, "user123", "novel", "Arrival").Return([]*Note{}, nil).Once()
+
+		note, err := service.CreateNote(ctx, "user123", "novel", req)
+
+		require.NoError(t, err)
+		assert.Equal(t, []Link{{FieldID: "pov", NoteID: "alice"}, {FieldID: "at", NoteID: "harbor"}}, note.Links)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Failure - Missing Or Foreign Note", func(t *testing.T) {
+		for _, target := range []*Note{
+			{ID: "elsewhere", OwnerID: "user123", ProjectID: "sequel", TemplateID: "character"},
+			{ID: "elsewhere", OwnerID: "someone", ProjectID: "novel", TemplateID: "character"},
+		} {
+			mockRepo := new(MockNoteRepository)
+			service := NewNoteService(mockRepo, testTemplates, testFields, testProjects, testLogger)
+			req := v1.CreateNoteRequest{TemplateID: "scene", Title: "Arrival", Values: map[string]any{"pov": "elsewhere"}}
+			mockRepo.On("FindNoteByID", ctx, "elsewhere").Return(target, nil).Once()
+
+			_, err := service.CreateNote(ctx, "user123", "novel", req)
+
+			assert.ErrorIs(t, err, field.ErrInvalidValue)
+			mockRepo.AssertNotCalled(t, "CreateNote", mock.Anything, mock.Anything)
+		}
+	})
+
+	t.Run("Failure - Wrong Target Template", func(t *testing.T) {
+		mockRepo := new(MockNoteRepository)
+		service := NewNoteService(mockRepo, testTemplates, testFields, testProjects, testLogger)
+		req := v1.CreateNoteRequest{TemplateID: "scene", Title: "Arrival", Values: map[string]any{"pov": "harbor"}}
+		mockRepo.On("FindNoteByID", ctx, "harbor").Return(harbor, nil).Once()
+
+		_, err := service.CreateNote(ctx, "user123", "novel", req)
+
+		assert.ErrorIs(t, err, field.ErrInvalidValue)
+		assert.Contains(t, err.Error(), "target template")
+	})
+
+	t.Run("Success - Keeps Dangling Link", func(t *testing.T) {
+		mockRepo := new(MockNoteRepository)
+		service := NewNoteService(mockRepo, testTemplates, testFields, testProjects, testLogger)
+		stored := &Note{
+			ID: "arrival", OwnerID: "user123", ProjectID: "novel", TemplateID: "scene", TemplateVersion: 1, Title: "Arrival",
+			Values: map[string]any{"pov": "gone"},
+			Links:  []Link{{FieldID: "pov", NoteID: "gone", Dangling: true}},
+		}
+		mockRepo.On("FindNoteByID", ctx, "arrival").Return(stored, nil).Once()
+		mockRepo.On("UpdateNote", ctx, stored).Return(nil).Once()
+		mockRepo.On("ListNotesByWikiTarget", ctx, "user123", "novel", "Arrival").Return([]*Note{}, nil).Once()
+		mockRepo.On("ListNotesByWikiTarget", ctx, "user123", "novel", "Landing").Return([]*Note{}, nil).Once()
+
+		note, err := service.UpdateNote(ctx, "user123", "arrival", v1.UpdateNoteRequest{Title: "Landing", Values: map[string]any{"pov": "gone"}})
+
+		require.NoError(t, err)
+		assert.Equal(t, []Link{{FieldID: "pov", NoteID: "gone", Dangling: true}}, note.Links)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - Lists Backlinks", func(t *testing.T) {
+		mockRepo := new(MockNoteRepository)
+		service := NewNoteService(mockRepo, testTemplates, testFields, testProjects, testLogger)
+		arrival := &Note{ID: "arrival", OwnerID: "user123", TemplateID: "scene", Title: "Arrival",
+			Links: []Link{{FieldID: "pov", NoteID: "alice"}, {FieldID: "at", NoteID: "harbor"}}}
+		mockRepo.On("FindNoteByID", ctx, "alice").Return(alice, nil).Once()
+		mockRepo.On("ListBacklinks", ctx, "user123", "alice").Return([]*Note{arrival}, nil).Once()
+
+		backlinks, err := service.ListBacklinks(ctx, "user123", "alice")
+
+		require.NoError(t, err)
+		assert.Equal(t, []Backlink{{NoteID: "arrival", Title: "Arrival", TemplateID: "scene", FieldID: "pov"}}, backlinks)
+	})
+
+	t.Run("Failure - Backlinks Of Other Owner", func(t *testing.T) {
+		mockRepo := new(MockNoteRepository)
+		service := NewNoteService(mockRepo, testTemplates, testFields, testProjects, testLogger)
+		mockRepo.On("FindNoteByID", ctx, "alice").Return(&Note{ID: "alice", OwnerID: "someone"}, nil).Once()
+
+		_, err := service.ListBacklinks(ctx, "user123", "alice")
+
+		assert.ErrorIs(t, err, ErrNoteNotFound)
+		mockRepo.AssertNotCalled(t, "ListBacklinks", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Success - Delete Marks Links Dangling", func(t *testing.T) {
+		mockRepo := new(MockNoteRepository)
+		service := NewNoteService(mockRepo, testTemplates, testFields, testProjects, testLogger)
+		arrival := &Note{ID: "arrival", OwnerID: "user123", Links: []Link{{FieldID: "pov", NoteID: "alice"}, {FieldID: "at", NoteID: "harbor"}}}
+		mockRepo.On("FindNoteByID", ctx, "alice").Return(alice, nil).Once()
+		mockRepo.On("DeleteNote", ctx, "user123", "alice").Return(nil).Once()
+		mockRepo.On("ListBacklinks", ctx, "user123", "alice").Return([]*Note{arrival}, nil).Once()
+		mockRepo.On("UpdateNote", ctx, arrival).Return(nil).Once()
+		mockRepo.On("ListNotesByWikiTarget", ctx, "user123", "novel", "Alice").Return([]*Note{}, nil).Once()
+
+		require.NoError(t, service.DeleteNote(ctx, "user123", "alice"))
+		assert.Equal(t, []Link{{FieldID: "pov", NoteID: "alice", Dangling: true}, {FieldID: "at", NoteID: "harbor"}}, arrival.Links)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Failure - Delete Note Of Other Owner", func(t *testing.T) {
+		mockRepo := new(MockNoteRepository)
+		service := NewNoteService(mockRepo, testTemplates, testFields, testProjects, testLogger)
+		mockRepo.On("FindNoteByID", ctx, "alice").Return(&Note{ID: "alice", OwnerID: "someone"}, nil).Once()
+
+		err := service.DeleteNote(ctx, "user123", "alice")
+
+		assert.ErrorIs(t, err, ErrNoteNotFound)
+		mockRepo.AssertNotCalled(t, "DeleteNote", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestNoteService_Markdown(t *testing.T) {
+	ctx := context.Background()
+	alice := &Note{ID: "alice", OwnerID: "user123", ProjectID: "novel", TemplateID: "character", Title: "Alice"}
+
+	t.Run("Success - Indexes Wiki Links And Words", func(t *testing.T) {
+		mockRepo := new(MockNoteRepository)
+		service := NewNoteService(mockRepo, testTemplates, testFields, testProjects, testLogger)
+		req := v1.CreateNoteRequest{TemplateID: "scene", Title: "Arrival", Values: map[string]any{
+			"pov":  "alice",
+			"text": "[[alice]] meets *[[ALICE|herself]]* at [[Nowhere]].",
+		}}
+		mockRepo.On("FindNoteByID", ctx, "alice").Return(alice, nil).Once()
+		mockRepo.On("FindNoteByTitle", ctx, "user123", "novel", "alice").Return(alice, nil).Once()
+		mockRepo.On("FindNoteByTitle", ctx, "user123", "novel", "ALICE").Return(alice, nil).Once()
+		mockRepo.On("FindNoteByTitle", ctx, "user123", "novel", "Nowhere").Return(nil, ErrNoteNotFound).Once()
+		mockRepo.On("CreateNote", ctx, mock.AnythingOfType("*note.Note")).Return(nil).Once()
+		mockRepo.On("ListNotesByWikiTarget", ctx, "user123", "novel", "Arrival").Return([]*Note{}, nil).Once()
+
+		note, err := service.CreateNote(ctx, "user123", "novel", req)
+
+		require.NoError(t, err)
+		assert.Equal(t, []Link{{FieldID: "pov", NoteID: "alice"}, {FieldID: "text", NoteID: "alice"}}, note.Links)
+		assert.Equal(t, []string{"alice", "nowhere"}, note.WikiTargets)
+		assert.Equal(t, map[string]int{"text": 5}, note.WordCounts)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - Creating A Note Resolves Wiki Links To It", func(t *testing.T) {
+		mockRepo := new(MockNoteRepository)
+		service := NewNoteService(mockRepo, testTemplates, testFields, testProjects, testLogger)
+		arrival := &Note{
+			ID: "arrival", OwnerID: "user123", ProjectID: "novel", TemplateID: "scene", TemplateVersion: 1, Title: "Arrival",
+			Values:      map[string]any{"text": "[[Nowhere]] is far."},
+			WikiTargets: []string{"nowhere"},
+		}
+		nowhere := &Note{ID: "nowhere", OwnerID: "user123", ProjectID: "novel", TemplateID: "character", Title: "Nowhere"}
+		req := v1.CreateNoteRequest{TemplateID: "character", Title: "Nowhere", Values: map[string]any{"name": "Nowhere"}}
+		mockRepo.On("CreateNote", ctx, mock.AnythingOfType("*note.Note")).Run(func(args mock.Arguments) {
+			args.Get(1).(*Note).ID = "nowhere"
+		}).Return(nil).Once()
+		mockRepo.On("ListNotesByWikiTarget", ctx, "user123", "novel", "Nowhere").Return([]*Note{arrival}, nil).Once()
+		mockRepo.On("FindNoteByTitle", ctx, "user123", "novel", "Nowhere").Return(nowhere, nil).Once()
+		mockRepo.On("UpdateNote", ctx, arrival).Return(nil).Once()
+
+		_, err := service.CreateNote(ctx, "user123", "novel", req)
+
+		require.NoError(t, err)
+		assert.Equal(t, []Link{{FieldID: "text", NoteID: "nowhere"}}, arrival.Links)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - Renaming A Note Resolves Wiki Links Again", func(t *testing.T) {
+		mockRepo := new(MockNoteRepository)
+		service := NewNoteService(mockRepo, testTemplates, testFields, testProjects, testLogger)
+		arrival := &Note{
+			ID: "arrival", OwnerID: "user123", ProjectID: "novel", TemplateID: "scene", TemplateVersion: 1, Title: "Arrival",
+			Values:      map[string]any{"text": "[[Alice]] arrives."},
+			Links:       []Link{{FieldID: "text", NoteID: "alice"}},
+			WikiTargets: []string{"alice"},
+		}
+		stored := &Note{ID: "alice", OwnerID: "user123", ProjectID: "novel", TemplateID: "character", TemplateVersion: 3, Title: "Alice"}
+		mockRepo.On("FindNoteByID", ctx, "alice").Return(stored, nil).Once()
+		mockRepo.On("UpdateNote", ctx, stored).Return(nil).Once()
+		mockRepo.On("ListNotesByWikiTarget", ctx, "user123", "novel", "Alice").Return([]*Note{arrival}, nil).Once()
+		mockRepo.On("ListNotesByWikiTarget", ctx, "user123", "novel", "Alicia").Return([]*Note{}, nil).Once()
+		mockRepo.On("FindNoteByTitle", ctx, "user123", "novel", "Alice").Return(nil, ErrNoteNotFound).Once()
+		mockRepo.On("UpdateNote", ctx, arrival).Return(nil).Once()
+
+		_, err := service.UpdateNote(ctx, "user123", "alice", v1.UpdateNoteRequest{Title: "Alicia", Values: map[string]any{"name": "Alicia"}})
+
+		require.NoError(t, err)
+		assert.Empty(t, arrival.Links)
+		assert.Equal(t, []string{"alice"}, arrival.WikiTargets)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - Deleting A Note Resolves Wiki Links Again", func(t *testing.T) {
+		mockRepo := new(MockNoteRepository)
+		service := NewNoteService(mockRepo, testTemplates, testFields, testProjects, testLogger)
+		arrival := &Note{
+			ID: "arrival", OwnerID: "user123", ProjectID: "novel", TemplateID: "scene", TemplateVersion: 1, Title: "Arrival",
+			Values:      map[string]any{"text": "[[Alice]] arrives."},
+			Links:       []Link{{FieldID: "text", NoteID: "alice"}},
+			WikiTargets: []string{"alice"},
+		}
+		twin := &Note{ID: "twin", OwnerID: "user123", ProjectID: "novel", TemplateID: "character", Title: "Alice"}
+		mockRepo.On("FindNoteByID", ctx, "alice").Return(alice, nil).Once()
+		mockRepo.On("DeleteNote", ctx, "user123", "alice").Return(nil).Once()
+		mockRepo.On("ListBacklinks", ctx, "user123", "alice").Return([]*Note{arrival}, nil).Once()
+		mockRepo.On("UpdateNote", ctx, arrival).Return(nil).Twice()
+		mockRepo.On("ListNotesByWikiTarget", ctx, "user123", "novel", "Alice").Return([]*Note{arrival}, nil).Once()
+		mockRepo.On("FindNoteByTitle", ctx, "user123", "novel", "Alice").Return(twin, nil).Once()
+
+		require.NoError(t, service.DeleteNote(ctx, "user123", "alice"))
+		assert.Equal(t, []Link{{FieldID: "text", NoteID: "twin"}}, arrival.Links)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - Renders HTML", func(t *testing.T) {
+		mockRepo := new(MockNoteRepository)
+		service := NewNoteService(mockRepo, testTemplates, testFields, testProjects, testLogger)
+		stored := &Note{
+			ID: "arrival", OwnerID: "user123", ProjectID: "novel", TemplateID: "scene", TemplateVersion: 1, Title: "Arrival",
+			Values: map[string]any{"text": "**[[Alice]]** waves. <script>alert(1)</script>"},
+		}
+		mockRepo.On("FindNoteByID", ctx, "arrival").Return(stored, nil).Once()
+		mockRepo.On("FindNoteByTitle", ctx, "user123", "novel", "Alice").Return(alice, nil).Once()
+
+		rendered, err := service.RenderNote(ctx, "user123", "arrival")
+
+		require.NoError(t, err)
+		assert.Equal(t, "arrival", rendered.NoteID)
+		assert.Equal(t, `<p><strong><a class="wiki-link" href="/notes/alice" rel="nofollow">Alice</a></strong> waves. alert(1)</p>`+"\n", rendered.HTML["text"])
+		assert.Equal(t, map[string]int{"text": 3}, rendered.WordCounts)
+	})
+
+	t.Run("Failure - Render Note Of Other Owner", func(t *testing.T) {
+		mockRepo := new(MockNoteRepository)
+		service := NewNoteService(mockRepo, testTemplates, testFields, testProjects, testLogger)
+		mockRepo.On("FindNoteByID", ctx, "arrival").Return(&Note{ID: "arrival", OwnerID: "someone"}, nil).Once()
+
+		_, err := service.RenderNote(ctx, "user123", "arrival")
+
+		assert.ErrorIs(t, err, ErrNoteNotFound)
+	})
+}
+
+// MockIndexer is a mock implementation of Indexer
+type MockIndexer struct {
+	mock.Mock
+}
+
+func (m *MockIndexer) IndexNote(ctx context.Context, note *Note) error {
+	args := m.Called(ctx, note)
+	return args.Error(0)
+}
+
+func (m *MockIndexer) RemoveNote(ctx context.Context, ownerID, id string) error {
+	args := m.Called(ctx, ownerID, id)
+	return args.Error(0)
+}
+
+func (m *MockIndexer) RemoveProjectNotes(ctx context.Context, projectID string) error {
+	args := m.Called(ctx, projectID)
+	return args.Error(0)
+}
+
+func (m *MockIndexer) RemoveOwnerNotes(ctx context.Context, ownerID string) error {
+	args := m.Called(ctx, ownerID)
+	return args.Error(0)
+}
+
+func TestNoteService_Indexers(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockNoteRepository)
+	mockIndexer := new(MockIndexer)
+	service := NewNoteService(mockRepo, testTemplates, testFields, testProjects, testLogger, mockIndexer)
+
+	req := v1.CreateNoteRequest{TemplateID: "character", Title: "Alice", Values: map[string]any{"name": "Alice"}}
+	mockRepo.On("CreateNote", ctx, mock.AnythingOfType("*note.Note")).Return(nil).Once()
+	mockRepo.On("ListNotesByWikiTarget", ctx, "user123", "novel", "Alice").Return([]*Note{}, nil).Once()
+	mockIndexer.On("IndexNote", ctx, mock.AnythingOfType("*note.Note")).Return(nil).Once()
+	mockRepo.On("FindNoteByID", ctx, "note1").Return(&Note{ID: "note1", OwnerID: "user123", ProjectID: "novel", Title: "Bob"}, nil).Once()
+	mockRepo.On("DeleteNote", ctx, "user123", "note1").Return(nil).Once()
+	mockIndexer.On("RemoveNote", ctx, "user123", "note1").Return(nil).Once()
+	mockRepo.On("ListBacklinks", ctx, "user123", "note1").Return([]*Note{}, nil).Once()
+	mockRepo.On("ListNotesByWikiTarget", ctx, "user123", "novel", "Bob").Return([]*Note{}, nil).Once()
+	mockRepo.On("DeleteNotesByProject", ctx, "novel").Return(nil).Once()
+	mockIndexer.On("RemoveProjectNotes", ctx, "novel").Return(nil).Once()
+	mockRepo.On("DeleteNotesByOwner", ctx, "user123").Return(nil).Once()
+	mockIndexer.On("RemoveOwnerNotes", ctx, "user123").Return(nil).Once()
+
+	_, err := service.CreateNote(ctx, "user123", "novel", req)
+	require.NoError(t, err)
+	require.NoError(t, service.DeleteNote(ctx, "user123", "note1"))
+	require.NoError(t, service.DeleteProjectContent(ctx, "novel"))
+	require.NoError(t, service.DeleteUserContent(ctx, "user123"))
+	mockRepo.AssertExpectations(t)
+	mockIndexer.AssertExpectations(t)
+}
+
+func TestNoteService_IndexerFailures(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockNoteRepository)
+	mockIndexer := new(MockIndexer)
+	var logs bytes.Buffer
+	service := NewNoteService(mockRepo, testTemplates, testFields, testProjects, slog.New(slog.NewTextHandler(&logs, nil)), mockIndexer)
+	indexErr := errors.New("index unavailable")
+
+	t.Run("Create Succeeds", func(t *testing.T) {
+		req := v1.CreateNoteRequest{TemplateID: "character", Title: "Alice", Values: map[string]any{"name": "Alice"}}
+		mockRepo.On("CreateNote", ctx, mock.AnythingOfType("*note.Note")).Return(nil).Once()
+		mockRepo.On("ListNotesByWikiTarget", ctx, "user123", "novel", "Alice").Return([]*Note{}, nil).Once()
+		mockIndexer.On("IndexNote", ctx, mock.AnythingOfType("*note.Note")).Return(indexErr).Once()
+
+		note, err := service.CreateNote(ctx, "user123", "novel", req)
+
+		require.NoError(t, err)
+		assert.Equal(t, "Alice", note.Title)
+		assert.Contains(t, logs.String(), "updating note indexer failed")
+		assert.Contains(t, logs.String(), "index unavailable")
+	})
+
+	t.Run("Delete Succeeds", func(t *testing.T) {
+		logs.Reset()
+		mockRepo.On("FindNoteByID", ctx, "note1").Return(&Note{ID: "note1", OwnerID: "user123", ProjectID: "novel", Title: "Bob"}, nil).Once()
+		mockRepo.On("DeleteNote", ctx, "user123", "note1").Return(nil).Once()
+		mockIndexer.On("RemoveNote", ctx, "user123", "note1").Return(indexErr).Once()
+		mockRepo.On("ListBacklinks", ctx, "user123", "note1").Return([]*Note{}, nil).Once()
+		mockRepo.On("ListNotesByWikiTarget", ctx, "user123", "novel", "Bob").Return([]*Note{}, nil).Once()
+
+		require.NoError(t, service.DeleteNote(ctx, "user123", "note1"))
+		assert.Contains(t, logs.String(), "note_id=note1")
+	})
+
+	mockRepo.AssertExpectations(t)
+	mockIndexer.AssertExpectations(t)
+}
@@ -0,0 +1,56 @@
+package openapi
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// swaggerUIVersion pins the Swagger UI assets loaded from the CDN.
+const swaggerUIVersion = "5.17.14"
+
+// swaggerUIPage renders the document served next to it at openapi.json.
+const swaggerUIPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8" />
+  <meta name="viewport" content="width=device-width, initial-scale=1" />
+  <title>Author Notes API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@` + swaggerUIVersion + `/swagger-ui.css" />
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@` + swaggerUIVersion + `/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.onload = () => {
+      window.ui = SwaggerUIBundle({ url: "openapi.json", dom_id: "#swagger-ui" });
+    };
+  </script>
+</body>
+</html>
+`
+
+// OpenAPIHTTPHandler serves the OpenAPI document of the API and a Swagger UI
+// page to browse it.
+type OpenAPIHTTPHandler struct {
+	spec []byte
+}
+
+func NewOpenAPIHTTPHandler(spec []byte) *OpenAPIHTTPHandler {
+	return &OpenAPIHTTPHandler{spec: spec}
+}
+
+func (h *OpenAPIHTTPHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.GET("/openapi.json", h.GetSpec)
+	rg.GET("/docs", h.GetDocs)
+}
+
+// GetSpec serves the OpenAPI document.
+func (h *OpenAPIHTTPHandler) GetSpec(c *gin.Context) {
+	c.Data(http.StatusOK, "application/json; charset=utf-8", h.spec)
+}
+
+// GetDocs serves the Swagger UI page.
+func (h *OpenAPIHTTPHandler) GetDocs(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(swaggerUIPage))
+}
@@ -0,0 +1,818 @@
+package openapi
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Generate builds an OpenAPI document from the swag style annotations of the
+// Go module rooted at root: the general API information annotated anywhere in
+// a main package, and one operation per function annotated with @Router.
+// Request and response types are resolved from the module's source.
+func Generate(root string) (*Document, error) {
+	module, err := readModulePath(filepath.Join(root, "go.mod"))
+	if err != nil {
+		return nil, err
+	}
+	g := &generator{
+		root:     root,
+		module:   module,
+		fset:     token.NewFileSet(),
+		packages: make(map[string]*sourcePackage),
+		doc: &Document{
+			OpenAPI: "3.0.3",
+			Paths:   make(map[string]PathItem),
+			Components: Components{
+				Schemas:         make(map[string]*Schema),
+				SecuritySchemes: make(map[string]*SecurityScheme),
+			},
+		},
+		operationIDs: make(map[string]string),
+	}
+
+	err = filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		name := d.Name()
+		if p != root && (strings.HasPrefix(name, ".") || name == "testdata" || name == "vendor") {
+			return filepath.SkipDir
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		importPath := module
+		if rel != "." {
+			importPath = module + "/" + filepath.ToSlash(rel)
+		}
+		pkg, err := g.load(importPath)
+		if err != nil || pkg == nil {
+			return err
+		}
+		return g.collect(pkg)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if g.doc.Info.Title == "" {
+		return nil, errors.New("openapi: no @title annotation found")
+	}
+	return g.doc, nil
+}
+
+// sourcePackage is a parsed package of the module.
+type sourcePackage struct {
+	path   string
+	name   string
+	files  []*ast.File
+	types  map[string]*typeDecl
+	consts map[string][]any // enum values of named types, by type name
+}
+
+// typeDecl is a type declaration and the file it appears in, which is needed
+// to resolve the package selectors it uses.
+type typeDecl struct {
+	spec *ast.TypeSpec
+	file *ast.File
+	pkg  *sourcePackage
+}
+
+type generator struct {
+	root         string
+	module       string
+	fset         *token.FileSet
+	packages     map[string]*sourcePackage
+	doc          *Document
+	operationIDs map[string]string // operation ID to the route that uses it
+}
+
+func readModulePath(goMod string) (string, error) {
+	f, err := os.Open(goMod)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if rest, ok := strings.CutPrefix(strings.TrimSpace(scanner.Text()), "module "); ok {
+			return strings.Trim(strings.TrimSpace(rest), `"`), nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf("openapi: no module directive in %s", goMod)
+}
+
+// load parses the non-test Go files of the module package importPath. It
+// returns nil if the path is outside the module or has no Go files.
+func (g *generator) load(importPath string) (*sourcePackage, error) {
+	if pkg, ok := g.packages[importPath]; ok {
+		return pkg, nil
+	}
+	if importPath != g.module && !strings.HasPrefix(importPath, g.module+"/") {
+		return nil, nil
+	}
+	dir := filepath.Join(g.root, filepath.FromSlash(strings.TrimPrefix(strings.TrimPrefix(importPath, g.module), "/")))
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var pkg *sourcePackage
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(g.fset, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		if pkg == nil {
+			pkg = &sourcePackage{
+				path:   importPath,
+				name:   file.Name.Name,
+				types:  make(map[string]*typeDecl),
+				consts: make(map[string][]any),
+			}
+		}
+		pkg.files = append(pkg.files, file)
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					pkg.types[spec.Name.Name] = &typeDecl{spec: spec, file: file, pkg: pkg}
+				case *ast.ValueSpec:
+					collectEnumValues(pkg, gen.Tok, spec)
+				}
+			}
+		}
+	}
+	g.packages[importPath] = pkg
+	return pkg, nil
+}
+
+// collectEnumValues records the literal values of typed constants, which
+// become the enum of their type's schema.
+func collectEnumValues(pkg *sourcePackage, tok token.Token, spec *ast.ValueSpec) {
+	ident, ok := spec.Type.(*ast.Ident)
+	if tok != token.CONST || !ok {
+		return
+	}
+	for _, value := range spec.Values {
+		lit, ok := value.(*ast.BasicLit)
+		if !ok {
+			continue
+		}
+		switch lit.Kind {
+		case token.STRING:
+			if s, err := strconv.Unquote(lit.Value); err == nil {
+				pkg.consts[ident.Name] = append(pkg.consts[ident.Name], s)
+			}
+		case token.INT:
+			if n, err := strconv.ParseInt(lit.Value, 0, 64); err == nil {
+				pkg.consts[ident.Name] = append(pkg.consts[ident.Name], n)
+			}
+		}
+	}
+}
+
+// collect adds the annotations found in pkg to the document.
+func (g *generator) collect(pkg *sourcePackage) error {
+	for _, file := range pkg.files {
+		if pkg.name == "main" {
+			for _, group := range file.Comments {
+				g.parseGeneralInfo(group)
+			}
+		}
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Doc == nil || !hasRoute(fn.Doc) {
+				continue
+			}
+			if err := g.parseOperation(pkg, file, fn); err != nil {
+				pos := g.fset.Position(fn.Pos())
+				return fmt.Errorf("%s:%d: %s: %w", pos.Filename, pos.Line, fn.Name.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// annotations returns the "@Name value" lines of a comment group in order.
+func annotations(group *ast.CommentGroup) [][2]string {
+	var lines [][2]string
+	for _, line := range strings.Split(group.Text(), "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "@") {
+			continue
+		}
+		name, value, _ := strings.Cut(line, " ")
+		lines = append(lines, [2]string{name, strings.TrimSpace(value)})
+	}
+	return lines
+}
+
+// hasRoute reports whether a comment group annotates an operation.
+func hasRoute(group *ast.CommentGroup) bool {
+	for _, a := range annotations(group) {
+		if strings.EqualFold(a[0], "@Router") {
+			return true
+		}
+	}
+	return false
+}
+
+// parseGeneralInfo reads @title, @version, @description and API key security
+// definitions. A @description following a security definition describes it.
+func (g *generator) parseGeneralInfo(group *ast.CommentGroup) {
+	var scheme *SecurityScheme
+	for _, a := range annotations(group) {
+		switch name, value := a[0], a[1]; strings.ToLower(name) {
+		case "@title":
+			g.doc.Info.Title = value
+		case "@version":
+			g.doc.Info.Version = value
+		case "@description":
+			if scheme != nil {
+				scheme.Description = value
+			} else {
+				g.doc.Info.Description = value
+			}
+		case "@securitydefinitions.apikey":
+			scheme = &SecurityScheme{Type: "apiKey"}
+			g.doc.Components.SecuritySchemes[value] = scheme
+		case "@in":
+			if scheme != nil {
+				scheme.In = value
+			}
+		case "@name":
+			if scheme != nil {
+				scheme.Name = value
+			}
+		}
+	}
+}
+
+var (
+	paramPattern    = regexp.MustCompile(`^(\S+)\s+(\w+)\s+(\S+)\s+(true|false)\s+"([^"]*)"\s*(.*)$`)
+	responsePattern = regexp.MustCompile(`^(\d{3})\s+(?:\{(\w+)\}\s+(\S+)\s+)?"([^"]*)"$`)
+	routePattern    = regexp.MustCompile(`^(\S+)\s+\[(\w+)\]$`)
+	attrPattern     = regexp.MustCompile(`(\w+)\(([^)]*)\)`)
+	pathParam       = regexp.MustCompile(`:(\w+)`)
+)
+
+// parseOperation adds the operation annotated on fn.
+func (g *generator) parseOperation(pkg *sourcePackage, file *ast.File, fn *ast.FuncDecl) error {
+	op := &Operation{OperationID: fn.Name.Name, Responses: make(map[string]*Response)}
+	consumes, produces := "application/json", "application/json"
+	var route, method string
+	var body *Parameter
+	var bodyType string
+
+	for _, a := range annotations(fn.Doc) {
+		name, value := a[0], a[1]
+		switch strings.ToLower(name) {
+		case "@summary":
+			op.Summary = value
+		case "@description":
+			op.Description = strings.TrimSpace(op.Description + "\n" + value)
+		case "@tags":
+			for _, tag := range strings.Split(value, ",") {
+				op.Tags = append(op.Tags, strings.TrimSpace(tag))
+			}
+		case "@accept":
+			consumes = mimeType(value)
+		case "@produce":
+			produces = mimeType(value)
+		case "@security":
+			op.Security = append(op.Security, map[string][]string{value: {}})
+		case "@param":
+			m := paramPattern.FindStringSubmatch(value)
+			if m == nil {
+				return fmt.Errorf("malformed @Param %q", value)
+			}
+			param := &Parameter{Name: m[1], In: m[2], Required: m[4] == "true", Description: m[5]}
+			if param.In == "body" {
+				body, bodyType = param, m[3]
+				continue
+			}
+			schema, err := primitiveSchema(m[3])
+			if err != nil {
+				return err
+			}
+			for _, attr := range attrPattern.FindAllStringSubmatch(m[6], -1) {
+				switch strings.ToLower(attr[1]) {
+				case "enums":
+					for _, v := range strings.Split(attr[2], ",") {
+						schema.Enum = append(schema.Enum, strings.TrimSpace(v))
+					}
+				case "default":
+					schema.Default = strings.TrimSpace(attr[2])
+				}
+			}
+			param.Schema = schema
+			op.Parameters = append(op.Parameters, param)
+		case "@success", "@failure":
+			m := responsePattern.FindStringSubmatch(value)
+			if m == nil {
+				return fmt.Errorf("malformed %s %q", name, value)
+			}
+			// Handlers report errors as JSON whatever they produce on success.
+			contentType := produces
+			if strings.EqualFold(name, "@Failure") {
+				contentType = "application/json"
+			}
+			if err := g.addResponse(op, pkg, file, m[1], m[2], m[3], m[4], contentType); err != nil {
+				return err
+			}
+		case "@router":
+			m := routePattern.FindStringSubmatch(value)
+			if m == nil {
+				return fmt.Errorf("malformed @Router %q", value)
+			}
+			route, method = pathParam.ReplaceAllString(m[1], "{$1}"), strings.ToLower(m[2])
+		}
+	}
+
+	if body != nil {
+		schema, err := g.schemaFor(pkg, file, bodyType)
+		if err != nil {
+			return err
+		}
+		op.RequestBody = &RequestBody{
+			Description: body.Description,
+			Required:    body.Required,
+			Content:     map[string]MediaType{consumes: {Schema: schema}},
+		}
+	}
+	if len(op.Responses) == 0 {
+		return errors.New("no @Success or @Failure annotations")
+	}
+	if prev, ok := g.operationIDs[op.OperationID]; ok {
+		return fmt.Errorf("operation ID %s is also used by %s", op.OperationID, prev)
+	}
+	key := strings.ToUpper(method) + " " + route
+	g.operationIDs[op.OperationID] = key
+
+	item := g.doc.Paths[route]
+	if item == nil {
+		item = make(PathItem)
+		g.doc.Paths[route] = item
+	}
+	if _, ok := item[method]; ok {
+		return fmt.Errorf("route %s is annotated twice", key)
+	}
+	item[method] = op
+	return nil
+}
+
+// addResponse adds a @Success or @Failure response. Several annotations for
+// the same status code are merged into one response.
+func (g *generator) addResponse(op *Operation, pkg *sourcePackage, file *ast.File, code, kind, typ, description, contentType string) error {
+	var schema *Schema
+	switch kind {
+	case "":
+	case "file":
+		schema = &Schema{Type: "string", Format: "binary"}
+	case "object", "array":
+		item, err := g.schemaFor(pkg, file, typ)
+		if err != nil {
+			return err
+		}
+		schema = item
+		if kind == "array" {
+			schema = &Schema{Type: "array", Items: item}
+		}
+	default:
+		item, err := primitiveSchema(kind)
+		if err != nil {
+			return err
+		}
+		schema = item
+	}
+
+	response, ok := op.Responses[code]
+	if !ok {
+		response = &Response{}
+		op.Responses[code] = response
+	}
+	if response.Description == "" {
+		response.Description = description
+	} else {
+		response.Description += "; " + description
+	}
+	if schema != nil && response.Content == nil {
+		response.Content = map[string]MediaType{contentType: {Schema: schema}}
+	}
+	return nil
+}
+
+// mimeType expands the swag shorthands for common MIME types.
+func mimeType(value string) string {
+	switch value {
+	case "json":
+		return "application/json"
+	case "plain":
+		return "text/plain"
+	case "html":
+		return "text/html"
+	}
+	return value
+}
+
+// primitiveSchema returns the schema of a path, query or header parameter type.
+func primitiveSchema(typ string) (*Schema, error) {
+	switch typ {
+	case "string":
+		return &Schema{Type: "string"}, nil
+	case "int", "integer":
+		return &Schema{Type: "integer"}, nil
+	case "number":
+		return &Schema{Type: "number"}, nil
+	case "bool", "boolean":
+		return &Schema{Type: "boolean"}, nil
+	}
+	return nil, fmt.Errorf("unsupported parameter type %q", typ)
+}
+
+// schemaFor returns the schema of a type written in an annotation of file.
+func (g *generator) schemaFor(pkg *sourcePackage, file *ast.File, typ string) (*Schema, error) {
+	expr, err := parser.ParseExpr(typ)
+	if err != nil {
+		return nil, fmt.Errorf("invalid type %q: %w", typ, err)
+	}
+	return g.exprSchema(&typeDecl{file: file, pkg: pkg}, expr)
+}
+
+// resolveImport returns the package that name refers to in file. Annotations
+// may use an import's package name instead of its local alias, so both match;
+// when several imports share the package name, the one declaring typeName wins.
+func (g *generator) resolveImport(scope *typeDecl, name, typeName string) (*sourcePackage, string, error) {
+	var byName string
+	for _, imp := range scope.file.Imports {
+		importPath, _ := strconv.Unquote(imp.Path.Value)
+		if imp.Name != nil && imp.Name.Name == name {
+			pkg, err := g.load(importPath)
+			return pkg, importPath, err
+		}
+		pkg, err := g.load(importPath)
+		if err != nil {
+			return nil, "", err
+		}
+		if pkg != nil && pkg.name == name || pkg == nil && path.Base(importPath) == name {
+			if pkg != nil && pkg.types[typeName] != nil {
+				return pkg, importPath, nil
+			}
+			byName = importPath
+		}
+	}
+	if byName != "" {
+		pkg, err := g.load(byName)
+		return pkg, byName, err
+	}
+	if scope.pkg.name == name {
+		return scope.pkg, scope.pkg.path, nil
+	}
+	return nil, "", fmt.Errorf("unknown package %q", name)
+}
+
+func (g *generator) exprSchema(scope *typeDecl, expr ast.Expr) (*Schema, error) {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		if schema := basicSchema(expr.Name); schema != nil {
+			return schema, nil
+		}
+		decl, ok := scope.pkg.types[expr.Name]
+		if !ok {
+			return nil, fmt.Errorf("unknown type %s in %s", expr.Name, scope.pkg.path)
+		}
+		return g.namedSchema(decl)
+	case *ast.SelectorExpr:
+		ident, ok := expr.X.(*ast.Ident)
+		if !ok {
+			return nil, fmt.Errorf("unsupported type %T", expr.X)
+		}
+		pkg, importPath, err := g.resolveImport(scope, ident.Name, expr.Sel.Name)
+		if err != nil {
+			return nil, err
+		}
+		if importPath == "time" && expr.Sel.Name == "Time" {
+			return &Schema{Type: "string", Format: "date-time"}, nil
+		}
+		if pkg == nil {
+			return nil, fmt.Errorf("type %s.%s is outside the module", importPath, expr.Sel.Name)
+		}
+		decl, ok := pkg.types[expr.Sel.Name]
+		if !ok {
+			return nil, fmt.Errorf("unknown type %s.%s", importPath, expr.Sel.Name)
+		}
+		return g.namedSchema(decl)
+	case *ast.StarExpr:
+		return g.exprSchema(scope, expr.X)
+	case *ast.ArrayType:
+		if ident, ok := expr.Elt.(*ast.Ident); ok && ident.Name == "byte" {
+			return &Schema{Type: "string", Format: "byte"}, nil
+		}
+		items, err := g.exprSchema(scope, expr.Elt)
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: "array", Items: items}, nil
+	case *ast.MapType:
+		values, err := g.exprSchema(scope, expr.Value)
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: "object", AdditionalProperties: values}, nil
+	case *ast.InterfaceType:
+		return &Schema{}, nil
+	case *ast.StructType:
+		return g.structSchema(scope, expr)
+	}
+	return nil, fmt.Errorf("unsupported type %T", expr)
+}
+
+// basicSchema returns the schema of a predeclared type, or nil.
+func basicSchema(name string) *Schema {
+	switch name {
+	case "string":
+		return &Schema{Type: "string"}
+	case "bool":
+		return &Schema{Type: "boolean"}
+	case "int", "int64", "uint", "uint64":
+		return &Schema{Type: "integer", Format: "int64"}
+	case "int8", "int16", "int32", "uint8", "uint16", "uint32":
+		return &Schema{Type: "integer", Format: "int32"}
+	case "float32":
+		return &Schema{Type: "number", Format: "float"}
+	case "float64":
+		return &Schema{Type: "number", Format: "double"}
+	case "any":
+		return &Schema{}
+	}
+	return nil
+}
+
+// schemaName names the component schema of a type after its package path
+// within the module, such as "auth.User" or "api.v1.auth.LoginRequest".
+func (g *generator) schemaName(decl *typeDecl) string {
+	rel := strings.TrimPrefix(strings.TrimPrefix(decl.pkg.path, g.module), "/")
+	rel = strings.TrimPrefix(rel, "internal/")
+	if rel == "" {
+		return decl.spec.Name.Name
+	}
+	return strings.ReplaceAll(rel, "/", ".") + "." + decl.spec.Name.Name
+}
+
+// namedSchema returns a reference to the component schema of a struct type,
+// adding it on first use. Other named types are inlined.
+func (g *generator) namedSchema(decl *typeDecl) (*Schema, error) {
+	st, ok := decl.spec.Type.(*ast.StructType)
+	if !ok {
+		schema, err := g.exprSchema(decl, decl.spec.Type)
+		if err != nil {
+			return nil, err
+		}
+		schema.Enum = decl.pkg.consts[decl.spec.Name.Name]
+		return schema, nil
+	}
+
+	name := g.schemaName(decl)
+	ref := &Schema{Ref: "#/components/schemas/" + name}
+	if _, ok := g.doc.Components.Schemas[name]; ok {
+		return ref, nil
+	}
+	// Reserve the name first so recursive types terminate.
+	g.doc.Components.Schemas[name] = &Schema{}
+	schema, err := g.structSchema(decl, st)
+	if err != nil {
+		return nil, err
+	}
+	g.doc.Components.Schemas[name] = schema
+	return ref, nil
+}
+
+// structSchema returns the object schema of a struct as encoding/json would
+// marshal it, with the constraints of its validate tags.
+func (g *generator) structSchema(scope *typeDecl, st *ast.StructType) (*Schema, error) {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	for _, field := range st.Fields.List {
+		tag := structTag(field)
+		jsonName, jsonOpts, _ := strings.Cut(tag.Get("json"), ",")
+		if jsonName == "-" && jsonOpts == "" {
+			continue
+		}
+
+		if len(field.Names) == 0 && jsonName == "" {
+			embedded, err := g.embeddedSchema(scope, field.Type)
+			if err != nil {
+				return nil, err
+			}
+			for name, prop := range embedded.Properties {
+				if _, ok := schema.Properties[name]; !ok {
+					schema.Properties[name] = prop
+				}
+			}
+			schema.Required = append(schema.Required, embedded.Required...)
+			continue
+		}
+
+		names := field.Names
+		if len(names) == 0 {
+			names = []*ast.Ident{ast.NewIdent(typeName(field.Type))}
+		}
+		for _, ident := range names {
+			if !ident.IsExported() {
+				continue
+			}
+			name := jsonName
+			if name == "" {
+				name = ident.Name
+			}
+			prop, err := g.exprSchema(scope, field.Type)
+			if err != nil {
+				return nil, err
+			}
+			if strings.Contains(jsonOpts, "string") && prop.Ref == "" {
+				prop = &Schema{Type: "string"}
+			}
+			prop = withDescription(prop, fieldDescription(field))
+			rules := tag.Get("validate")
+			if applyValidation(prop, rules) || alwaysPresent(field.Type, jsonOpts, rules) {
+				schema.Required = append(schema.Required, name)
+			}
+			schema.Properties[name] = prop
+		}
+	}
+	sort.Strings(schema.Required)
+	return schema, nil
+}
+
+// embeddedSchema returns the inline object schema of an embedded struct, whose
+// fields encoding/json promotes into the embedding struct.
+func (g *generator) embeddedSchema(scope *typeDecl, expr ast.Expr) (*Schema, error) {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	var decl *typeDecl
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		decl = scope.pkg.types[expr.Name]
+	case *ast.SelectorExpr:
+		if ident, ok := expr.X.(*ast.Ident); ok {
+			pkg, _, err := g.resolveImport(scope, ident.Name, expr.Sel.Name)
+			if err != nil {
+				return nil, err
+			}
+			if pkg != nil {
+				decl = pkg.types[expr.Sel.Name]
+			}
+		}
+	}
+	if decl == nil {
+		return nil, fmt.Errorf("unsupported embedded type %s", typeName(expr))
+	}
+	st, ok := decl.spec.Type.(*ast.StructType)
+	if !ok {
+		return nil, fmt.Errorf("embedded type %s is not a struct", decl.spec.Name.Name)
+	}
+	return g.structSchema(decl, st)
+}
+
+func typeName(expr ast.Expr) string {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		return expr.Name
+	case *ast.SelectorExpr:
+		return expr.Sel.Name
+	case *ast.StarExpr:
+		return typeName(expr.X)
+	}
+	return fmt.Sprintf("%T", expr)
+}
+
+func structTag(field *ast.Field) reflect.StructTag {
+	if field.Tag == nil {
+		return ""
+	}
+	tag, _ := strconv.Unquote(field.Tag.Value)
+	return reflect.StructTag(tag)
+}
+
+func fieldDescription(field *ast.Field) string {
+	for _, group := range []*ast.CommentGroup{field.Doc, field.Comment} {
+		if text := strings.Join(strings.Fields(group.Text()), " "); text != "" {
+			return text
+		}
+	}
+	return ""
+}
+
+// withDescription sets the description of a property. OpenAPI 3.0 ignores
+// the siblings of $ref, so references are left undescribed.
+func withDescription(schema *Schema, description string) *Schema {
+	if description != "" && schema.Ref == "" {
+		schema.Description = description
+	}
+	return schema
+}
+
+// alwaysPresent reports whether a field is present in every value of its
+// struct: encoding/json always marshals it, and requests may only omit it
+// when its validation rules allow.
+func alwaysPresent(typ ast.Expr, jsonOpts, rules string) bool {
+	if _, ok := typ.(*ast.StarExpr); ok {
+		return false
+	}
+	for _, opt := range strings.Split(jsonOpts, ",") {
+		if opt == "omitempty" {
+			return false
+		}
+	}
+	rule, _, _ := strings.Cut(rules, ",")
+	return rule != "omitempty"
+}
+
+// applyValidation maps the go-playground/validator rules of a field onto its
+// schema and reports whether the field is required. Rules after "dive" apply
+// to elements and are ignored.
+func applyValidation(schema *Schema, rules string) bool {
+	required := false
+	for _, rule := range strings.Split(rules, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "dive":
+			return required
+		case "required":
+			required = true
+		case "email":
+			schema.Format = "email"
+		case "url", "http_url":
+			schema.Format = "uri"
+		case "uuid":
+			schema.Format = "uuid"
+		case "oneof":
+			for _, v := range strings.Fields(param) {
+				if n, err := strconv.ParseInt(v, 10, 64); err == nil && schema.Type == "integer" {
+					schema.Enum = append(schema.Enum, n)
+				} else {
+					schema.Enum = append(schema.Enum, v)
+				}
+			}
+		case "min", "max", "gte", "lte":
+			n, err := strconv.ParseFloat(param, 64)
+			if err != nil {
+				continue
+			}
+			lower := name == "min" || name == "gte"
+			switch schema.Type {
+			case "string":
+				setInt(lower, &schema.MinLength, &schema.MaxLength, n)
+			case "array":
+				setInt(lower, &schema.MinItems, &schema.MaxItems, n)
+			case "integer", "number":
+				if lower {
+					schema.Minimum = &n
+				} else {
+					schema.Maximum = &n
+				}
+			}
+		}
+	}
+	return required
+}
+
+func setInt(lower bool, min, max **int, n float64) {
+	v := int(n)
+	if lower {
+		*min = &v
+	} else {
+		*max = &v
+	}
+}
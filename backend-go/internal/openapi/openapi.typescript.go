@@ -0,0 +1,374 @@
+package openapi
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// TypeScript renders doc as a TypeScript module for the frontend: one type
+// per component schema and a fetch based client with one method per
+// operation, named after the operation ID.
+func TypeScript(doc *Document) ([]byte, error) {
+	w := &tsWriter{names: tsTypeNames(doc.Components.Schemas)}
+
+	w.line("// Code generated by cmd/openapi from the OpenAPI document of the API; DO NOT EDIT.")
+	w.line("// Regenerate with `go generate ./api` in backend-go.")
+	w.line("")
+
+	schemaNames := sortedKeys(doc.Components.Schemas)
+	sort.Slice(schemaNames, func(i, j int) bool {
+		return w.names[schemaNames[i]] < w.names[schemaNames[j]]
+	})
+	for _, name := range schemaNames {
+		schema := doc.Components.Schemas[name]
+		w.comment("", schema.Description)
+		w.line("export interface %s %s", w.names[name], w.objectType(schema, ""))
+		w.line("")
+	}
+
+	w.line(tsRuntime)
+
+	w.line("export function createClient(options: ClientOptions) {")
+	w.line("  const request = requester(options);")
+	w.line("  return {")
+	for _, path := range sortedKeys(doc.Paths) {
+		item := doc.Paths[path]
+		for _, method := range sortedKeys(item) {
+			if err := w.operation(path, method, item[method]); err != nil {
+				return nil, fmt.Errorf("%s %s: %w", strings.ToUpper(method), path, err)
+			}
+		}
+	}
+	w.line("  };")
+	w.line("}")
+	w.line("")
+	w.line("export type Client = ReturnType<typeof createClient>;")
+	return w.buf.Bytes(), nil
+}
+
+// tsRuntime is the hand-written part of the client shared by all operations.
+const tsRuntime = `/** Body of an error response; most carry a message in error. */
+export type ErrorBody = { error?: string } & Record<string, unknown>;
+
+/** ApiError is thrown for every response with a non-2xx status. */
+export class ApiError extends Error {
+  readonly status: number;
+  readonly body: ErrorBody;
+
+  constructor(status: number, body: ErrorBody) {
+    super(body.error ?? ` + "`Request failed with status ${status}`" + `);
+    this.name = "ApiError";
+    this.status = status;
+    this.body = body;
+  }
+}
+
+export interface ClientOptions {
+  /** Origin of the API, such as "http://localhost:8080". */
+  baseUrl: string;
+  /** Returns the login or personal access token sent to protected operations. */
+  token?: () => string | null | undefined;
+  fetch?: typeof fetch;
+}
+
+type Query = Record<string, string | number | boolean | undefined>;
+
+interface RequestOptions {
+  query?: Query;
+  body?: unknown;
+  auth?: boolean;
+  blob?: boolean;
+}
+
+function requester(options: ClientOptions) {
+  const doFetch = options.fetch ?? fetch;
+  return async <T>(method: string, path: string, init: RequestOptions = {}): Promise<T> => {
+    const url = new URL(path, options.baseUrl);
+    for (const [key, value] of Object.entries(init.query ?? {})) {
+      if (value !== undefined && value !== "") {
+        url.searchParams.set(key, String(value));
+      }
+    }
+
+    const headers: Record<string, string> = { Accept: "application/json" };
+    if (init.body !== undefined) {
+      headers["Content-Type"] = "application/json";
+    }
+    const token = init.auth ? options.token?.() : undefined;
+    if (token) {
+      headers.Authorization = ` + "`Bearer ${token}`" + `;
+    }
+
+    const response = await doFetch(url, {
+      method,
+      headers,
+      body: init.body === undefined ? undefined : JSON.stringify(init.body),
+    });
+    if (!response.ok) {
+      const body = await response.json().catch(() => ({ error: response.statusText }));
+      throw new ApiError(response.status, body as ErrorBody);
+    }
+    if (init.blob) {
+      return (await response.blob()) as T;
+    }
+    if (response.status === 204) {
+      return undefined as T;
+    }
+    return (await response.json()) as T;
+  };
+}
+`
+
+type tsWriter struct {
+	buf   bytes.Buffer
+	names map[string]string // component schema name to TypeScript type name
+}
+
+func (w *tsWriter) line(format string, args ...any) {
+	fmt.Fprintf(&w.buf, format, args...)
+	w.buf.WriteByte('\n')
+}
+
+// comment writes text as a JSDoc comment at indent.
+func (w *tsWriter) comment(indent, text string) {
+	text = strings.ReplaceAll(strings.TrimSpace(text), "*/", "*\\/")
+	if text == "" {
+		return
+	}
+	lines := strings.Split(text, "\n")
+	if len(lines) == 1 {
+		w.line("%s/** %s */", indent, lines[0])
+		return
+	}
+	w.line("%s/**", indent)
+	for _, l := range lines {
+		w.line("%s", strings.TrimRight(indent+" * "+l, " "))
+	}
+	w.line("%s */", indent)
+}
+
+// tsTypeNames names each component schema after its Go type, qualified with
+// its package path only where two packages declare the same name.
+func tsTypeNames(schemas map[string]*Schema) map[string]string {
+	count := make(map[string]int)
+	for name := range schemas {
+		count[lastSegment(name)]++
+	}
+	names := make(map[string]string, len(schemas))
+	for name := range schemas {
+		short := lastSegment(name)
+		if count[short] == 1 {
+			names[name] = short
+			continue
+		}
+		var qualified strings.Builder
+		for _, segment := range strings.Split(name, ".") {
+			if segment == "api" || segment == "v1" {
+				continue
+			}
+			qualified.WriteString(upperFirst(segment))
+		}
+		names[name] = qualified.String()
+	}
+	return names
+}
+
+func lastSegment(name string) string {
+	return name[strings.LastIndex(name, ".")+1:]
+}
+
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	r := []rune(s)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	r := []rune(s)
+	r[0] = unicode.ToLower(r[0])
+	return string(r)
+}
+
+var tsIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+func tsPropertyName(name string) string {
+	if tsIdentifier.MatchString(name) {
+		return name
+	}
+	return strconv.Quote(name)
+}
+
+// typeOf returns the TypeScript type of schema.
+func (w *tsWriter) typeOf(schema *Schema, indent string) string {
+	if schema == nil {
+		return "unknown"
+	}
+	if schema.Ref != "" {
+		return w.names[strings.TrimPrefix(schema.Ref, "#/components/schemas/")]
+	}
+	if len(schema.Enum) > 0 {
+		literals := make([]string, len(schema.Enum))
+		for i, v := range schema.Enum {
+			if s, ok := v.(string); ok {
+				literals[i] = strconv.Quote(s)
+			} else {
+				literals[i] = fmt.Sprint(v)
+			}
+		}
+		return strings.Join(literals, " | ")
+	}
+	switch schema.Type {
+	case "string":
+		if schema.Format == "binary" {
+			return "Blob"
+		}
+		return "string"
+	case "integer", "number":
+		return "number"
+	case "boolean":
+		return "boolean"
+	case "array":
+		items := w.typeOf(schema.Items, indent)
+		if strings.Contains(items, " | ") {
+			items = "(" + items + ")"
+		}
+		return items + "[]"
+	case "object":
+		if len(schema.Properties) == 0 {
+			return "Record<string, " + w.typeOf(schema.AdditionalProperties, indent) + ">"
+		}
+		return w.objectType(schema, indent)
+	}
+	return "unknown"
+}
+
+// objectType returns an object literal type with the properties of schema.
+// Properties that are not required are optional.
+func (w *tsWriter) objectType(schema *Schema, indent string) string {
+	required := make(map[string]bool, len(schema.Required))
+	for _, name := range schema.Required {
+		required[name] = true
+	}
+
+	inner := &tsWriter{names: w.names}
+	inner.line("{")
+	for _, name := range sortedKeys(schema.Properties) {
+		prop := schema.Properties[name]
+		optional := "?"
+		if required[name] {
+			optional = ""
+		}
+		inner.comment(indent+"  ", prop.Description)
+		inner.line("%s  %s%s: %s;", indent, tsPropertyName(name), optional, w.typeOf(prop, indent+"  "))
+	}
+	inner.buf.WriteString(indent + "}")
+	return inner.buf.String()
+}
+
+var pathParamPattern = regexp.MustCompile(`\{(\w+)\}`)
+
+// operation writes the client method of one operation. Its arguments are the
+// path parameters in order, the request body and an object of query
+// parameters, which is optional when none of them is required.
+func (w *tsWriter) operation(path, method string, op *Operation) error {
+	var args []string
+	var query []*Parameter
+	queryRequired := false
+	for _, param := range op.Parameters {
+		switch param.In {
+		case "path":
+			args = append(args, fmt.Sprintf("%s: %s", param.Name, w.typeOf(param.Schema, "")))
+		case "query":
+			query = append(query, param)
+			queryRequired = queryRequired || param.Required
+		default:
+			return fmt.Errorf("unsupported parameter location %q", param.In)
+		}
+	}
+
+	var init []string
+	if op.RequestBody != nil {
+		media, ok := op.RequestBody.Content["application/json"]
+		if !ok {
+			return fmt.Errorf("unsupported request body")
+		}
+		args = append(args, "body: "+w.typeOf(media.Schema, "    "))
+		init = append(init, "body")
+	}
+	if len(query) > 0 {
+		var fields []string
+		for _, param := range query {
+			optional := "?"
+			if param.Required {
+				optional = ""
+			}
+			fields = append(fields, fmt.Sprintf("%s%s: %s", tsPropertyName(param.Name), optional, w.typeOf(param.Schema, "")))
+		}
+		optional := "?"
+		if queryRequired {
+			optional = ""
+		}
+		args = append(args, fmt.Sprintf("query%s: { %s }", optional, strings.Join(fields, "; ")))
+		init = append(init, "query")
+	}
+	if len(op.Security) > 0 {
+		init = append(init, "auth: true")
+	}
+
+	result, blob := w.resultType(op)
+	if blob {
+		init = append(init, "blob: true")
+	}
+
+	url := strconv.Quote(path)
+	if pathParamPattern.MatchString(path) {
+		url = "`" + pathParamPattern.ReplaceAllString(path, "$${encodeURIComponent($1)}") + "`"
+	}
+	call := fmt.Sprintf("request<%s>(%q, %s", result, strings.ToUpper(method), url)
+	if len(init) > 0 {
+		call += ", { " + strings.Join(init, ", ") + " }"
+	}
+
+	w.comment("    ", strings.TrimSpace(op.Summary+"\n\n"+op.Description))
+	w.line("    %s: (%s) =>", lowerFirst(op.OperationID), strings.Join(args, ", "))
+	w.line("      %s),", call)
+	return nil
+}
+
+// resultType returns the TypeScript type of the first successful response
+// and whether it is a file downloaded as a Blob.
+func (w *tsWriter) resultType(op *Operation) (string, bool) {
+	for _, code := range sortedKeys(op.Responses) {
+		if !strings.HasPrefix(code, "2") {
+			continue
+		}
+		for _, media := range op.Responses[code].Content {
+			if media.Schema != nil && media.Schema.Format == "binary" {
+				return "Blob", true
+			}
+			return w.typeOf(media.Schema, "    "), false
+		}
+		return "void", false
+	}
+	return "void", false
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,37 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+)
+
+// Cursor is the position of the last item of a page in the
+// (created_at desc, id desc) ordering used by ListUsers, audit event and note listings.
+type Cursor struct {
+	CreatedAt int64
+	ID        string
+}
+
+// Encode returns the opaque string form of the cursor handed to clients.
+func (c Cursor) Encode() string {
+	raw := strconv.FormatInt(c.CreatedAt, 10) + ":" + c.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor parses a cursor produced by Cursor.Encode.
+func DecodeCursor(s string) (Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	createdAt, id, found := strings.Cut(string(raw), ":")
+	if !found || id == "" {
+		return Cursor{}, ErrInvalidCursor
+	}
+	ts, err := strconv.ParseInt(createdAt, 10, 64)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	return Cursor{CreatedAt: ts, ID: id}, nil
+}
@@ -0,0 +1,188 @@
+package project
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	v1 "github.com/AldiandyaIrsyad/author-notes/api/v1/project"
+	app_auth "github.com/AldiandyaIrsyad/author-notes/internal/auth"
+	auth_adapter "github.com/AldiandyaIrsyad/author-notes/internal/auth/adapter"
+	app_project "github.com/AldiandyaIrsyad/author-notes/internal/project"
+)
+
+type ProjectHTTPHandler struct {
+	service app_project.ProjectService
+	logger  *slog.Logger
+}
+
+func NewProjectHTTPHandler(service app_project.ProjectService, logger *slog.Logger) *ProjectHTTPHandler {
+	return &ProjectHTTPHandler{service: service, logger: logger}
+}
+
+// internalError logs err and responds with a generic 500 error that does not leak it.
+func (h *ProjectHTTPHandler) internalError(c *gin.Context, message string, err error) {
+	h.logger.ErrorContext(c.Request.Context(), message, "route", c.FullPath(), "error", err)
+	c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+}
+
+// RegisterRoutes registers the project routes on rg, which must be authenticated.
+func (h *ProjectHTTPHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	read := auth_adapter.RequirePermission(app_auth.PermContentRead)
+	write := auth_adapter.RequirePermission(app_auth.PermContentWrite)
+
+	projectsGroup := rg.Group("/projects")
+	projectsGroup.GET("", read, h.ListProjects)
+	projectsGroup.POST("", write, h.CreateProject)
+	projectsGroup.GET("/:id", read, h.GetProject)
+	projectsGroup.PUT("/:id", write, h.UpdateProject)
+	projectsGroup.DELETE("/:id", write, h.DeleteProject)
+}
+
+// ListProjects handles the request for the caller's projects.
+// @Summary List my projects
+// @Description Returns every writing project of the caller, sorted by title.
+// @Tags projects
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} project.Project "Projects"
+// @Failure 401 {object} map[string]string "Missing or invalid token"
+// @Failure 403 {object} map[string]string "Missing permission"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/projects [get]
+func (h *ProjectHTTPHandler) ListProjects(c *gin.Context) {
+	claims, _ := app_auth.ClaimsFromContext(c.Request.Context())
+
+	projects, err := h.service.ListProjects(c.Request.Context(), claims.UserID)
+	if err != nil {
+		h.internalError(c, "Failed to list projects", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, projects)
+}
+
+// CreateProject handles the request to create a project.
+// @Summary Create a project
+// @Description Creates a writing project. Templates and notes are created inside a project. The status defaults to planning.
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param project body v1.CreateProjectRequest true "Project details"
+// @Success 201 {object} project.Project "Project created"
+// @Failure 400 {object} map[string]string "Validation error"
+// @Failure 401 {object} map[string]string "Missing or invalid token"
+// @Failure 403 {object} map[string]string "Missing permission"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/projects [post]
+func (h *ProjectHTTPHandler) CreateProject(c *gin.Context) {
+	var req v1.CreateProjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	claims, _ := app_auth.ClaimsFromContext(c.Request.Context())
+
+	project, err := h.service.CreateProject(c.Request.Context(), claims.UserID, req)
+	if err != nil {
+		h.respondError(c, "Failed to create project", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, project)
+}
+
+// GetProject handles the request for a single project.
+// @Summary Get a project
+// @Tags projects
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Success 200 {object} project.Project "Project"
+// @Failure 401 {object} map[string]string "Missing or invalid token"
+// @Failure 403 {object} map[string]string "Missing permission"
+// @Failure 404 {object} map[string]string "Project not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/projects/{id} [get]
+func (h *ProjectHTTPHandler) GetProject(c *gin.Context) {
+	claims, _ := app_auth.ClaimsFromContext(c.Request.Context())
+
+	project, err := h.service.GetProject(c.Request.Context(), claims.UserID, c.Param("id"))
+	if err != nil {
+		h.respondError(c, "Failed to get project", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, project)
+}
+
+// UpdateProject handles the request to update a project.
+// @Summary Update a project
+// @Description Replaces the title, genre, synopsis, target word count, cover image and status of a project.
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Param project body v1.UpdateProjectRequest true "New project details"
+// @Success 200 {object} project.Project "Updated project"
+// @Failure 400 {object} map[string]string "Validation error"
+// @Failure 401 {object} map[string]string "Missing or invalid token"
+// @Failure 403 {object} map[string]string "Missing permission"
+// @Failure 404 {object} map[string]string "Project not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/projects/{id} [put]
+func (h *ProjectHTTPHandler) UpdateProject(c *gin.Context) {
+	var req v1.UpdateProjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	claims, _ := app_auth.ClaimsFromContext(c.Request.Context())
+
+	project, err := h.service.UpdateProject(c.Request.Context(), claims.UserID, c.Param("id"), req)
+	if err != nil {
+		h.respondError(c, "Failed to update project", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, project)
+}
+
+// DeleteProject handles the request to delete a project.
+// @Summary Delete a project
+// @Description Deletes a project together with every template and note inside it.
+// @Tags projects
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Success 204 "Project deleted"
+// @Failure 401 {object} map[string]string "Missing or invalid token"
+// @Failure 403 {object} map[string]string "Missing permission"
+// @Failure 404 {object} map[string]string "Project not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/projects/{id} [delete]
+func (h *ProjectHTTPHandler) DeleteProject(c *gin.Context) {
+	claims, _ := app_auth.ClaimsFromContext(c.Request.Context())
+
+	if err := h.service.DeleteProject(c.Request.Context(), claims.UserID, c.Param("id")); err != nil {
+		h.respondError(c, "Failed to delete project", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// respondError maps the errors of the project service to HTTP responses.
+func (h *ProjectHTTPHandler) respondError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, app_project.ErrValidationFailed):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, app_project.ErrProjectNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		h.internalError(c, message, err)
+	}
+}
@@ -0,0 +1,93 @@
+package project
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	app_project "github.com/AldiandyaIrsyad/author-notes/internal/project"
+)
+
+// mongoProjectRepository implements the ProjectRepository interface using MongoDB.
+type mongoProjectRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoProjectRepository creates a new instance of mongoProjectRepository.
+func NewMongoProjectRepository(db *mongo.Database) app_project.ProjectRepository {
+	return &mongoProjectRepository{
+		collection: db.Collection("projects"),
+	}
+}
+
+// CreateProject inserts a new project.
+func (r *mongoProjectRepository) CreateProject(ctx context.Context, project *app_project.Project) error {
+	if project.ID == "" {
+		project.ID = uuid.NewString()
+	}
+	_, err := r.collection.InsertOne(ctx, project)
+	return err
+}
+
+// UpdateProject replaces a stored project with the given one.
+func (r *mongoProjectRepository) UpdateProject(ctx context.Context, project *app_project.Project) error {
+	result, err := r.collection.ReplaceOne(ctx, bson.M{"_id": project.ID, "owner_id": project.OwnerID}, project)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return app_project.ErrProjectNotFound
+	}
+	return nil
+}
+
+// FindProjectByID retrieves a project by its ID.
+func (r *mongoProjectRepository) FindProjectByID(ctx context.Context, id string) (*app_project.Project, error) {
+	var project app_project.Project
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&project)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, app_project.ErrProjectNotFound
+		}
+		return nil, err
+	}
+	return &project, nil
+}
+
+// ListProjectsByOwner returns every project of an owner, sorted by title.
+func (r *mongoProjectRepository) ListProjectsByOwner(ctx context.Context, ownerID string) ([]*app_project.Project, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "title", Value: 1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"owner_id": ownerID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	projects := []*app_project.Project{}
+	if err := cursor.All(ctx, &projects); err != nil {
+		return nil, err
+	}
+	return projects, nil
+}
+
+// DeleteProject removes a project of an owner.
+func (r *mongoProjectRepository) DeleteProject(ctx context.Context, ownerID, id string) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id, "owner_id": ownerID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return app_project.ErrProjectNotFound
+	}
+	return nil
+}
+
+// DeleteProjectsByOwner removes every project of an owner.
+func (r *mongoProjectRepository) DeleteProjectsByOwner(ctx context.Context, ownerID string) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"owner_id": ownerID})
+	return err
+}
@@ -0,0 +1,12 @@
+package project
+
+import "context"
+
+// ContentRemover is implemented by every domain that stores content inside
+// projects, so that deleting a project reaches all of it. The services of such
+// domains embed it in their interface: their content is deleted with its
+// project.
+type ContentRemover interface {
+	// DeleteProjectContent permanently removes every document of the project.
+	DeleteProjectContent(ctx context.Context, projectID string) error
+}
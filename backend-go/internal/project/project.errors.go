@@ -0,0 +1,8 @@
+package project
+
+import "errors"
+
+var (
+	ErrProjectNotFound  = errors.New("project not found")
+	ErrValidationFailed = errors.New("input validation failed")
+)
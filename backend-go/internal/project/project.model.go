@@ -0,0 +1,27 @@
+package project
+
+// Status is the stage a project is in.
+type Status string
+
+const (
+	StatusPlanning  Status = "planning"
+	StatusDrafting  Status = "drafting"
+	StatusRevising  Status = "revising"
+	StatusCompleted Status = "completed"
+	StatusOnHold    Status = "on_hold"
+)
+
+// Project is a user-owned writing project, such as a book or a series. The
+// templates and notes of an author live inside one of their projects.
+type Project struct {
+	ID              string `bson:"_id,omitempty" json:"id"`
+	OwnerID         string `bson:"owner_id" json:"owner_id"`
+	Title           string `bson:"title" json:"title"`
+	Genre           string `bson:"genre,omitempty" json:"genre,omitempty"`
+	Synopsis        string `bson:"synopsis,omitempty" json:"synopsis,omitempty"`
+	TargetWordCount int    `bson:"target_word_count,omitempty" json:"target_word_count,omitempty"`
+	CoverImageURL   string `bson:"cover_image_url,omitempty" json:"cover_image_url,omitempty"`
+	Status          Status `bson:"status" json:"status"`
+	CreatedAt       int64  `bson:"created_at" json:"created_at"`
+	UpdatedAt       int64  `bson:"updated_at" json:"updated_at"`
+}
@@ -0,0 +1,22 @@
+package project
+
+import "context"
+
+// ProjectRepository defines the interface for project database operations.
+type ProjectRepository interface {
+	// CreateProject inserts a new project, assigning its ID.
+	CreateProject(ctx context.Context, project *Project) error
+	// UpdateProject replaces a stored project with the given one.
+	// Returns ErrProjectNotFound if the project does not exist.
+	UpdateProject(ctx context.Context, project *Project) error
+	// FindProjectByID retrieves a project by its ID.
+	// Returns ErrProjectNotFound if the project does not exist.
+	FindProjectByID(ctx context.Context, id string) (*Project, error)
+	// ListProjectsByOwner returns every project of an owner, sorted by title.
+	ListProjectsByOwner(ctx context.Context, ownerID string) ([]*Project, error)
+	// DeleteProject removes a project of an owner.
+	// Returns ErrProjectNotFound if the owner has no such project.
+	DeleteProject(ctx context.Context, ownerID, id string) error
+	// DeleteProjectsByOwner removes every project of an owner.
+	DeleteProjectsByOwner(ctx context.Context, ownerID string) error
+}
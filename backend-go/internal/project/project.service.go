@@ -0,0 +1,158 @@
+package project
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+
+	v1 "github.com/AldiandyaIrsyad/author-notes/api/v1/project"
+	"github.com/AldiandyaIrsyad/author-notes/internal/account"
+)
+
+// ProjectService defines the interface for managing writing projects. Every
+// method is scoped to the owner: projects of other users are reported as not found.
+type ProjectService interface {
+	// CreateProject creates a project, in the planning status unless another is given.
+	CreateProject(ctx context.Context, ownerID string, req v1.CreateProjectRequest) (*Project, error)
+	// GetProject returns a project of the owner.
+	GetProject(ctx context.Context, ownerID, id string) (*Project, error)
+	// ListProjects returns every project of the owner, sorted by title.
+	ListProjects(ctx context.Context, ownerID string) ([]*Project, error)
+	// UpdateProject replaces the details of a project.
+	UpdateProject(ctx context.Context, ownerID, id string, req v1.UpdateProjectRequest) (*Project, error)
+	// DeleteProject deletes a project of the owner and everything inside it.
+	DeleteProject(ctx context.Context, ownerID, id string) error
+
+	account.ContentProvider
+}
+
+type projectService struct {
+	repo      ProjectRepository
+	removers  []ContentRemover
+	validator *validator.Validate
+}
+
+// NewProjectService creates a new instance of ProjectService. Deleting a
+// project deletes its content from every remover.
+func NewProjectService(repo ProjectRepository, removers ...ContentRemover) ProjectService {
+	return &projectService{
+		repo:      repo,
+		removers:  removers,
+		validator: validator.New(),
+	}
+}
+
+// CreateProject validates and stores a new project.
+func (s *projectService) CreateProject(ctx context.Context, ownerID string, req v1.CreateProjectRequest) (*Project, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, ErrValidationFailed
+	}
+
+	now := time.Now().Unix()
+	project := &Project{
+		OwnerID:         ownerID,
+		Title:           strings.TrimSpace(req.Title),
+		Genre:           strings.TrimSpace(req.Genre),
+		Synopsis:        strings.TrimSpace(req.Synopsis),
+		TargetWordCount: req.TargetWordCount,
+		CoverImageURL:   req.CoverImageURL,
+		Status:          Status(req.Status),
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	if project.Status == "" {
+		project.Status = StatusPlanning
+	}
+	if project.Title == "" {
+		return nil, ErrValidationFailed
+	}
+
+	if err := s.repo.CreateProject(ctx, project); err != nil {
+		return nil, err
+	}
+	return project, nil
+}
+
+// GetProject returns a project if it belongs to the owner.
+func (s *projectService) GetProject(ctx context.Context, ownerID, id string) (*Project, error) {
+	project, err := s.repo.FindProjectByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if project.OwnerID != ownerID {
+		return nil, ErrProjectNotFound
+	}
+	return project, nil
+}
+
+// ListProjects returns the projects of the owner.
+func (s *projectService) ListProjects(ctx context.Context, ownerID string) ([]*Project, error) {
+	return s.repo.ListProjectsByOwner(ctx, ownerID)
+}
+
+// UpdateProject validates and stores the new details of a project.
+func (s *projectService) UpdateProject(ctx context.Context, ownerID, id string, req v1.UpdateProjectRequest) (*Project, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, ErrValidationFailed
+	}
+
+	project, err := s.GetProject(ctx, ownerID, id)
+	if err != nil {
+		return nil, err
+	}
+	project.Title = strings.TrimSpace(req.Title)
+	project.Genre = strings.TrimSpace(req.Genre)
+	project.Synopsis = strings.TrimSpace(req.Synopsis)
+	project.TargetWordCount = req.TargetWordCount
+	project.CoverImageURL = req.CoverImageURL
+	project.Status = Status(req.Status)
+	project.UpdatedAt = time.Now().Unix()
+	if project.Title == "" {
+		return nil, ErrValidationFailed
+	}
+
+	if err := s.repo.UpdateProject(ctx, project); err != nil {
+		return nil, err
+	}
+	return project, nil
+}
+
+// DeleteProject removes the content of a project of the owner, then the
+// project itself, so that a failed delete can be retried.
+func (s *projectService) DeleteProject(ctx context.Context, ownerID, id string) error {
+	if _, err := s.GetProject(ctx, ownerID, id); err != nil {
+		return err
+	}
+	for _, remover := range s.removers {
+		if err := remover.DeleteProjectContent(ctx, id); err != nil {
+			return err
+		}
+	}
+	return s.repo.DeleteProject(ctx, ownerID, id)
+}
+
+// Name identifies projects in data exports.
+func (s *projectService) Name() string {
+	return "projects"
+}
+
+// ExportUserContent returns every project of the user.
+func (s *projectService) ExportUserContent(ctx context.Context, userID string) ([]account.ExportDocument, error) {
+	projects, err := s.repo.ListProjectsByOwner(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	docs := make([]account.ExportDocument, len(projects))
+	for i, project := range projects {
+		docs[i] = account.ExportDocument{ID: project.ID, Data: project}
+	}
+	return docs, nil
+}
+
+// DeleteUserContent removes every project of the user. Their content is
+// removed by the content providers of its own domains.
+func (s *projectService) DeleteUserContent(ctx context.Context, userID string) error {
+	return s.repo.DeleteProjectsByOwner(ctx, userID)
+}
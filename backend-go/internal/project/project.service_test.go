@@ -0,0 +1,209 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	v1 "github.com/AldiandyaIrsyad/author-notes/api/v1/project"
+)
+
+// MockProjectRepository is a mock implementation of ProjectRepository
+type MockProjectRepository struct {
+	mock.Mock
+}
+
+func (m *MockProjectRepository) CreateProject(ctx context.Context, project *Project) error {
+	args := m.Called(ctx, project)
+	return args.Error(0)
+}
+
+func (m *MockProjectRepository) UpdateProject(ctx context.Context, project *Project) error {
+	args := m.Called(ctx, project)
+	return args.Error(0)
+}
+
+func (m *MockProjectRepository) FindProjectByID(ctx context.Context, id string) (*Project, error) {
+	args := m.Called(ctx, id)
+	if project := args.Get(0); project != nil {
+		return project.(*Project), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockProjectRepository) ListProjectsByOwner(ctx context.Context, ownerID string) ([]*Project, error) {
+	args := m.Called(ctx, ownerID)
+	if projects := args.Get(0); projects != nil {
+		return projects.([]*Project), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockProjectRepository) DeleteProject(ctx context.Context, ownerID, id string) error {
+	args := m.Called(ctx, ownerID, id)
+	return args.Error(0)
+}
+
+func (m *MockProjectRepository) DeleteProjectsByOwner(ctx context.Context, ownerID string) error {
+	args := m.Called(ctx, ownerID)
+	return args.Error(0)
+}
+
+// MockContentRemover is a mock implementation of ContentRemover
+type MockContentRemover struct {
+	mock.Mock
+}
+
+func (m *MockContentRemover) DeleteProjectContent(ctx context.Context, projectID string) error {
+	args := m.Called(ctx, projectID)
+	return args.Error(0)
+}
+
+func TestProjectService_CreateProject(t *testing.T) {
+	mockRepo := new(MockProjectRepository)
+	service := NewProjectService(mockRepo)
+	ctx := context.Background()
+
+	t.Run("Success - Starts In Planning", func(t *testing.T) {
+		req := v1.CreateProjectRequest{
+			Title:           " The Long Winter ",
+			Genre:           "Fantasy",
+			TargetWordCount: 90000,
+			CoverImageURL:   "https://example.com/cover.png",
+		}
+		mockRepo.On("CreateProject", ctx, mock.AnythingOfType("*project.Project")).Return(nil).Once()
+
+		project, err := service.CreateProject(ctx, "user123", req)
+
+		require.NoError(t, err)
+		assert.Equal(t, "The Long Winter", project.Title)
+		assert.Equal(t, "user123", project.OwnerID)
+		assert.Equal(t, StatusPlanning, project.Status)
+		assert.Equal(t, 90000, project.TargetWordCount)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Unknown Status", func(t *testing.T) {
+		_, err := service.CreateProject(ctx, "user123", v1.CreateProjectRequest{Title: "Novel", Status: "abandoned"})
+
+		assert.ErrorIs(t, err, ErrValidationFailed)
+	})
+
+	t.Run("Invalid Cover Image URL", func(t *testing.T) {
+		_, err := service.CreateProject(ctx, "user123", v1.CreateProjectRequest{Title: "Novel", CoverImageURL: "cover.png"})
+
+		assert.ErrorIs(t, err, ErrValidationFailed)
+	})
+
+	t.Run("Cover Image URL Must Be HTTP", func(t *testing.T) {
+		for _, coverURL := range []string{"javascript:alert(1)", "data:text/html,<script>alert(1)</script>", "file:///etc/passwd", "ftp://example.com/cover.png"} {
+			_, err := service.CreateProject(ctx, "user123", v1.CreateProjectRequest{Title: "Novel", CoverImageURL: coverURL})
+
+			assert.ErrorIs(t, err, ErrValidationFailed, coverURL)
+		}
+	})
+
+	t.Run("Blank Title", func(t *testing.T) {
+		_, err := service.CreateProject(ctx, "user123", v1.CreateProjectRequest{Title: "   "})
+
+		assert.ErrorIs(t, err, ErrValidationFailed)
+	})
+}
+
+func TestProjectService_UpdateProject(t *testing.T) {
+	mockRepo := new(MockProjectRepository)
+	service := NewProjectService(mockRepo)
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		stored := &Project{ID: "novel", OwnerID: "user123", Title: "Novel", Status: StatusPlanning}
+		mockRepo.On("FindProjectByID", ctx, "novel").Return(stored, nil).Once()
+		mockRepo.On("UpdateProject", ctx, stored).Return(nil).Once()
+
+		project, err := service.UpdateProject(ctx, "user123", "novel", v1.UpdateProjectRequest{Title: "Novel", Status: "drafting"})
+
+		require.NoError(t, err)
+		assert.Equal(t, StatusDrafting, project.Status)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Cover Image URL Must Be HTTP", func(t *testing.T) {
+		_, err := service.UpdateProject(ctx, "user123", "novel", v1.UpdateProjectRequest{Title: "Novel", Status: "drafting", CoverImageURL: "javascript:alert(1)"})
+
+		assert.ErrorIs(t, err, ErrValidationFailed)
+		mockRepo.AssertNumberOfCalls(t, "UpdateProject", 1)
+	})
+
+	t.Run("Other Owner", func(t *testing.T) {
+		mockRepo.On("FindProjectByID", ctx, "novel").Return(&Project{ID: "novel", OwnerID: "someone"}, nil).Once()
+
+		_, err := service.UpdateProject(ctx, "user123", "novel", v1.UpdateProjectRequest{Title: "Mine", Status: "drafting"})
+
+		assert.ErrorIs(t, err, ErrProjectNotFound)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestProjectService_DeleteProject(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success - Removes Content First", func(t *testing.T) {
+		mockRepo := new(MockProjectRepository)
+		notes, templates := new(MockContentRemover), new(MockContentRemover)
+		service := NewProjectService(mockRepo, notes, templates)
+		mockRepo.On("FindProjectByID", ctx, "novel").Return(&Project{ID: "novel", OwnerID: "user123"}, nil).Once()
+		notes.On("DeleteProjectContent", ctx, "novel").Return(nil).Once()
+		templates.On("DeleteProjectContent", ctx, "novel").Return(nil).Once()
+		mockRepo.On("DeleteProject", ctx, "user123", "novel").Return(nil).Once()
+
+		require.NoError(t, service.DeleteProject(ctx, "user123", "novel"))
+		mockRepo.AssertExpectations(t)
+		notes.AssertExpectations(t)
+		templates.AssertExpectations(t)
+	})
+
+	t.Run("Failed Removal Keeps Project", func(t *testing.T) {
+		mockRepo := new(MockProjectRepository)
+		notes := new(MockContentRemover)
+		service := NewProjectService(mockRepo, notes)
+		mockRepo.On("FindProjectByID", ctx, "novel").Return(&Project{ID: "novel", OwnerID: "user123"}, nil).Once()
+		notes.On("DeleteProjectContent", ctx, "novel").Return(errors.New("connection lost")).Once()
+
+		assert.Error(t, service.DeleteProject(ctx, "user123", "novel"))
+		mockRepo.AssertNotCalled(t, "DeleteProject", ctx, "user123", "novel")
+	})
+
+	t.Run("Other Owner Removes Nothing", func(t *testing.T) {
+		mockRepo := new(MockProjectRepository)
+		notes := new(MockContentRemover)
+		service := NewProjectService(mockRepo, notes)
+		mockRepo.On("FindProjectByID", ctx, "novel").Return(&Project{ID: "novel", OwnerID: "someone"}, nil).Once()
+
+		err := service.DeleteProject(ctx, "user123", "novel")
+
+		assert.ErrorIs(t, err, ErrProjectNotFound)
+		notes.AssertNotCalled(t, "DeleteProjectContent", ctx, "novel")
+	})
+}
+
+func TestProjectService_UserContent(t *testing.T) {
+	mockRepo := new(MockProjectRepository)
+	service := NewProjectService(mockRepo)
+	ctx := context.Background()
+
+	mockRepo.On("ListProjectsByOwner", ctx, "user123").Return([]*Project{{ID: "novel"}}, nil).Once()
+	mockRepo.On("DeleteProjectsByOwner", ctx, "user123").Return(nil).Once()
+
+	docs, err := service.ExportUserContent(ctx, "user123")
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, "novel", docs[0].ID)
+	assert.Equal(t, "projects", service.Name())
+
+	require.NoError(t, service.DeleteUserContent(ctx, "user123"))
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,107 @@
+package search
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	v1 "github.com/AldiandyaIrsyad/author-notes/api/v1/search"
+	app_auth "github.com/AldiandyaIrsyad/author-notes/internal/auth"
+	auth_adapter "github.com/AldiandyaIrsyad/author-notes/internal/auth/adapter"
+	app_search "github.com/AldiandyaIrsyad/author-notes/internal/search"
+)
+
+type SearchHTTPHandler struct {
+	service app_search.SearchService
+	logger  *slog.Logger
+}
+
+func NewSearchHTTPHandler(service app_search.SearchService, logger *slog.Logger) *SearchHTTPHandler {
+	return &SearchHTTPHandler{service: service, logger: logger}
+}
+
+// internalError logs err and responds with a generic 500 error that does not leak it.
+func (h *SearchHTTPHandler) internalError(c *gin.Context, message string, err error) {
+	h.logger.ErrorContext(c.Request.Context(), message, "route", c.FullPath(), "error", err)
+	c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+}
+
+// RegisterRoutes registers the search routes on rg, which must be authenticated.
+func (h *SearchHTTPHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	read := auth_adapter.RequirePermission(app_auth.PermContentRead)
+	write := auth_adapter.RequirePermission(app_auth.PermContentWrite)
+
+	searchGroup := rg.Group("/search")
+	searchGroup.GET("", read, h.Search)
+	searchGroup.POST("/reindex", write, h.Reindex)
+}
+
+// Search handles a full-text search of the notes of the caller.
+// @Summary Search notes
+// @Description Searches the titles and text values of the notes of the caller. Results contain every word and "quoted phrase" of the query, ignoring case and punctuation, best first. Highlights hold the matching title and values, HTML-escaped, with the matches wrapped in <mark>.
+// @Tags search
+// @Produce json
+// @Security BearerAuth
+// @Param q query string true "Words and quoted phrases"
+// @Param project_id query string false "Only notes of this project"
+// @Param template_id query string false "Only notes of this template"
+// @Param offset query int false "Number of results to skip, next_offset of the previous page"
+// @Param limit query int false "Page size (1-50, default 20)"
+// @Success 200 {object} search.Result "Results"
+// @Failure 400 {object} map[string]string "Invalid or empty query"
+// @Failure 401 {object} map[string]string "Missing or invalid token"
+// @Failure 403 {object} map[string]string "Missing permission"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/search [get]
+func (h *SearchHTTPHandler) Search(c *gin.Context) {
+	var req v1.SearchRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query parameters: " + err.Error()})
+		return
+	}
+	claims, _ := app_auth.ClaimsFromContext(c.Request.Context())
+
+	result, err := h.service.Search(c.Request.Context(), claims.UserID, req)
+	if err != nil {
+		h.respondError(c, "Failed to search notes", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// Reindex handles the request to rebuild the search index of the caller.
+// @Summary Rebuild the search index
+// @Description Rebuilds the search index of the notes of the caller, such as after switching indexes or for notes written before search was available.
+// @Tags search
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} search.ReindexReport "Number of notes indexed"
+// @Failure 401 {object} map[string]string "Missing or invalid token"
+// @Failure 403 {object} map[string]string "Missing permission"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/search/reindex [post]
+func (h *SearchHTTPHandler) Reindex(c *gin.Context) {
+	claims, _ := app_auth.ClaimsFromContext(c.Request.Context())
+
+	report, err := h.service.Reindex(c.Request.Context(), claims.UserID)
+	if err != nil {
+		h.respondError(c, "Failed to rebuild search index", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// respondError maps the errors of the search service to HTTP responses.
+func (h *SearchHTTPHandler) respondError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, app_search.ErrValidationFailed),
+		errors.Is(err, app_search.ErrEmptyQuery):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		h.internalError(c, message, err)
+	}
+}
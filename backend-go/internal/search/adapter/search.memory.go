@@ -0,0 +1,162 @@
+package search
+
+import (
+	"cmp"
+	"context"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+
+	app_search "github.com/AldiandyaIrsyad/author-notes/internal/search"
+)
+
+// titleWeight is how much more a word of the title counts than a word of a value.
+const titleWeight = 3
+
+// memoryIndex implements the Index interface in memory, with an inverted
+// index from each word to the documents containing it. It suits single
+// process setups without a search engine; documents are lost on restart, and
+// owners get them back with a reindex.
+type memoryIndex struct {
+	mu       sync.RWMutex
+	docs     map[string]*app_search.Document
+	postings map[string]map[string]int // occurrences of a word, by note ID
+}
+
+// NewMemoryIndex creates a new, empty instance of memoryIndex.
+func NewMemoryIndex() app_search.Index {
+	return &memoryIndex{
+		docs:     make(map[string]*app_search.Document),
+		postings: make(map[string]map[string]int),
+	}
+}
+
+// words returns the words of the terms of a document.
+func words(doc *app_search.Document) []string {
+	return slices.DeleteFunc(strings.Fields(doc.Terms), func(w string) bool { return w == "|" })
+}
+
+// PutDocument adds a document or replaces the one of the same note.
+func (x *memoryIndex) PutDocument(ctx context.Context, doc *app_search.Document) error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	x.remove(doc.NoteID)
+	x.docs[doc.NoteID] = doc
+	for _, w := range words(doc) {
+		if x.postings[w] == nil {
+			x.postings[w] = make(map[string]int)
+		}
+		x.postings[w][doc.NoteID]++
+	}
+	return nil
+}
+
+// remove drops a document and its postings. The caller must hold the lock.
+func (x *memoryIndex) remove(noteID string) {
+	doc, ok := x.docs[noteID]
+	if !ok {
+		return
+	}
+	for _, w := range words(doc) {
+		delete(x.postings[w], noteID)
+		if len(x.postings[w]) == 0 {
+			delete(x.postings, w)
+		}
+	}
+	delete(x.docs, noteID)
+}
+
+// removeWhere drops the documents for which match returns true.
+func (x *memoryIndex) removeWhere(match func(doc *app_search.Document) bool) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	for id, doc := range x.docs {
+		if match(doc) {
+			x.remove(id)
+		}
+	}
+}
+
+// DeleteDocument removes the document of a note of an owner.
+func (x *memoryIndex) DeleteDocument(ctx context.Context, ownerID, noteID string) error {
+	x.removeWhere(func(doc *app_search.Document) bool {
+		return doc.NoteID == noteID && doc.OwnerID == ownerID
+	})
+	return nil
+}
+
+// DeleteDocumentsByProject removes the documents of every note of a project.
+func (x *memoryIndex) DeleteDocumentsByProject(ctx context.Context, projectID string) error {
+	x.removeWhere(func(doc *app_search.Document) bool { return doc.ProjectID == projectID })
+	return nil
+}
+
+// DeleteDocumentsByOwner removes the documents of every note of an owner.
+func (x *memoryIndex) DeleteDocumentsByOwner(ctx context.Context, ownerID string) error {
+	x.removeWhere(func(doc *app_search.Document) bool { return doc.OwnerID == ownerID })
+	return nil
+}
+
+// Search returns one page of the documents matching query. Documents are
+// scored by TF-IDF over the words of the query, words of the title counting
+// titleWeight times.
+func (x *memoryIndex) Search(ctx context.Context, query app_search.Query) (*app_search.Matches, error) {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	queryWords := query.Words()
+	if len(queryWords) == 0 {
+		return &app_search.Matches{Matches: []app_search.Match{}}, nil
+	}
+	// Every word must occur, so the rarest one bounds the candidates.
+	rarest := slices.MinFunc(queryWords, func(a, b string) int {
+		return cmp.Compare(len(x.postings[a]), len(x.postings[b]))
+	})
+
+	var matches []app_search.Match
+	for id := range x.postings[rarest] {
+		doc := x.docs[id]
+		if doc.OwnerID != query.OwnerID ||
+			query.ProjectID != "" && doc.ProjectID != query.ProjectID ||
+			query.TemplateID != "" && doc.TemplateID != query.TemplateID ||
+			!query.Matches(doc.Terms) {
+			continue
+		}
+
+		titleWords := app_search.Tokenize(doc.Title)
+		var score float64
+		for _, w := range queryWords {
+			idf := math.Log(1 + float64(len(x.docs))/float64(len(x.postings[w])))
+			occurrences := x.postings[w][id] + (titleWeight-1)*countOf(titleWords, w)
+			score += float64(occurrences) * idf
+		}
+		matches = append(matches, app_search.Match{Document: doc, Score: score})
+	}
+
+	slices.SortFunc(matches, func(a, b app_search.Match) int {
+		return cmp.Or(
+			cmp.Compare(b.Score, a.Score),
+			cmp.Compare(a.Document.Title, b.Document.Title),
+			cmp.Compare(a.Document.NoteID, b.Document.NoteID),
+		)
+	})
+	result := &app_search.Matches{Matches: []app_search.Match{}, Total: len(matches)}
+	if query.Offset < len(matches) {
+		result.Matches = matches[query.Offset:min(query.Offset+query.Limit, len(matches))]
+	}
+	return result, nil
+}
+
+// countOf returns how many times w occurs in words.
+func countOf(words []string, w string) int {
+	n := 0
+	for _, word := range words {
+		if word == w {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,241 @@
+package search
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	app_search "github.com/AldiandyaIrsyad/author-notes/internal/search"
+)
+
+// newDocument returns the document of a note of project "novel" with the
+// template "character", as the search service builds it.
+func newDocument(noteID, ownerID, title, text string) *app_search.Document {
+	return &app_search.Document{
+		NoteID:     noteID,
+		OwnerID:    ownerID,
+		ProjectID:  "novel",
+		TemplateID: "character",
+		Title:      title,
+		Text:       text,
+		Terms:      app_search.NewTerms(title, text),
+	}
+}
+
+// withScope returns doc moved to another project and template.
+func withScope(doc *app_search.Document, projectID, templateID string) *app_search.Document {
+	doc.ProjectID = projectID
+	doc.TemplateID = templateID
+	return doc
+}
+
+func TestMemoryIndex_Search(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		docs      []*app_search.Document
+		update    func(index app_search.Index)
+		query     app_search.Query
+		want      []string
+		wantTotal int
+	}{
+		{
+			name:      "Replaced Document Matches Its New Words Only",
+			docs:      []*app_search.Document{newDocument("n1", "user123", "Aria", "carries an ancient sword"), newDocument("n1", "user123", "Aria", "carries a rusty shield")},
+			query:     app_search.Query{OwnerID: "user123", Phrases: []string{"sword"}, Limit: 10},
+			want:      []string{},
+			wantTotal: 0,
+		},
+		{
+			name:      "Replaced Document Matches Its New Words",
+			docs:      []*app_search.Document{newDocument("n1", "user123", "Aria", "carries an ancient sword"), newDocument("n1", "user123", "Aria", "carries a rusty shield")},
+			query:     app_search.Query{OwnerID: "user123", Phrases: []string{"shield"}, Limit: 10},
+			want:      []string{"n1"},
+			wantTotal: 1,
+		},
+		{
+			name: "Deleted Document Does Not Match",
+			docs: []*app_search.Document{newDocument("n1", "user123", "Aria", "a sword"), newDocument("n2", "user123", "Bram", "a sword")},
+			update: func(index app_search.Index) {
+				require.NoError(t, index.DeleteDocument(ctx, "user123", "n1"))
+			},
+			query:     app_search.Query{OwnerID: "user123", Phrases: []string{"sword"}, Limit: 10},
+			want:      []string{"n2"},
+			wantTotal: 1,
+		},
+		{
+			name: "Document Of Another Owner Is Not Deleted",
+			docs: []*app_search.Document{newDocument("n1", "user123", "Aria", "a sword")},
+			update: func(index app_search.Index) {
+				require.NoError(t, index.DeleteDocument(ctx, "someone", "n1"))
+			},
+			query:     app_search.Query{OwnerID: "user123", Phrases: []string{"sword"}, Limit: 10},
+			want:      []string{"n1"},
+			wantTotal: 1,
+		},
+		{
+			name: "Documents Of A Deleted Project Do Not Match",
+			docs: []*app_search.Document{newDocument("n1", "user123", "Aria", "a sword"), withScope(newDocument("n2", "user123", "Bram", "a sword"), "sequel", "character")},
+			update: func(index app_search.Index) {
+				require.NoError(t, index.DeleteDocumentsByProject(ctx, "novel"))
+			},
+			query:     app_search.Query{OwnerID: "user123", Phrases: []string{"sword"}, Limit: 10},
+			want:      []string{"n2"},
+			wantTotal: 1,
+		},
+		{
+			name: "Documents Of A Deleted Owner Do Not Match",
+			docs: []*app_search.Document{newDocument("n1", "user123", "Aria", "a sword"), newDocument("n2", "someone", "Bram", "a sword")},
+			update: func(index app_search.Index) {
+				require.NoError(t, index.DeleteDocumentsByOwner(ctx, "someone"))
+			},
+			query:     app_search.Query{OwnerID: "someone", Phrases: []string{"sword"}, Limit: 10},
+			want:      []string{},
+			wantTotal: 0,
+		},
+		{
+			name:      "Phrase Matches Consecutive Words",
+			docs:      []*app_search.Document{newDocument("n1", "user123", "Aria", "the red dragon sleeps"), newDocument("n2", "user123", "Bram", "red wine for a dragon")},
+			query:     app_search.Query{OwnerID: "user123", Phrases: []string{"red dragon"}, Limit: 10},
+			want:      []string{"n1"},
+			wantTotal: 1,
+		},
+		{
+			name:      "Phrase Does Not Match Across Title And Text",
+			docs:      []*app_search.Document{newDocument("n1", "user123", "Red", "dragon of the north")},
+			query:     app_search.Query{OwnerID: "user123", Phrases: []string{"red dragon"}, Limit: 10},
+			want:      []string{},
+			wantTotal: 0,
+		},
+		{
+			name:      "Every Phrase Must Match",
+			docs:      []*app_search.Document{newDocument("n1", "user123", "Aria", "a sword and a shield"), newDocument("n2", "user123", "Bram", "a sword")},
+			query:     app_search.Query{OwnerID: "user123", Phrases: []string{"sword", "shield"}, Limit: 10},
+			want:      []string{"n1"},
+			wantTotal: 1,
+		},
+		{
+			name:      "Title Words Outweigh Text Words",
+			docs:      []*app_search.Document{newDocument("n1", "user123", "Aria", "fears the dragon, hunts the dragon"), newDocument("n2", "user123", "Dragon", "a beast"), newDocument("n3", "user123", "Bram", "met a dragon once")},
+			query:     app_search.Query{OwnerID: "user123", Phrases: []string{"dragon"}, Limit: 10},
+			want:      []string{"n2", "n1", "n3"},
+			wantTotal: 3,
+		},
+		{
+			name:      "Other Owners Are Filtered Out",
+			docs:      []*app_search.Document{newDocument("n1", "user123", "Aria", "a sword"), newDocument("n2", "someone", "Aria", "a sword")},
+			query:     app_search.Query{OwnerID: "user123", Phrases: []string{"sword"}, Limit: 10},
+			want:      []string{"n1"},
+			wantTotal: 1,
+		},
+		{
+			name:      "Filtered By Project",
+			docs:      []*app_search.Document{newDocument("n1", "user123", "Aria", "a sword"), withScope(newDocument("n2", "user123", "Aria", "a sword"), "sequel", "character")},
+			query:     app_search.Query{OwnerID: "user123", ProjectID: "sequel", Phrases: []string{"sword"}, Limit: 10},
+			want:      []string{"n2"},
+			wantTotal: 1,
+		},
+		{
+			name:      "Filtered By Template",
+			docs:      []*app_search.Document{newDocument("n1", "user123", "Aria", "a sword"), withScope(newDocument("n2", "user123", "Armory", "a sword"), "novel", "location")},
+			query:     app_search.Query{OwnerID: "user123", TemplateID: "location", Phrases: []string{"sword"}, Limit: 10},
+			want:      []string{"n2"},
+			wantTotal: 1,
+		},
+		{
+			name:      "Page Within Matches",
+			docs:      []*app_search.Document{newDocument("n1", "user123", "Aria", "a sword"), newDocument("n2", "user123", "Bram", "a sword"), newDocument("n3", "user123", "Cole", "a sword")},
+			query:     app_search.Query{OwnerID: "user123", Phrases: []string{"sword"}, Offset: 1, Limit: 1},
+			want:      []string{"n2"},
+			wantTotal: 3,
+		},
+		{
+			name:      "Last Page Is Cut Short",
+			docs:      []*app_search.Document{newDocument("n1", "user123", "Aria", "a sword"), newDocument("n2", "user123", "Bram", "a sword"), newDocument("n3", "user123", "Cole", "a sword")},
+			query:     app_search.Query{OwnerID: "user123", Phrases: []string{"sword"}, Offset: 2, Limit: 2},
+			want:      []string{"n3"},
+			wantTotal: 3,
+		},
+		{
+			name:      "Page Past Matches Is Empty",
+			docs:      []*app_search.Document{newDocument("n1", "user123", "Aria", "a sword"), newDocument("n2", "user123", "Bram", "a sword")},
+			query:     app_search.Query{OwnerID: "user123", Phrases: []string{"sword"}, Offset: 5, Limit: 10},
+			want:      []string{},
+			wantTotal: 2,
+		},
+		{
+			name:      "Query Without Words Matches Nothing",
+			docs:      []*app_search.Document{newDocument("n1", "user123", "Aria", "a sword")},
+			query:     app_search.Query{OwnerID: "user123", Limit: 10},
+			want:      []string{},
+			wantTotal: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			index := NewMemoryIndex()
+			for _, doc := range tt.docs {
+				require.NoError(t, index.PutDocument(ctx, doc))
+			}
+			if tt.update != nil {
+				tt.update(index)
+			}
+
+			matches, err := index.Search(ctx, tt.query)
+
+			require.NoError(t, err)
+			got := []string{}
+			for _, match := range matches.Matches {
+				got = append(got, match.Document.NoteID)
+			}
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantTotal, matches.Total)
+		})
+	}
+}
+
+func TestMemoryIndex_Score(t *testing.T) {
+	ctx := context.Background()
+	index := NewMemoryIndex()
+	require.NoError(t, index.PutDocument(ctx, newDocument("n1", "user123", "Dragon", "the dragon sleeps")))
+	require.NoError(t, index.PutDocument(ctx, newDocument("n2", "user123", "Aria", "a sword")))
+
+	matches, err := index.Search(ctx, app_search.Query{OwnerID: "user123", Phrases: []string{"dragon"}, Limit: 10})
+
+	require.NoError(t, err)
+	require.Len(t, matches.Matches, 1)
+	// Two documents, one with the word: twice in the terms, once of them in
+	// the title, which counts titleWeight times.
+	idf := math.Log(1 + 2.0/1.0)
+	assert.InDelta(t, float64(1+titleWeight)*idf, matches.Matches[0].Score, 1e-9)
+}
+
+func TestMemoryIndex_Postings(t *testing.T) {
+	ctx := context.Background()
+	index := NewMemoryIndex().(*memoryIndex)
+
+	require.NoError(t, index.PutDocument(ctx, newDocument("n1", "user123", "Aria", "an ancient sword")))
+	require.NoError(t, index.PutDocument(ctx, newDocument("n2", "user123", "Bram", "an ancient shield")))
+	assert.Equal(t, map[string]int{"n1": 1, "n2": 1}, index.postings["ancient"])
+
+	t.Run("Replacing Drops The Old Words", func(t *testing.T) {
+		require.NoError(t, index.PutDocument(ctx, newDocument("n1", "user123", "Aria", "a rusty shield")))
+
+		assert.NotContains(t, index.postings, "sword")
+		assert.Equal(t, map[string]int{"n2": 1}, index.postings["ancient"])
+		assert.Equal(t, map[string]int{"n1": 1, "n2": 1}, index.postings["shield"])
+	})
+
+	t.Run("Deleting Drops Every Word", func(t *testing.T) {
+		require.NoError(t, index.DeleteDocument(ctx, "user123", "n1"))
+		require.NoError(t, index.DeleteDocumentsByOwner(ctx, "user123"))
+
+		assert.Empty(t, index.docs)
+		assert.Empty(t, index.postings)
+	})
+}
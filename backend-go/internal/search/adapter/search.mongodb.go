@@ -0,0 +1,123 @@
+package search
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	app_search "github.com/AldiandyaIrsyad/author-notes/internal/search"
+)
+
+// mongoIndex implements the Index interface using a MongoDB text index.
+// The text index finds and scores candidates; the stored terms of each
+// document then make sure every word and phrase of the query occurs as is.
+type mongoIndex struct {
+	collection *mongo.Collection
+}
+
+// scoredDocument is a Document returned by a text search, with its score.
+type scoredDocument struct {
+	app_search.Document `bson:",inline"`
+	Score               float64 `bson:"score"`
+}
+
+// NewMongoIndex creates a new instance of mongoIndex.
+func NewMongoIndex(ctx context.Context, db *mongo.Database) (app_search.Index, error) {
+	collection := db.Collection("search_documents")
+	_, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "title", Value: "text"}, {Key: "text", Value: "text"}},
+			// Words are matched as written, so the index neither stems them
+			// nor drops stop words.
+			Options: options.Index().
+				SetWeights(bson.D{{Key: "title", Value: titleWeight}, {Key: "text", Value: 1}}).
+				SetDefaultLanguage("none"),
+		},
+		{Keys: bson.D{{Key: "owner_id", Value: 1}, {Key: "project_id", Value: 1}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &mongoIndex{collection: collection}, nil
+}
+
+// PutDocument adds a document or replaces the one of the same note.
+func (x *mongoIndex) PutDocument(ctx context.Context, doc *app_search.Document) error {
+	_, err := x.collection.ReplaceOne(ctx, bson.M{"_id": doc.NoteID}, doc, options.Replace().SetUpsert(true))
+	return err
+}
+
+// DeleteDocument removes the document of a note of an owner.
+func (x *mongoIndex) DeleteDocument(ctx context.Context, ownerID, noteID string) error {
+	_, err := x.collection.DeleteOne(ctx, bson.M{"_id": noteID, "owner_id": ownerID})
+	return err
+}
+
+// DeleteDocumentsByProject removes the documents of every note of a project.
+func (x *mongoIndex) DeleteDocumentsByProject(ctx context.Context, projectID string) error {
+	_, err := x.collection.DeleteMany(ctx, bson.M{"project_id": projectID})
+	return err
+}
+
+// DeleteDocumentsByOwner removes the documents of every note of an owner.
+func (x *mongoIndex) DeleteDocumentsByOwner(ctx context.Context, ownerID string) error {
+	_, err := x.collection.DeleteMany(ctx, bson.M{"owner_id": ownerID})
+	return err
+}
+
+// Search returns one page of the documents matching query, by text score.
+func (x *mongoIndex) Search(ctx context.Context, query app_search.Query) (*app_search.Matches, error) {
+	result := &app_search.Matches{Matches: []app_search.Match{}}
+	queryWords := query.Words()
+	if len(queryWords) == 0 {
+		return result, nil
+	}
+
+	filter := bson.M{
+		"$text":    bson.M{"$search": strings.Join(queryWords, " ")},
+		"owner_id": query.OwnerID,
+	}
+	if query.ProjectID != "" {
+		filter["project_id"] = query.ProjectID
+	}
+	if query.TemplateID != "" {
+		filter["template_id"] = query.TemplateID
+	}
+	phrases := make([]bson.M, 0, len(query.Phrases))
+	for _, phrase := range query.Phrases {
+		phrases = append(phrases, bson.M{"terms": primitive.Regex{Pattern: regexp.QuoteMeta(" " + phrase + " ")}})
+	}
+	filter["$and"] = phrases
+
+	total, err := x.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	result.Total = int(total)
+
+	score := bson.M{"$meta": "textScore"}
+	opts := options.Find().
+		SetProjection(bson.M{"score": score}).
+		SetSort(bson.D{{Key: "score", Value: score}, {Key: "_id", Value: 1}}).
+		SetSkip(int64(query.Offset)).
+		SetLimit(int64(query.Limit))
+	cursor, err := x.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	docs := []scoredDocument{}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	for _, doc := range docs {
+		result.Matches = append(result.Matches, app_search.Match{Document: &doc.Document, Score: doc.Score})
+	}
+	return result, nil
+}
@@ -0,0 +1,8 @@
+package search
+
+import "errors"
+
+var (
+	ErrValidationFailed = errors.New("input validation failed")
+	ErrEmptyQuery       = errors.New("query has no words to search for")
+)
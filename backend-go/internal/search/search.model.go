@@ -0,0 +1,99 @@
+package search
+
+import "strings"
+
+// Document is the searchable state of a note: its title and the text of its
+// values, by field ID.
+type Document struct {
+	NoteID     string            `bson:"_id"`
+	OwnerID    string            `bson:"owner_id"`
+	ProjectID  string            `bson:"project_id"`
+	TemplateID string            `bson:"template_id"`
+	Title      string            `bson:"title"`
+	Fields     map[string]string `bson:"fields,omitempty"`
+	Text       string            `bson:"text"`  // the values of Fields, one per line
+	Terms      string            `bson:"terms"` // see NewTerms
+	UpdatedAt  int64             `bson:"updated_at"`
+}
+
+// Query selects the documents of an owner containing every one of Phrases,
+// optionally of a project and a template. Phrases are words as returned by
+// Tokenize, separated by single spaces; a phrase may be a single word.
+type Query struct {
+	OwnerID    string
+	ProjectID  string
+	TemplateID string
+	Phrases    []string
+	Offset     int
+	Limit      int
+}
+
+// Words returns the distinct words of the phrases of the query.
+func (q Query) Words() []string {
+	var words []string
+	seen := make(map[string]bool)
+	for _, phrase := range q.Phrases {
+		for _, word := range strings.Split(phrase, " ") {
+			if !seen[word] {
+				seen[word] = true
+				words = append(words, word)
+			}
+		}
+	}
+	return words
+}
+
+// Matches reports whether terms, as returned by NewTerms, contain every
+// phrase of the query.
+func (q Query) Matches(terms string) bool {
+	for _, phrase := range q.Phrases {
+		if !strings.Contains(terms, " "+phrase+" ") {
+			return false
+		}
+	}
+	return true
+}
+
+// Match is a document found by an Index, with its relevance to the query.
+type Match struct {
+	Document *Document
+	Score    float64
+}
+
+// Matches is one page of the documents matching a query, best first, along
+// with how many match in total.
+type Matches struct {
+	Matches []Match
+	Total   int
+}
+
+// Result is one page of search results. NextOffset is zero when there are
+// no more results.
+type Result struct {
+	Hits       []Hit `json:"hits"`
+	Total      int   `json:"total"`
+	NextOffset int   `json:"next_offset,omitempty"`
+}
+
+// Hit is a note found by a search. Highlights hold the title and the values
+// of the note that match, HTML-escaped, with the matches wrapped in <mark>.
+type Hit struct {
+	NoteID     string      `json:"note_id"`
+	ProjectID  string      `json:"project_id"`
+	TemplateID string      `json:"template_id"`
+	Title      string      `json:"title"`
+	Score      float64     `json:"score"`
+	Highlights []Highlight `json:"highlights"`
+}
+
+// Highlight is an excerpt of the title or of the value of a field of a note
+// around the matches of a search. FieldID is empty for the title.
+type Highlight struct {
+	FieldID string `json:"field_id,omitempty"`
+	Snippet string `json:"snippet"`
+}
+
+// ReindexReport is the outcome of rebuilding the search index of an owner.
+type ReindexReport struct {
+	Indexed int `json:"indexed"`
+}
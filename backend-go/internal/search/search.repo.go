@@ -0,0 +1,35 @@
+package search
+
+import (
+	"context"
+
+	"github.com/AldiandyaIrsyad/author-notes/internal/field"
+	"github.com/AldiandyaIrsyad/author-notes/internal/note"
+)
+
+// Index defines the interface for storing and searching documents.
+type Index interface {
+	// PutDocument adds a document or replaces the one of the same note.
+	PutDocument(ctx context.Context, doc *Document) error
+	// DeleteDocument removes the document of a note of an owner. Missing
+	// documents are ignored.
+	DeleteDocument(ctx context.Context, ownerID, noteID string) error
+	// DeleteDocumentsByProject removes the documents of every note of a project.
+	DeleteDocumentsByProject(ctx context.Context, projectID string) error
+	// DeleteDocumentsByOwner removes the documents of every note of an owner.
+	DeleteDocumentsByOwner(ctx context.Context, ownerID string) error
+	// Search returns one page of the documents matching query, best first.
+	Search(ctx context.Context, query Query) (*Matches, error)
+}
+
+// NoteStore is the subset of note.NoteRepository used to rebuild the index.
+type NoteStore interface {
+	ListOwnerIDs(ctx context.Context) ([]string, error)
+	ListNotes(ctx context.Context, filter note.NoteFilter) (*note.NotePage, error)
+}
+
+// FieldStore is the subset of field.FieldRepository used to find the values
+// of notes that hold text.
+type FieldStore interface {
+	FindFieldByID(ctx context.Context, id string) (*field.Field, error)
+}
@@ -0,0 +1,248 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+
+	v1 "github.com/AldiandyaIrsyad/author-notes/api/v1/search"
+	"github.com/AldiandyaIrsyad/author-notes/internal/field"
+	"github.com/AldiandyaIrsyad/author-notes/internal/note"
+)
+
+// SearchService defines the interface for searching notes. Searches are
+// scoped to the owner.
+type SearchService interface {
+	// Search returns one page of the notes of the owner matching a query.
+	Search(ctx context.Context, ownerID string, req v1.SearchRequest) (*Result, error)
+	// Reindex rebuilds the index of the notes of the owner from scratch.
+	Reindex(ctx context.Context, ownerID string) (*ReindexReport, error)
+	// ReindexAll rebuilds the index of the notes of every user, such as when
+	// an index kept in memory starts empty.
+	ReindexAll(ctx context.Context) (*ReindexReport, error)
+
+	// The index follows the changes to the notes.
+	note.Indexer
+}
+
+type searchService struct {
+	index     Index
+	notes     NoteStore
+	fields    FieldStore
+	validator *validator.Validate
+}
+
+// NewSearchService creates a new instance of SearchService.
+func NewSearchService(index Index, notes NoteStore, fields FieldStore) SearchService {
+	return &searchService{
+		index:     index,
+		notes:     notes,
+		fields:    fields,
+		validator: validator.New(),
+	}
+}
+
+// defaultSearchPageSize is used by Search when the request does not specify a limit.
+const defaultSearchPageSize = 20
+
+// Search returns the notes of the owner containing every word and quoted
+// phrase of the query, best first, with their matches highlighted.
+func (s *searchService) Search(ctx context.Context, ownerID string, req v1.SearchRequest) (*Result, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, ErrValidationFailed
+	}
+	phrases := parseQuery(req.Query)
+	if len(phrases) == 0 {
+		return nil, ErrEmptyQuery
+	}
+
+	query := Query{
+		OwnerID:    ownerID,
+		ProjectID:  req.ProjectID,
+		TemplateID: req.TemplateID,
+		Phrases:    phrases,
+		Offset:     req.Offset,
+		Limit:      req.Limit,
+	}
+	if query.Limit == 0 {
+		query.Limit = defaultSearchPageSize
+	}
+	matches, err := s.index.Search(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{Hits: make([]Hit, 0, len(matches.Matches)), Total: matches.Total}
+	for _, m := range matches.Matches {
+		result.Hits = append(result.Hits, newHit(m, phrases))
+	}
+	if next := query.Offset + len(matches.Matches); len(matches.Matches) > 0 && next < matches.Total {
+		result.NextOffset = next
+	}
+	return result, nil
+}
+
+// quotedPhrase matches a phrase in double quotes, or an opening quote left
+// unclosed at the end of a query.
+var quotedPhrase = regexp.MustCompile(`"([^"]*)("|$)`)
+
+// parseQuery returns the phrases of a query: the words of each quoted phrase
+// together, and every word outside of quotes on its own. Repeats are dropped.
+func parseQuery(q string) []string {
+	var phrases []string
+	add := func(words []string) {
+		if phrase := strings.Join(words, " "); phrase != "" && !slices.Contains(phrases, phrase) {
+			phrases = append(phrases, phrase)
+		}
+	}
+	for _, m := range quotedPhrase.FindAllStringSubmatch(q, -1) {
+		add(Tokenize(m[1]))
+	}
+	for _, word := range Tokenize(quotedPhrase.ReplaceAllString(q, " ")) {
+		add([]string{word})
+	}
+	return phrases
+}
+
+// newHit returns the search result for a match, highlighting the title and
+// the values of the fields that contain phrases.
+func newHit(m Match, phrases []string) Hit {
+	doc := m.Document
+	hit := Hit{
+		NoteID:     doc.NoteID,
+		ProjectID:  doc.ProjectID,
+		TemplateID: doc.TemplateID,
+		Title:      doc.Title,
+		Score:      m.Score,
+		Highlights: []Highlight{},
+	}
+	if snippet := highlight(doc.Title, phrases, false); snippet != "" {
+		hit.Highlights = append(hit.Highlights, Highlight{Snippet: snippet})
+	}
+	for _, fieldID := range slices.Sorted(maps.Keys(doc.Fields)) {
+		if snippet := highlight(doc.Fields[fieldID], phrases, true); snippet != "" {
+			hit.Highlights = append(hit.Highlights, Highlight{FieldID: fieldID, Snippet: snippet})
+		}
+	}
+	return hit
+}
+
+// Reindex replaces the documents of the owner by those of the notes the
+// owner has now.
+func (s *searchService) Reindex(ctx context.Context, ownerID string) (*ReindexReport, error) {
+	if err := s.index.DeleteDocumentsByOwner(ctx, ownerID); err != nil {
+		return nil, err
+	}
+
+	report := &ReindexReport{}
+	filter := note.NoteFilter{OwnerID: ownerID, Limit: 100}
+	for {
+		page, err := s.notes.ListNotes(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		for _, n := range page.Notes {
+			if err := s.IndexNote(ctx, n); err != nil {
+				return nil, err
+			}
+			report.Indexed++
+		}
+		if page.NextCursor == "" {
+			return report, nil
+		}
+		filter.Cursor = page.NextCursor
+	}
+}
+
+// ReindexAll reindexes the notes of every user with notes.
+func (s *searchService) ReindexAll(ctx context.Context) (*ReindexReport, error) {
+	ownerIDs, err := s.notes.ListOwnerIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	total := &ReindexReport{}
+	for _, ownerID := range ownerIDs {
+		report, err := s.Reindex(ctx, ownerID)
+		if err != nil {
+			return nil, err
+		}
+		total.Indexed += report.Indexed
+	}
+	return total, nil
+}
+
+// textKinds are the kinds of fields whose values are searched.
+var textKinds = []field.Kind{
+	field.KindShortText,
+	field.KindLongText,
+	field.KindSingleSelect,
+	field.KindMultiSelect,
+	field.KindURL,
+}
+
+// IndexNote stores the searchable state of a note: its title and its values
+// that hold text.
+func (s *searchService) IndexNote(ctx context.Context, n *note.Note) error {
+	doc := &Document{
+		NoteID:     n.ID,
+		OwnerID:    n.OwnerID,
+		ProjectID:  n.ProjectID,
+		TemplateID: n.TemplateID,
+		Title:      n.Title,
+		Fields:     make(map[string]string),
+		UpdatedAt:  n.UpdatedAt,
+	}
+	sections := []string{n.Title}
+	for _, fieldID := range slices.Sorted(maps.Keys(n.Values)) {
+		f, err := s.fields.FindFieldByID(ctx, fieldID)
+		if errors.Is(err, field.ErrFieldNotFound) || err == nil && (f.OwnerID != n.OwnerID || !slices.Contains(textKinds, f.Kind)) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if text := valueText(n.Values[fieldID]); text != "" {
+			doc.Fields[fieldID] = text
+			sections = append(sections, text)
+		}
+	}
+	doc.Text = strings.Join(sections[1:], "\n")
+	doc.Terms = NewTerms(sections...)
+	return s.index.PutDocument(ctx, doc)
+}
+
+// valueText returns the text of a value: a string, or the choices of a multi
+// select value separated by commas.
+func valueText(value any) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	items, _ := field.ListValue(value)
+	var choices []string
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			choices = append(choices, s)
+		}
+	}
+	return strings.Join(choices, ", ")
+}
+
+// RemoveNote removes the document of a note of an owner.
+func (s *searchService) RemoveNote(ctx context.Context, ownerID, id string) error {
+	return s.index.DeleteDocument(ctx, ownerID, id)
+}
+
+// RemoveProjectNotes removes the documents of the notes of a project.
+func (s *searchService) RemoveProjectNotes(ctx context.Context, projectID string) error {
+	return s.index.DeleteDocumentsByProject(ctx, projectID)
+}
+
+// RemoveOwnerNotes removes the documents of the notes of an owner.
+func (s *searchService) RemoveOwnerNotes(ctx context.Context, ownerID string) error {
+	return s.index.DeleteDocumentsByOwner(ctx, ownerID)
+}
@@ -0,0 +1,273 @@
+package search
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	v1 "github.com/AldiandyaIrsyad/author-notes/api/v1/search"
+	"github.com/AldiandyaIrsyad/author-notes/internal/field"
+	"github.com/AldiandyaIrsyad/author-notes/internal/note"
+)
+
+// MockIndex is a mock implementation of Index
+type MockIndex struct {
+	mock.Mock
+}
+
+func (m *MockIndex) PutDocument(ctx context.Context, doc *Document) error {
+	args := m.Called(ctx, doc)
+	return args.Error(0)
+}
+
+func (m *MockIndex) DeleteDocument(ctx context.Context, ownerID, noteID string) error {
+	args := m.Called(ctx, ownerID, noteID)
+	return args.Error(0)
+}
+
+func (m *MockIndex) DeleteDocumentsByProject(ctx context.Context, projectID string) error {
+	args := m.Called(ctx, projectID)
+	return args.Error(0)
+}
+
+func (m *MockIndex) DeleteDocumentsByOwner(ctx context.Context, ownerID string) error {
+	args := m.Called(ctx, ownerID)
+	return args.Error(0)
+}
+
+func (m *MockIndex) Search(ctx context.Context, query Query) (*Matches, error) {
+	args := m.Called(ctx, query)
+	if matches := args.Get(0); matches != nil {
+		return matches.(*Matches), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// stubNoteStore serves fixed pages of notes, by cursor, keeping the notes of
+// the owner of the filter.
+type stubNoteStore map[string]*note.NotePage
+
+func (s stubNoteStore) ListOwnerIDs(_ context.Context) ([]string, error) {
+	ownerIDs := []string{}
+	for _, page := range s {
+		for _, n := range page.Notes {
+			if !slices.Contains(ownerIDs, n.OwnerID) {
+				ownerIDs = append(ownerIDs, n.OwnerID)
+			}
+		}
+	}
+	return ownerIDs, nil
+}
+
+func (s stubNoteStore) ListNotes(_ context.Context, filter note.NoteFilter) (*note.NotePage, error) {
+	page := &note.NotePage{NextCursor: s[filter.Cursor].NextCursor}
+	for _, n := range s[filter.Cursor].Notes {
+		if n.OwnerID == filter.OwnerID {
+			page.Notes = append(page.Notes, n)
+		}
+	}
+	return page, nil
+}
+
+// stubFieldStore serves a fixed set of fields.
+type stubFieldStore map[string]*field.Field
+
+func (s stubFieldStore) FindFieldByID(_ context.Context, id string) (*field.Field, error) {
+	if f, ok := s[id]; ok {
+		return f, nil
+	}
+	return nil, field.ErrFieldNotFound
+}
+
+var testFields = stubFieldStore{
+	"bio":    {ID: "bio", OwnerID: "user123", Kind: field.KindLongText},
+	"tags":   {ID: "tags", OwnerID: "user123", Kind: field.KindMultiSelect},
+	"age":    {ID: "age", OwnerID: "user123", Kind: field.KindNumber},
+	"friend": {ID: "friend", OwnerID: "user123", Kind: field.KindReference},
+	"theirs": {ID: "theirs", OwnerID: "someone", Kind: field.KindShortText},
+}
+
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"Alice", []string{"alice"}},
+		{`red  Dragon red`, []string{"red", "dragon"}},
+		{`"the Red-Dragon" inn`, []string{"the red dragon", "inn"}},
+		{`sword "of fire`, []string{"of fire", "sword"}},
+		{`"" !?`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			assert.Equal(t, tt.want, parseQuery(tt.query))
+		})
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	t.Run("Marks Words And Phrases", func(t *testing.T) {
+		got := highlight("The <Red> Dragon's red dragon", []string{"red dragon"}, false)
+
+		assert.Equal(t, "The &lt;<mark>Red&gt; Dragon</mark>&#39;s <mark>red dragon</mark>", got)
+	})
+
+	t.Run("Cuts Long Text Around First Match", func(t *testing.T) {
+		text := strings.Repeat("lorem ipsum ", 30) + "the dragon sleeps " + strings.Repeat("dolor sit ", 30)
+
+		got := highlight(text, []string{"dragon"}, true)
+
+		assert.True(t, strings.HasPrefix(got, "…ipsum"))
+		assert.True(t, strings.HasSuffix(got, "…"))
+		assert.Contains(t, got, "the <mark>dragon</mark> sleeps")
+		assert.LessOrEqual(t, len(got), snippetLength+len("……<mark></mark>"))
+	})
+
+	t.Run("No Match", func(t *testing.T) {
+		assert.Empty(t, highlight("The red dragon", []string{"red wyrm"}, true))
+	})
+}
+
+func TestSearchService_Search(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		mockIndex := new(MockIndex)
+		service := NewSearchService(mockIndex, stubNoteStore{}, testFields)
+		doc := &Document{
+			NoteID: "note1", ProjectID: "novel", TemplateID: "character", Title: "Red Dragon",
+			Fields: map[string]string{"bio": "Sleeps under the inn.", "tags": "villain"},
+		}
+		expected := Query{OwnerID: "user123", ProjectID: "novel", Phrases: []string{"under the inn", "dragon"}, Offset: 1, Limit: 1}
+		mockIndex.On("Search", ctx, expected).Return(&Matches{Matches: []Match{{Document: doc, Score: 2.5}}, Total: 3}, nil).Once()
+
+		result, err := service.Search(ctx, "user123", v1.SearchRequest{Query: `dragon "under the inn"`, ProjectID: "novel", Offset: 1, Limit: 1})
+
+		require.NoError(t, err)
+		assert.Equal(t, 3, result.Total)
+		assert.Equal(t, 2, result.NextOffset)
+		require.Len(t, result.Hits, 1)
+		assert.Equal(t, Hit{
+			NoteID: "note1", ProjectID: "novel", TemplateID: "character", Title: "Red Dragon", Score: 2.5,
+			Highlights: []Highlight{
+				{Snippet: "Red <mark>Dragon</mark>"},
+				{FieldID: "bio", Snippet: "Sleeps <mark>under the inn</mark>."},
+			},
+		}, result.Hits[0])
+		mockIndex.AssertExpectations(t)
+	})
+
+	t.Run("Last Page", func(t *testing.T) {
+		mockIndex := new(MockIndex)
+		service := NewSearchService(mockIndex, stubNoteStore{}, testFields)
+		mockIndex.On("Search", ctx, Query{OwnerID: "user123", Phrases: []string{"dragon"}, Limit: 20}).
+			Return(&Matches{Matches: []Match{{Document: &Document{NoteID: "note1", Title: "Dragon"}}}, Total: 1}, nil).Once()
+
+		result, err := service.Search(ctx, "user123", v1.SearchRequest{Query: "dragon"})
+
+		require.NoError(t, err)
+		assert.Zero(t, result.NextOffset)
+	})
+
+	t.Run("Empty Query", func(t *testing.T) {
+		mockIndex := new(MockIndex)
+		service := NewSearchService(mockIndex, stubNoteStore{}, testFields)
+
+		_, err := service.Search(ctx, "user123", v1.SearchRequest{Query: `"" -`})
+
+		assert.ErrorIs(t, err, ErrEmptyQuery)
+		mockIndex.AssertNotCalled(t, "Search", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Invalid Request", func(t *testing.T) {
+		service := NewSearchService(new(MockIndex), stubNoteStore{}, testFields)
+
+		_, err := service.Search(ctx, "user123", v1.SearchRequest{Query: "dragon", Limit: 500})
+
+		assert.ErrorIs(t, err, ErrValidationFailed)
+	})
+}
+
+func TestSearchService_IndexNote(t *testing.T) {
+	ctx := context.Background()
+	mockIndex := new(MockIndex)
+	service := NewSearchService(mockIndex, stubNoteStore{}, testFields)
+	n := &note.Note{
+		ID: "note1", OwnerID: "user123", ProjectID: "novel", TemplateID: "character", Title: "Red Dragon", UpdatedAt: 42,
+		Values: map[string]any{
+			"bio":    "Sleeps *under* the inn.",
+			"tags":   primitive.A{"villain", "winged"},
+			"age":    300.0,
+			"friend": "note2",
+			"theirs": "hidden",
+			"gone":   "deleted field",
+		},
+	}
+	mockIndex.On("PutDocument", ctx, &Document{
+		NoteID: "note1", OwnerID: "user123", ProjectID: "novel", TemplateID: "character", Title: "Red Dragon",
+		Fields:    map[string]string{"bio": "Sleeps *under* the inn.", "tags": "villain, winged"},
+		Text:      "Sleeps *under* the inn.\nvillain, winged",
+		Terms:     " red dragon | sleeps under the inn | villain winged ",
+		UpdatedAt: 42,
+	}).Return(nil).Once()
+
+	require.NoError(t, service.IndexNote(ctx, n))
+	mockIndex.AssertExpectations(t)
+}
+
+func TestSearchService_Reindex(t *testing.T) {
+	ctx := context.Background()
+	mockIndex := new(MockIndex)
+	notes := stubNoteStore{
+		"":     {Notes: []*note.Note{{ID: "note1", OwnerID: "user123", Title: "Alice"}}, NextCursor: "next"},
+		"next": {Notes: []*note.Note{{ID: "note2", OwnerID: "user123", Title: "Bob"}}},
+	}
+	service := NewSearchService(mockIndex, notes, testFields)
+	mockIndex.On("DeleteDocumentsByOwner", ctx, "user123").Return(nil).Once()
+	mockIndex.On("PutDocument", ctx, mock.AnythingOfType("*search.Document")).Return(nil).Twice()
+
+	report, err := service.Reindex(ctx, "user123")
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.Indexed)
+	mockIndex.AssertExpectations(t)
+}
+
+func TestSearchService_ReindexAll(t *testing.T) {
+	ctx := context.Background()
+	mockIndex := new(MockIndex)
+	notes := stubNoteStore{
+		"":     {Notes: []*note.Note{{ID: "note1", OwnerID: "user123", Title: "Alice"}, {ID: "note2", OwnerID: "someone", Title: "Eve"}}, NextCursor: "next"},
+		"next": {Notes: []*note.Note{{ID: "note3", OwnerID: "user123", Title: "Bob"}}},
+	}
+	service := NewSearchService(mockIndex, notes, testFields)
+	mockIndex.On("DeleteDocumentsByOwner", ctx, "user123").Return(nil).Once()
+	mockIndex.On("DeleteDocumentsByOwner", ctx, "someone").Return(nil).Once()
+	mockIndex.On("PutDocument", ctx, mock.AnythingOfType("*search.Document")).Return(nil).Times(3)
+
+	report, err := service.ReindexAll(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, report.Indexed)
+	mockIndex.AssertExpectations(t)
+}
+
+func TestSearchService_RemoveNotes(t *testing.T) {
+	ctx := context.Background()
+	mockIndex := new(MockIndex)
+	service := NewSearchService(mockIndex, stubNoteStore{}, testFields)
+	mockIndex.On("DeleteDocument", ctx, "user123", "note1").Return(nil).Once()
+	mockIndex.On("DeleteDocumentsByProject", ctx, "novel").Return(nil).Once()
+	mockIndex.On("DeleteDocumentsByOwner", ctx, "user123").Return(nil).Once()
+
+	require.NoError(t, service.RemoveNote(ctx, "user123", "note1"))
+	require.NoError(t, service.RemoveProjectNotes(ctx, "novel"))
+	require.NoError(t, service.RemoveOwnerNotes(ctx, "user123"))
+	mockIndex.AssertExpectations(t)
+}
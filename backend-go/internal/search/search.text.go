@@ -0,0 +1,140 @@
+package search
+
+import (
+	"html"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+const (
+	// snippetLength is about the number of bytes of text a highlight shows,
+	// starting snippetContext bytes before the first match.
+	snippetLength  = 200
+	snippetContext = 60
+)
+
+// span is the byte range of a word in a text.
+type span struct {
+	start, end int
+}
+
+// wordSpans returns the ranges of the words of text: runs of letters, digits
+// and combining marks.
+func wordSpans(text string) []span {
+	var spans []span
+	start := -1
+	for i, r := range text {
+		word := unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r)
+		switch {
+		case word && start < 0:
+			start = i
+		case !word && start >= 0:
+			spans = append(spans, span{start, i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		spans = append(spans, span{start, len(text)})
+	}
+	return spans
+}
+
+// Tokenize returns the words of text in lower case. Documents and queries are
+// compared word by word as returned by Tokenize.
+func Tokenize(text string) []string {
+	spans := wordSpans(text)
+	words := make([]string, len(spans))
+	for i, s := range spans {
+		words[i] = strings.ToLower(text[s.start:s.end])
+	}
+	return words
+}
+
+// NewTerms returns the words of sections as stored in Document.Terms: each
+// word surrounded by spaces, and sections kept apart so that phrases do not
+// match across them.
+func NewTerms(sections ...string) string {
+	var b strings.Builder
+	b.WriteString(" ")
+	for i, section := range sections {
+		if i > 0 {
+			b.WriteString("| ")
+		}
+		for _, word := range Tokenize(section) {
+			b.WriteString(word)
+			b.WriteString(" ")
+		}
+	}
+	return b.String()
+}
+
+// highlight returns text, HTML-escaped, with the words that are part of a
+// match of one of phrases wrapped in <mark>. Long texts are cut around the
+// first match when cut is set. It returns an empty string if nothing matches.
+func highlight(text string, phrases []string, cut bool) string {
+	spans := wordSpans(text)
+	words := Tokenize(text)
+	marked := make([]bool, len(spans))
+	first := len(spans)
+	for _, phrase := range phrases {
+		target := strings.Split(phrase, " ")
+		for i := 0; i+len(target) <= len(words); i++ {
+			if slices.Equal(words[i:i+len(target)], target) {
+				for j := i; j < i+len(target); j++ {
+					marked[j] = true
+				}
+				first = min(first, i)
+			}
+		}
+	}
+	if first == len(spans) {
+		return ""
+	}
+
+	from, to := 0, len(text)
+	if cut && len(text) > snippetLength {
+		from = max(spans[first].start-snippetContext, 0)
+		// Start and end on word boundaries.
+		for _, s := range spans {
+			if s.start >= from {
+				from = s.start
+				break
+			}
+		}
+		to = min(from+snippetLength, len(text))
+		for i := len(spans) - 1; i >= 0 && to < len(text); i-- {
+			if spans[i].end <= to && spans[i].start >= from {
+				to = spans[i].end
+				break
+			}
+		}
+	}
+
+	var b strings.Builder
+	if from > 0 {
+		b.WriteString("…")
+	}
+	pos := from
+	for i := 0; i < len(spans); i++ {
+		if !marked[i] || spans[i].start < from || spans[i].end > to {
+			continue
+		}
+		// Marked words next to each other share one mark.
+		j := i
+		for j+1 < len(spans) && marked[j+1] && spans[j+1].end <= to {
+			j++
+		}
+		b.WriteString(html.EscapeString(text[pos:spans[i].start]))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(text[spans[i].start:spans[j].end]))
+		b.WriteString("</mark>")
+		pos = spans[j].end
+		i = j
+	}
+	b.WriteString(html.EscapeString(text[pos:to]))
+	if to < len(text) {
+		b.WriteString("…")
+	}
+	return b.String()
+}
@@ -0,0 +1,389 @@
+package template
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	v1 "github.com/AldiandyaIrsyad/author-notes/api/v1/template"
+	app_auth "github.com/AldiandyaIrsyad/author-notes/internal/auth"
+	auth_adapter "github.com/AldiandyaIrsyad/author-notes/internal/auth/adapter"
+	app_project "github.com/AldiandyaIrsyad/author-notes/internal/project"
+	app_template "github.com/AldiandyaIrsyad/author-notes/internal/template"
+)
+
+type TemplateHTTPHandler struct {
+	service app_template.TemplateService
+	logger  *slog.Logger
+}
+
+func NewTemplateHTTPHandler(service app_template.TemplateService, logger *slog.Logger) *TemplateHTTPHandler {
+	return &TemplateHTTPHandler{service: service, logger: logger}
+}
+
+// internalError logs err and responds with a generic 500 error that does not leak it.
+func (h *TemplateHTTPHandler) internalError(c *gin.Context, message string, err error) {
+	h.logger.ErrorContext(c.Request.Context(), message, "route", c.FullPath(), "error", err)
+	c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+}
+
+// RegisterRoutes registers the template routes on rg, which must be authenticated.
+func (h *TemplateHTTPHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	read := auth_adapter.RequirePermission(app_auth.PermContentRead)
+	write := auth_adapter.RequirePermission(app_auth.PermContentWrite)
+
+	rg.GET("/projects/:id/templates", read, h.ListTemplates)
+	rg.POST("/projects/:id/templates", write, h.CreateTemplate)
+	rg.POST("/projects/:id/templates/import-starter", write, h.ImportStarters)
+
+	templatesGroup := rg.Group("/templates")
+	templatesGroup.GET("/starters", read, h.ListStarters)
+	templatesGroup.POST("/import", write, h.ImportTemplate)
+	templatesGroup.GET("/:id", read, h.GetTemplate)
+	templatesGroup.PUT("/:id", write, h.UpdateTemplate)
+	templatesGroup.DELETE("/:id", write, h.DeleteTemplate)
+	templatesGroup.GET("/:id/export", read, h.ExportTemplate)
+	templatesGroup.GET("/:id/versions", read, h.ListVersions)
+	templatesGroup.GET("/:id/versions/:version", read, h.GetVersion)
+}
+
+// ListTemplates handles the request for the templates of a project.
+// @Summary List the templates of a project
+// @Description Returns every template of a project of the caller, sorted by name.
+// @Tags templates
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Success 200 {array} template.Template "Templates"
+// @Failure 401 {object} map[string]string "Missing or invalid token"
+// @Failure 403 {object} map[string]string "Missing permission"
+// @Failure 404 {object} map[string]string "Project not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/projects/{id}/templates [get]
+func (h *TemplateHTTPHandler) ListTemplates(c *gin.Context) {
+	claims, _ := app_auth.ClaimsFromContext(c.Request.Context())
+
+	templates, err := h.service.ListTemplates(c.Request.Context(), claims.UserID, c.Param("id"))
+	if err != nil {
+		h.respondError(c, "Failed to list templates", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, templates)
+}
+
+// CreateTemplate handles the request to create a template.
+// @Summary Create a template
+// @Description Creates a template in a project of the caller from rows of the caller's fields. Template names are unique within a project. Each row holds one to three slots whose widths add up to at most three columns; each field is placed once and its default must be a valid value of it.
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Param template body v1.CreateTemplateRequest true "Template name and layout"
+// @Success 201 {object} template.Template "Template created"
+// @Failure 400 {object} map[string]string "Validation error, invalid layout or invalid default value"
+// @Failure 401 {object} map[string]string "Missing or invalid token"
+// @Failure 403 {object} map[string]string "Missing permission"
+// @Failure 404 {object} map[string]string "Project not found"
+// @Failure 409 {object} map[string]string "Template name already used"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/projects/{id}/templates [post]
+func (h *TemplateHTTPHandler) CreateTemplate(c *gin.Context) {
+	var req v1.CreateTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	claims, _ := app_auth.ClaimsFromContext(c.Request.Context())
+
+	template, err := h.service.CreateTemplate(c.Request.Context(), claims.UserID, c.Param("id"), req)
+	if err != nil {
+		h.respondError(c, "Failed to create template", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, template)
+}
+
+// ListStarters handles the request for the built-in starter templates.
+// @Summary List starter templates
+// @Description Returns the built-in starter templates that can be imported into a project, sorted by key. Their slots define fields by name instead of referring to fields of the caller.
+// @Tags templates
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} template.Starter "Starter templates"
+// @Failure 401 {object} map[string]string "Missing or invalid token"
+// @Failure 403 {object} map[string]string "Missing permission"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/templates/starters [get]
+func (h *TemplateHTTPHandler) ListStarters(c *gin.Context) {
+	starters, err := h.service.ListStarters(c.Request.Context())
+	if err != nil {
+		h.internalError(c, "Failed to list starter templates", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, starters)
+}
+
+// ImportStarters handles the request to import starter templates into a project.
+// @Summary Import starter templates
+// @Description Creates the listed starter templates, or all of them when none are listed, in a project of the caller. Fields of the caller with the names a starter uses are reused; missing ones are created. Starters whose name is already used in the project are skipped. The body may be omitted.
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Project ID"
+// @Param starters body v1.ImportStartersRequest false "Keys of the starters to import"
+// @Success 200 {object} template.ImportReport "Created and skipped templates"
+// @Failure 400 {object} map[string]string "Validation error or unknown starter"
+// @Failure 401 {object} map[string]string "Missing or invalid token"
+// @Failure 403 {object} map[string]string "Missing permission"
+// @Failure 404 {object} map[string]string "Project not found"
+// @Failure 409 {object} map[string]string "A field of the caller has a starter's field name but another kind"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/projects/{id}/templates/import-starter [post]
+func (h *TemplateHTTPHandler) ImportStarters(c *gin.Context) {
+	var req v1.ImportStartersRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	claims, _ := app_auth.ClaimsFromContext(c.Request.Context())
+
+	report, err := h.service.ImportStarters(c.Request.Context(), claims.UserID, c.Param("id"), req)
+	if err != nil {
+		h.respondError(c, "Failed to import starter templates", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// GetTemplate handles the request for a single template.
+// @Summary Get a template
+// @Tags templates
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Template ID"
+// @Success 200 {object} template.Template "Template"
+// @Failure 401 {object} map[string]string "Missing or invalid token"
+// @Failure 403 {object} map[string]string "Missing permission"
+// @Failure 404 {object} map[string]string "Template not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/templates/{id} [get]
+func (h *TemplateHTTPHandler) GetTemplate(c *gin.Context) {
+	claims, _ := app_auth.ClaimsFromContext(c.Request.Context())
+
+	template, err := h.service.GetTemplate(c.Request.Context(), claims.UserID, c.Param("id"))
+	if err != nil {
+		h.respondError(c, "Failed to get template", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+// UpdateTemplate handles the request to update a template.
+// @Summary Update a template
+// @Description Replaces the name, description and layout of a template and creates its next version. Existing notes stay on their version until they are upgraded; renames tell the upgrade which added field takes over the values of a removed one.
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Template ID"
+// @Param template body v1.UpdateTemplateRequest true "New template name and layout"
+// @Success 200 {object} template.Template "Updated template"
+// @Failure 400 {object} map[string]string "Validation error, invalid layout, default value or renames"
+// @Failure 401 {object} map[string]string "Missing or invalid token"
+// @Failure 403 {object} map[string]string "Missing permission"
+// @Failure 404 {object} map[string]string "Template not found"
+// @Failure 409 {object} map[string]string "Template name already used"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/templates/{id} [put]
+func (h *TemplateHTTPHandler) UpdateTemplate(c *gin.Context) {
+	var req v1.UpdateTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	claims, _ := app_auth.ClaimsFromContext(c.Request.Context())
+
+	template, err := h.service.UpdateTemplate(c.Request.Context(), claims.UserID, c.Param("id"), req)
+	if err != nil {
+		h.respondError(c, "Failed to update template", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+// DeleteTemplate handles the request to delete a template.
+// @Summary Delete a template
+// @Description Deletes a template and all of its versions. A template that notes are still filled in from cannot be deleted.
+// @Tags templates
+// @Security BearerAuth
+// @Param id path string true "Template ID"
+// @Success 204 "Template deleted"
+// @Failure 401 {object} map[string]string "Missing or invalid token"
+// @Failure 403 {object} map[string]string "Missing permission"
+// @Failure 404 {object} map[string]string "Template not found"
+// @Failure 409 {object} map[string]string "Template is still used by notes"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/templates/{id} [delete]
+func (h *TemplateHTTPHandler) DeleteTemplate(c *gin.Context) {
+	claims, _ := app_auth.ClaimsFromContext(c.Request.Context())
+
+	if err := h.service.DeleteTemplate(c.Request.Context(), claims.UserID, c.Param("id")); err != nil {
+		h.respondError(c, "Failed to delete template", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ExportTemplate handles the request to export a template.
+// @Summary Export a template
+// @Description Returns a template of the caller in the portable interchange format, with the definitions of its fields instead of their IDs, so that other users can import it.
+// @Tags templates
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Template ID"
+// @Success 200 {object} template.Document "Template document"
+// @Failure 401 {object} map[string]string "Missing or invalid token"
+// @Failure 403 {object} map[string]string "Missing permission"
+// @Failure 404 {object} map[string]string "Template not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/templates/{id}/export [get]
+func (h *TemplateHTTPHandler) ExportTemplate(c *gin.Context) {
+	claims, _ := app_auth.ClaimsFromContext(c.Request.Context())
+
+	doc, err := h.service.ExportTemplate(c.Request.Context(), claims.UserID, c.Param("id"))
+	if err != nil {
+		h.respondError(c, "Failed to export template", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, doc)
+}
+
+// ImportTemplate handles the request to import a template.
+// @Summary Import a template
+// @Description Creates a template in a project of the caller from an exported template document. Fields of the caller with the names the document uses are reused and must be of the same kind; missing ones are created. Documents of another format or of a newer schema version are rejected.
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param project_id query string true "Project to import the template into"
+// @Param template body v1.ImportTemplateRequest true "Template document"
+// @Success 201 {object} template.Template "Template created"
+// @Failure 400 {object} map[string]string "Validation error, invalid layout or incompatible document"
+// @Failure 401 {object} map[string]string "Missing or invalid token"
+// @Failure 403 {object} map[string]string "Missing permission"
+// @Failure 404 {object} map[string]string "Project not found"
+// @Failure 409 {object} map[string]string "Template name already used, or a field of the caller has a name of the document but another kind"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/templates/import [post]
+func (h *TemplateHTTPHandler) ImportTemplate(c *gin.Context) {
+	projectID := c.Query("project_id")
+	if projectID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query parameters: project_id is required"})
+		return
+	}
+	var req v1.ImportTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	claims, _ := app_auth.ClaimsFromContext(c.Request.Context())
+
+	template, err := h.service.ImportTemplate(c.Request.Context(), claims.UserID, projectID, req)
+	if err != nil {
+		h.respondError(c, "Failed to import template", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, template)
+}
+
+// ListVersions handles the request for the version history of a template.
+// @Summary List template versions
+// @Description Returns every version of a template, oldest first. Versions are immutable.
+// @Tags templates
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Template ID"
+// @Success 200 {array} template.Version "Versions"
+// @Failure 401 {object} map[string]string "Missing or invalid token"
+// @Failure 403 {object} map[string]string "Missing permission"
+// @Failure 404 {object} map[string]string "Template not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/templates/{id}/versions [get]
+func (h *TemplateHTTPHandler) ListVersions(c *gin.Context) {
+	claims, _ := app_auth.ClaimsFromContext(c.Request.Context())
+
+	versions, err := h.service.ListVersions(c.Request.Context(), claims.UserID, c.Param("id"))
+	if err != nil {
+		h.respondError(c, "Failed to list template versions", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, versions)
+}
+
+// GetVersion handles the request for a single version of a template.
+// @Summary Get a template version
+// @Tags templates
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Template ID"
+// @Param version path int true "Version number"
+// @Success 200 {object} template.Version "Version"
+// @Failure 401 {object} map[string]string "Missing or invalid token"
+// @Failure 403 {object} map[string]string "Missing permission"
+// @Failure 404 {object} map[string]string "Template or version not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/templates/{id}/versions/{version} [get]
+func (h *TemplateHTTPHandler) GetVersion(c *gin.Context) {
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": app_template.ErrVersionNotFound.Error()})
+		return
+	}
+	claims, _ := app_auth.ClaimsFromContext(c.Request.Context())
+
+	v, err := h.service.GetVersion(c.Request.Context(), claims.UserID, c.Param("id"), version)
+	if err != nil {
+		h.respondError(c, "Failed to get template version", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, v)
+}
+
+// respondError maps the errors of the template service to HTTP responses.
+func (h *TemplateHTTPHandler) respondError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, app_template.ErrValidationFailed),
+		errors.Is(err, app_template.ErrInvalidLayout),
+		errors.Is(err, app_template.ErrInvalidDefault),
+		errors.Is(err, app_template.ErrInvalidRenames),
+		errors.Is(err, app_template.ErrIncompatibleDocument):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, app_template.ErrTemplateNotFound),
+		errors.Is(err, app_template.ErrVersionNotFound),
+		errors.Is(err, app_project.ErrProjectNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, app_template.ErrTemplateNameTaken),
+		errors.Is(err, app_template.ErrFieldConflict),
+		errors.Is(err, app_template.ErrTemplateInUse):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		h.internalError(c, message, err)
+	}
+}
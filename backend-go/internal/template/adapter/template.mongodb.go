@@ -0,0 +1,175 @@
+package template
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	app_template "github.com/AldiandyaIrsyad/author-notes/internal/template"
+)
+
+// mongoTemplateRepository implements the TemplateRepository interface using MongoDB.
+type mongoTemplateRepository struct {
+	collection *mongo.Collection
+	versions   *mongo.Collection
+}
+
+// NewMongoTemplateRepository creates a new instance of mongoTemplateRepository.
+func NewMongoTemplateRepository(db *mongo.Database) app_template.TemplateRepository {
+	return &mongoTemplateRepository{
+		collection: db.Collection("templates"),
+		versions:   db.Collection("template_versions"),
+	}
+}
+
+// CreateTemplate inserts a new template.
+func (r *mongoTemplateRepository) CreateTemplate(ctx context.Context, template *app_template.Template) error {
+	if template.ID == "" {
+		template.ID = uuid.NewString()
+	}
+	_, err := r.collection.InsertOne(ctx, template)
+	return err
+}
+
+// UpdateTemplate replaces a stored template with the given one.
+func (r *mongoTemplateRepository) UpdateTemplate(ctx context.Context, template *app_template.Template) error {
+	result, err := r.collection.ReplaceOne(ctx, bson.M{"_id": template.ID, "owner_id": template.OwnerID}, template)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return app_template.ErrTemplateNotFound
+	}
+	return nil
+}
+
+// FindTemplateByID retrieves a template by its ID.
+func (r *mongoTemplateRepository) FindTemplateByID(ctx context.Context, id string) (*app_template.Template, error) {
+	return r.findOne(ctx, bson.M{"_id": id})
+}
+
+// FindTemplateByName retrieves the template of a project with the given name.
+func (r *mongoTemplateRepository) FindTemplateByName(ctx context.Context, projectID, name string) (*app_template.Template, error) {
+	return r.findOne(ctx, bson.M{"project_id": projectID, "name": name})
+}
+
+func (r *mongoTemplateRepository) findOne(ctx context.Context, filter bson.M) (*app_template.Template, error) {
+	var template app_template.Template
+	err := r.collection.FindOne(ctx, filter).Decode(&template)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, app_template.ErrTemplateNotFound
+		}
+		return nil, err
+	}
+	return &template, nil
+}
+
+// ListTemplatesByOwner returns every template of an owner, sorted by name.
+func (r *mongoTemplateRepository) ListTemplatesByOwner(ctx context.Context, ownerID string) ([]*app_template.Template, error) {
+	return r.find(ctx, bson.M{"owner_id": ownerID})
+}
+
+// ListTemplatesByProject returns every template of a project, sorted by name.
+func (r *mongoTemplateRepository) ListTemplatesByProject(ctx context.Context, projectID string) ([]*app_template.Template, error) {
+	return r.find(ctx, bson.M{"project_id": projectID})
+}
+
+func (r *mongoTemplateRepository) find(ctx context.Context, filter bson.M) ([]*app_template.Template, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	templates := []*app_template.Template{}
+	if err := cursor.All(ctx, &templates); err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+// DeleteTemplate removes a template of an owner.
+func (r *mongoTemplateRepository) DeleteTemplate(ctx context.Context, ownerID, id string) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id, "owner_id": ownerID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return app_template.ErrTemplateNotFound
+	}
+	return nil
+}
+
+// DeleteTemplatesByOwner removes every template of an owner.
+func (r *mongoTemplateRepository) DeleteTemplatesByOwner(ctx context.Context, ownerID string) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"owner_id": ownerID})
+	return err
+}
+
+// DeleteTemplatesByProject removes every template of a project.
+func (r *mongoTemplateRepository) DeleteTemplatesByProject(ctx context.Context, projectID string) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"project_id": projectID})
+	return err
+}
+
+// CountTemplatesByField returns the number of templates of an owner that
+// place a field.
+func (r *mongoTemplateRepository) CountTemplatesByField(ctx context.Context, ownerID, fieldID string) (int64, error) {
+	return r.collection.CountDocuments(ctx, bson.M{"owner_id": ownerID, "rows.slots.field_id": fieldID})
+}
+
+// CreateVersion inserts a version of a template.
+func (r *mongoTemplateRepository) CreateVersion(ctx context.Context, version *app_template.Version) error {
+	if version.ID == "" {
+		version.ID = uuid.NewString()
+	}
+	_, err := r.versions.InsertOne(ctx, version)
+	return err
+}
+
+// FindVersion retrieves a version of a template.
+func (r *mongoTemplateRepository) FindVersion(ctx context.Context, templateID string, version int) (*app_template.Version, error) {
+	var v app_template.Version
+	err := r.versions.FindOne(ctx, bson.M{"template_id": templateID, "version": version}).Decode(&v)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, app_template.ErrVersionNotFound
+		}
+		return nil, err
+	}
+	return &v, nil
+}
+
+// ListVersions returns every version of a template, oldest first.
+func (r *mongoTemplateRepository) ListVersions(ctx context.Context, templateID string) ([]*app_template.Version, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "version", Value: 1}})
+	cursor, err := r.versions.Find(ctx, bson.M{"template_id": templateID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	versions := []*app_template.Version{}
+	if err := cursor.All(ctx, &versions); err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+// DeleteVersionsByTemplate removes every version of a template.
+func (r *mongoTemplateRepository) DeleteVersionsByTemplate(ctx context.Context, templateID string) error {
+	_, err := r.versions.DeleteMany(ctx, bson.M{"template_id": templateID})
+	return err
+}
+
+// DeleteVersionsByOwner removes every template version of an owner.
+func (r *mongoTemplateRepository) DeleteVersionsByOwner(ctx context.Context, ownerID string) error {
+	_, err := r.versions.DeleteMany(ctx, bson.M{"owner_id": ownerID})
+	return err
+}
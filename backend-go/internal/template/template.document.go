@@ -0,0 +1,241 @@
+package template
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	v1 "github.com/AldiandyaIrsyad/author-notes/api/v1/template"
+	"github.com/AldiandyaIrsyad/author-notes/internal/field"
+)
+
+// ExportTemplate returns a template of the owner as a document. Slots of
+// fields deleted after they were placed are left out.
+func (s *templateService) ExportTemplate(ctx context.Context, ownerID, id string) (*Document, error) {
+	template, err := s.GetTemplate(ctx, ownerID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &Document{
+		Format:        DocumentFormat,
+		SchemaVersion: DocumentSchemaVersion,
+		Name:          template.Name,
+		Description:   template.Description,
+		Rows:          make([]DocumentRow, 0, len(template.Rows)),
+	}
+	for _, row := range template.Rows {
+		docRow := DocumentRow{Slots: make([]DocumentSlot, 0, len(row.Slots))}
+		for _, slot := range row.Slots {
+			f, err := s.fields.FindFieldByID(ctx, slot.FieldID)
+			if errors.Is(err, field.ErrFieldNotFound) || err == nil && f.OwnerID != ownerID {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			// Template IDs only make sense within the account, so reference
+			// fields travel without their target template.
+			options := f.Options
+			options.TargetTemplateID = ""
+			docRow.Slots = append(docRow.Slots, DocumentSlot{
+				Field:    DocumentField{Name: f.Name, Description: f.Description, Kind: f.Kind, Options: options},
+				Width:    slot.Width,
+				Required: slot.Required,
+				Default:  slot.Default,
+			})
+		}
+		if len(docRow.Slots) > 0 {
+			doc.Rows = append(doc.Rows, docRow)
+		}
+	}
+	return doc, nil
+}
+
+// ImportTemplate validates a document and creates its template in a project
+// of the owner. Nothing is created if the document is invalid or one of its
+// fields conflicts with a field of the owner.
+func (s *templateService) ImportTemplate(ctx context.Context, ownerID, projectID string, req v1.ImportTemplateRequest) (*Template, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, ErrValidationFailed
+	}
+	if req.Format != DocumentFormat || req.SchemaVersion > DocumentSchemaVersion {
+		return nil, fmt.Errorf("%w: %s version %d is not %s version %d or older",
+			ErrIncompatibleDocument, req.Format, req.SchemaVersion, DocumentFormat, DocumentSchemaVersion)
+	}
+
+	if err := s.checkProject(ctx, ownerID, projectID); err != nil {
+		return nil, err
+	}
+	rows := documentRows(req.Rows)
+	if err := checkDocument(rows); err != nil {
+		return nil, err
+	}
+	now := time.Now().Unix()
+	template := &Template{
+		OwnerID:     ownerID,
+		ProjectID:   projectID,
+		Name:        strings.TrimSpace(req.Name),
+		Description: strings.TrimSpace(req.Description),
+		Version:     1,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.checkName(ctx, template); err != nil {
+		return nil, err
+	}
+
+	fields, err := s.resolveFields(ctx, ownerID, FieldDefinitions(rows))
+	if err != nil {
+		return nil, err
+	}
+	if err := s.fromDocument(ctx, template, rows, fields); err != nil {
+		return nil, err
+	}
+	if err := s.create(ctx, template); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+// documentRows converts imported rows into document rows.
+func documentRows(reqRows []v1.ImportRow) []DocumentRow {
+	rows := make([]DocumentRow, 0, len(reqRows))
+	for _, reqRow := range reqRows {
+		row := DocumentRow{Slots: make([]DocumentSlot, 0, len(reqRow.Slots))}
+		for _, reqSlot := range reqRow.Slots {
+			slot := DocumentSlot{
+				Field: DocumentField{
+					Name:        strings.TrimSpace(reqSlot.Field.Name),
+					Description: strings.TrimSpace(reqSlot.Field.Description),
+					Kind:        field.Kind(reqSlot.Field.Kind),
+					Options:     field.Options(reqSlot.Field.Options),
+				},
+				Width:    reqSlot.Width,
+				Required: reqSlot.Required,
+				Default:  reqSlot.Default,
+			}
+			if slot.Width == 0 {
+				slot.Width = 1
+			}
+			slot.Field.Options.TargetTemplateID = ""
+			row.Slots = append(row.Slots, slot)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// checkDocument checks the layout and field definitions of document rows
+// before any of their fields are created. The layout rules are those of
+// templates, with fields identified by name.
+func checkDocument(rows []DocumentRow) error {
+	placed := make(map[string]bool)
+	for i, row := range rows {
+		if len(row.Slots) == 0 {
+			return fmt.Errorf("%w: row %d is empty", ErrInvalidLayout, i+1)
+		}
+		columns := 0
+		for _, slot := range row.Slots {
+			if slot.Width < 1 || slot.Width > MaxColumns {
+				return fmt.Errorf("%w: slot widths are 1 to %d columns", ErrInvalidLayout, MaxColumns)
+			}
+			columns += slot.Width
+			if slot.Field.Name == "" {
+				return ErrValidationFailed
+			}
+			if placed[slot.Field.Name] {
+				return fmt.Errorf("%w: field %s is placed twice", ErrInvalidLayout, slot.Field.Name)
+			}
+			placed[slot.Field.Name] = true
+
+			options := cloneOptions(slot.Field.Options)
+			if err := field.ValidateOptions(slot.Field.Kind, &options); err != nil {
+				return fmt.Errorf("%w: field %s: %w", ErrInvalidLayout, slot.Field.Name, err)
+			}
+		}
+		if columns > MaxColumns {
+			return fmt.Errorf("%w: row %d spans %d columns, more than %d", ErrInvalidLayout, i+1, columns, MaxColumns)
+		}
+	}
+	return nil
+}
+
+// resolveFields looks up the fields of the owner with the names of
+// definitions, keyed by name. Names the owner does not use map to nil. It
+// runs before any field is created, so that a conflict creates nothing.
+func (s *templateService) resolveFields(ctx context.Context, ownerID string, definitions []DocumentField) (map[string]*field.Field, error) {
+	fields := make(map[string]*field.Field)
+	for _, def := range definitions {
+		if _, seen := fields[def.Name]; seen {
+			continue
+		}
+		f, err := s.fields.FindFieldByName(ctx, ownerID, def.Name)
+		if err != nil && !errors.Is(err, field.ErrFieldNotFound) {
+			return nil, err
+		}
+		if f != nil && f.Kind != def.Kind {
+			return nil, fmt.Errorf("%w: field %s is not a %s field", ErrFieldConflict, def.Name, def.Kind)
+		}
+		fields[def.Name] = f
+	}
+	return fields, nil
+}
+
+// fromDocument lays out template from document rows. Fields missing from
+// fields, which is keyed by name, are created and added to it. Defaults that
+// are not valid values of a field the owner already had are dropped.
+func (s *templateService) fromDocument(ctx context.Context, template *Template, rows []DocumentRow, fields map[string]*field.Field) error {
+	template.Rows = make([]Row, 0, len(rows))
+	for _, docRow := range rows {
+		row := Row{Slots: make([]Slot, 0, len(docRow.Slots))}
+		for _, docSlot := range docRow.Slots {
+			f := fields[docSlot.Field.Name]
+			if f == nil {
+				var err error
+				if f, err = s.createField(ctx, template.OwnerID, docSlot.Field, template.CreatedAt); err != nil {
+					return err
+				}
+				fields[f.Name] = f
+			}
+			slot := Slot{FieldID: f.ID, Width: docSlot.Width, Required: docSlot.Required}
+			if !field.IsEmptyValue(docSlot.Default) && f.ValidateValue(docSlot.Default) == nil {
+				slot.Default = docSlot.Default
+			}
+			row.Slots = append(row.Slots, slot)
+		}
+		template.Rows = append(template.Rows, row)
+	}
+	return nil
+}
+
+// createField creates a field of the owner from a field definition.
+func (s *templateService) createField(ctx context.Context, ownerID string, def DocumentField, now int64) (*field.Field, error) {
+	f := &field.Field{
+		OwnerID:     ownerID,
+		Name:        def.Name,
+		Description: def.Description,
+		Kind:        def.Kind,
+		Options:     cloneOptions(def.Options),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := field.ValidateOptions(f.Kind, &f.Options); err != nil {
+		return nil, err
+	}
+	if err := s.fields.CreateField(ctx, f); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// cloneOptions copies options, so that normalising the copy in place leaves
+// the lists of the original intact.
+func cloneOptions(options field.Options) field.Options {
+	options.Choices = slices.Clone(options.Choices)
+	options.Schemes = slices.Clone(options.Schemes)
+	return options
+}
@@ -0,0 +1,16 @@
+package template
+
+import "errors"
+
+var (
+	ErrTemplateNotFound     = errors.New("template not found")
+	ErrTemplateNameTaken    = errors.New("a template with this name already exists")
+	ErrValidationFailed     = errors.New("input validation failed")
+	ErrInvalidLayout        = errors.New("invalid template layout")
+	ErrInvalidDefault       = errors.New("invalid default value")
+	ErrInvalidRenames       = errors.New("invalid field renames")
+	ErrVersionNotFound      = errors.New("template version not found")
+	ErrFieldConflict        = errors.New("a field of the template conflicts with an existing field")
+	ErrIncompatibleDocument = errors.New("incompatible template document")
+	ErrTemplateInUse        = errors.New("template is still used by notes")
+)
@@ -0,0 +1,149 @@
+package template
+
+import "github.com/AldiandyaIrsyad/author-notes/internal/field"
+
+// MaxColumns is the number of columns of a template row.
+const MaxColumns = 3
+
+// Template is a layout of fields that notes are created from, such as
+// "Character" or "Location". It belongs to a project of its owner.
+type Template struct {
+	ID          string `bson:"_id,omitempty" json:"id"`
+	OwnerID     string `bson:"owner_id" json:"owner_id"`
+	ProjectID   string `bson:"project_id" json:"project_id"`
+	Name        string `bson:"name" json:"name"`
+	Description string `bson:"description,omitempty" json:"description,omitempty"`
+	Rows        []Row  `bson:"rows" json:"rows"`
+	Version     int    `bson:"version" json:"version"` // incremented by every update, starting at 1
+	CreatedAt   int64  `bson:"created_at" json:"created_at"`
+	UpdatedAt   int64  `bson:"updated_at" json:"updated_at"`
+}
+
+// Version is an immutable snapshot of a template, stored by every create and
+// update. Notes are pinned to the version they were last checked against.
+type Version struct {
+	ID          string `bson:"_id,omitempty" json:"-"`
+	TemplateID  string `bson:"template_id" json:"template_id"`
+	OwnerID     string `bson:"owner_id" json:"owner_id"`
+	Version     int    `bson:"version" json:"version"`
+	Name        string `bson:"name" json:"name"`
+	Description string `bson:"description,omitempty" json:"description,omitempty"`
+	Rows        []Row  `bson:"rows" json:"rows"`
+	// Renames maps fields removed by this version to the fields that replace them.
+	Renames   map[string]string `bson:"renames,omitempty" json:"renames,omitempty"`
+	CreatedAt int64             `bson:"created_at" json:"created_at"`
+}
+
+// Snapshot returns the version of the template as it is now.
+func (t *Template) Snapshot(renames map[string]string) *Version {
+	return &Version{
+		TemplateID:  t.ID,
+		OwnerID:     t.OwnerID,
+		Version:     t.Version,
+		Name:        t.Name,
+		Description: t.Description,
+		Rows:        t.Rows,
+		Renames:     renames,
+		CreatedAt:   t.UpdatedAt,
+	}
+}
+
+// Row is a row of slots whose widths add up to at most MaxColumns.
+type Row struct {
+	Slots []Slot `bson:"slots" json:"slots"`
+}
+
+// Slot places a field definition in a row.
+type Slot struct {
+	FieldID  string `bson:"field_id" json:"field_id"`
+	Width    int    `bson:"width" json:"width"` // columns spanned, 1 to 3
+	Required bool   `bson:"required,omitempty" json:"required,omitempty"`
+	Default  any    `bson:"default,omitempty" json:"default,omitempty"`
+}
+
+// FieldIDs returns the IDs of the fields of the template in layout order.
+func (t *Template) FieldIDs() []string {
+	return fieldIDs(t.Rows)
+}
+
+// FieldIDs returns the IDs of the fields of the version in layout order.
+func (v *Version) FieldIDs() []string {
+	return fieldIDs(v.Rows)
+}
+
+func fieldIDs(rows []Row) []string {
+	var ids []string
+	for _, row := range rows {
+		for _, slot := range row.Slots {
+			ids = append(ids, slot.FieldID)
+		}
+	}
+	return ids
+}
+
+// DocumentFormat and DocumentSchemaVersion identify the interchange format
+// of templates. The schema version changes whenever a document of the new
+// version could not be read by an older server.
+const (
+	DocumentFormat        = "author-notes/template"
+	DocumentSchemaVersion = 1
+)
+
+// Document is a template in the portable interchange format. Its slots hold
+// field definitions instead of field IDs, so that any user can import it:
+// importing reuses the importer's fields with those names and creates the
+// missing ones.
+type Document struct {
+	Format        string        `json:"format"`
+	SchemaVersion int           `json:"schema_version"`
+	Name          string        `json:"name"`
+	Description   string        `json:"description,omitempty"`
+	Rows          []DocumentRow `json:"rows"`
+}
+
+// DocumentRow is a row of a portable template.
+type DocumentRow struct {
+	Slots []DocumentSlot `json:"slots"`
+}
+
+// DocumentSlot places a field definition in a row of a portable template.
+type DocumentSlot struct {
+	Field    DocumentField `json:"field"`
+	Width    int           `json:"width"`
+	Required bool          `json:"required,omitempty"`
+	Default  any           `json:"default,omitempty"`
+}
+
+// DocumentField is a field definition carried by a portable template.
+type DocumentField struct {
+	Name        string        `json:"name"`
+	Description string        `json:"description,omitempty"`
+	Kind        field.Kind    `json:"kind"`
+	Options     field.Options `json:"options"`
+}
+
+// FieldDefinitions returns the fields of the rows in layout order.
+func FieldDefinitions(rows []DocumentRow) []DocumentField {
+	var fields []DocumentField
+	for _, row := range rows {
+		for _, slot := range row.Slots {
+			fields = append(fields, slot.Field)
+		}
+	}
+	return fields
+}
+
+// Starter is a built-in template that can be imported into a project. Like
+// documents, its slots define their fields by name.
+type Starter struct {
+	Key         string        `json:"key"` // name of its data file, e.g. "scene_card"
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	Rows        []DocumentRow `json:"rows"`
+}
+
+// ImportReport is the outcome of importing starter templates into a project.
+type ImportReport struct {
+	Created []*Template `json:"created"`
+	Skipped []string    `json:"skipped"` // keys of starters whose name is already used in the project
+}
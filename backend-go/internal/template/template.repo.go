@@ -0,0 +1,69 @@
+package template
+
+import (
+	"context"
+
+	"github.com/AldiandyaIrsyad/author-notes/internal/field"
+	"github.com/AldiandyaIrsyad/author-notes/internal/project"
+)
+
+// TemplateRepository defines the interface for template database operations.
+type TemplateRepository interface {
+	// CreateTemplate inserts a new template, assigning its ID.
+	CreateTemplate(ctx context.Context, template *Template) error
+	// UpdateTemplate replaces a stored template with the given one.
+	// Returns ErrTemplateNotFound if the template does not exist.
+	UpdateTemplate(ctx context.Context, template *Template) error
+	// FindTemplateByID retrieves a template by its ID.
+	// Returns ErrTemplateNotFound if the template does not exist.
+	FindTemplateByID(ctx context.Context, id string) (*Template, error)
+	// FindTemplateByName retrieves the template of a project with the given name.
+	// Returns ErrTemplateNotFound if the project has no such template.
+	FindTemplateByName(ctx context.Context, projectID, name string) (*Template, error)
+	// ListTemplatesByOwner returns every template of an owner, sorted by name.
+	ListTemplatesByOwner(ctx context.Context, ownerID string) ([]*Template, error)
+	// ListTemplatesByProject returns every template of a project, sorted by name.
+	ListTemplatesByProject(ctx context.Context, projectID string) ([]*Template, error)
+	// DeleteTemplate removes a template of an owner.
+	// Returns ErrTemplateNotFound if the owner has no such template.
+	DeleteTemplate(ctx context.Context, ownerID, id string) error
+	// DeleteTemplatesByOwner removes every template of an owner.
+	DeleteTemplatesByOwner(ctx context.Context, ownerID string) error
+	// DeleteTemplatesByProject removes every template of a project.
+	DeleteTemplatesByProject(ctx context.Context, projectID string) error
+	// CountTemplatesByField returns the number of templates of an owner that
+	// place a field.
+	CountTemplatesByField(ctx context.Context, ownerID, fieldID string) (int64, error)
+
+	// CreateVersion inserts a version of a template, assigning its ID.
+	CreateVersion(ctx context.Context, version *Version) error
+	// FindVersion retrieves a version of a template.
+	// Returns ErrVersionNotFound if the template has no such version.
+	FindVersion(ctx context.Context, templateID string, version int) (*Version, error)
+	// ListVersions returns every version of a template, oldest first.
+	ListVersions(ctx context.Context, templateID string) ([]*Version, error)
+	// DeleteVersionsByTemplate removes every version of a template.
+	DeleteVersionsByTemplate(ctx context.Context, templateID string) error
+	// DeleteVersionsByOwner removes every template version of an owner.
+	DeleteVersionsByOwner(ctx context.Context, ownerID string) error
+}
+
+// FieldStore is the subset of field.FieldRepository used to check the fields
+// a template places and to create the fields of starter templates.
+type FieldStore interface {
+	FindFieldByID(ctx context.Context, id string) (*field.Field, error)
+	FindFieldByName(ctx context.Context, ownerID, name string) (*field.Field, error)
+	CreateField(ctx context.Context, field *field.Field) error
+}
+
+// ProjectStore is the subset of project.ProjectRepository used to check the
+// project a template is created in.
+type ProjectStore interface {
+	FindProjectByID(ctx context.Context, id string) (*project.Project, error)
+}
+
+// NoteStore is the subset of note.NoteRepository used to keep templates that
+// notes are still filled in from.
+type NoteStore interface {
+	CountNotesByTemplate(ctx context.Context, ownerID, templateID string) (int64, error)
+}
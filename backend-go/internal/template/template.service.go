@@ -0,0 +1,413 @@
+package template
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+
+	v1 "github.com/AldiandyaIrsyad/author-notes/api/v1/template"
+	"github.com/AldiandyaIrsyad/author-notes/internal/account"
+	"github.com/AldiandyaIrsyad/author-notes/internal/field"
+	"github.com/AldiandyaIrsyad/author-notes/internal/project"
+)
+
+// TemplateService defines the interface for managing templates. Every method
+// is scoped to the owner: templates and projects of other users are reported
+// as not found.
+type TemplateService interface {
+	// CreateTemplate creates a template in a project of the owner from a
+	// layout of the owner's fields.
+	CreateTemplate(ctx context.Context, ownerID, projectID string, req v1.CreateTemplateRequest) (*Template, error)
+	// GetTemplate returns a template of the owner.
+	GetTemplate(ctx context.Context, ownerID, id string) (*Template, error)
+	// ListTemplates returns every template of a project of the owner, sorted by name.
+	ListTemplates(ctx context.Context, ownerID, projectID string) ([]*Template, error)
+	// UpdateTemplate replaces the name, description and layout of a template,
+	// creating its next version.
+	UpdateTemplate(ctx context.Context, ownerID, id string, req v1.UpdateTemplateRequest) (*Template, error)
+	// DeleteTemplate deletes a template of the owner and all of its versions.
+	// Notes filled in from the template must be deleted first.
+	DeleteTemplate(ctx context.Context, ownerID, id string) error
+	// ListVersions returns every version of a template of the owner, oldest first.
+	ListVersions(ctx context.Context, ownerID, id string) ([]*Version, error)
+	// GetVersion returns a version of a template of the owner.
+	GetVersion(ctx context.Context, ownerID, id string, version int) (*Version, error)
+	// ListStarters returns the built-in starter templates, sorted by key.
+	ListStarters(ctx context.Context) ([]*Starter, error)
+	// ImportStarters creates starter templates in a project of the owner,
+	// along with the fields they define that the owner does not have yet.
+	ImportStarters(ctx context.Context, ownerID, projectID string, req v1.ImportStartersRequest) (*ImportReport, error)
+	// ExportTemplate returns a template of the owner in the interchange format.
+	ExportTemplate(ctx context.Context, ownerID, id string) (*Document, error)
+	// ImportTemplate creates a template in a project of the owner from a
+	// document in the interchange format, along with the fields it defines
+	// that the owner does not have yet.
+	ImportTemplate(ctx context.Context, ownerID, projectID string, req v1.ImportTemplateRequest) (*Template, error)
+
+	account.ContentProvider
+	project.ContentRemover
+}
+
+type templateService struct {
+	repo      TemplateRepository
+	fields    FieldStore
+	projects  ProjectStore
+	notes     NoteStore
+	validator *validator.Validate
+}
+
+// NewTemplateService creates a new instance of TemplateService.
+func NewTemplateService(repo TemplateRepository, fields FieldStore, projects ProjectStore, notes NoteStore) TemplateService {
+	return &templateService{
+		repo:      repo,
+		fields:    fields,
+		projects:  projects,
+		notes:     notes,
+		validator: validator.New(),
+	}
+}
+
+// CreateTemplate validates and stores a new template.
+func (s *templateService) CreateTemplate(ctx context.Context, ownerID, projectID string, req v1.CreateTemplateRequest) (*Template, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, ErrValidationFailed
+	}
+
+	if err := s.checkProject(ctx, ownerID, projectID); err != nil {
+		return nil, err
+	}
+	rows, err := s.layout(ctx, ownerID, req.Rows)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().Unix()
+	template := &Template{
+		OwnerID:     ownerID,
+		ProjectID:   projectID,
+		Name:        strings.TrimSpace(req.Name),
+		Description: strings.TrimSpace(req.Description),
+		Rows:        rows,
+		Version:     1,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.checkName(ctx, template); err != nil {
+		return nil, err
+	}
+
+	if err := s.create(ctx, template); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+// create stores a new template and its first version.
+func (s *templateService) create(ctx context.Context, template *Template) error {
+	if err := s.repo.CreateTemplate(ctx, template); err != nil {
+		return err
+	}
+	return s.repo.CreateVersion(ctx, template.Snapshot(nil))
+}
+
+// GetTemplate returns a template if it belongs to the owner.
+func (s *templateService) GetTemplate(ctx context.Context, ownerID, id string) (*Template, error) {
+	template, err := s.repo.FindTemplateByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if template.OwnerID != ownerID {
+		return nil, ErrTemplateNotFound
+	}
+	return template, nil
+}
+
+// ListTemplates returns the templates of a project of the owner.
+func (s *templateService) ListTemplates(ctx context.Context, ownerID, projectID string) ([]*Template, error) {
+	if err := s.checkProject(ctx, ownerID, projectID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListTemplatesByProject(ctx, projectID)
+}
+
+// UpdateTemplate validates and stores the new name, description and layout of a template.
+func (s *templateService) UpdateTemplate(ctx context.Context, ownerID, id string, req v1.UpdateTemplateRequest) (*Template, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, ErrValidationFailed
+	}
+
+	template, err := s.GetTemplate(ctx, ownerID, id)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.layout(ctx, ownerID, req.Rows)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkRenames(template.FieldIDs(), fieldIDs(rows), req.Renames); err != nil {
+		return nil, err
+	}
+	template.Name = strings.TrimSpace(req.Name)
+	template.Description = strings.TrimSpace(req.Description)
+	template.Rows = rows
+	template.Version++
+	template.UpdatedAt = time.Now().Unix()
+	if err := s.checkName(ctx, template); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateTemplate(ctx, template); err != nil {
+		return nil, err
+	}
+	if err := s.repo.CreateVersion(ctx, template.Snapshot(req.Renames)); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+// DeleteTemplate removes a template of the owner and its versions.
+// Returns ErrTemplateInUse while notes of the owner are filled in from it.
+func (s *templateService) DeleteTemplate(ctx context.Context, ownerID, id string) error {
+	count, err := s.notes.CountNotesByTemplate(ctx, ownerID, id)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrTemplateInUse
+	}
+	if err := s.repo.DeleteTemplate(ctx, ownerID, id); err != nil {
+		return err
+	}
+	return s.repo.DeleteVersionsByTemplate(ctx, id)
+}
+
+// ListVersions returns the versions of a template of the owner.
+func (s *templateService) ListVersions(ctx context.Context, ownerID, id string) ([]*Version, error) {
+	if _, err := s.GetTemplate(ctx, ownerID, id); err != nil {
+		return nil, err
+	}
+	return s.repo.ListVersions(ctx, id)
+}
+
+// GetVersion returns a version of a template of the owner.
+func (s *templateService) GetVersion(ctx context.Context, ownerID, id string, version int) (*Version, error) {
+	template, err := s.GetTemplate(ctx, ownerID, id)
+	if err != nil {
+		return nil, err
+	}
+	v, err := s.repo.FindVersion(ctx, id, version)
+	if errors.Is(err, ErrVersionNotFound) && version == template.Version {
+		// Templates created before versions were stored only have their latest version.
+		return template.Snapshot(nil), nil
+	}
+	return v, err
+}
+
+// checkProject checks that a project exists and belongs to the owner.
+func (s *templateService) checkProject(ctx context.Context, ownerID, projectID string) error {
+	p, err := s.projects.FindProjectByID(ctx, projectID)
+	if err != nil {
+		return err
+	}
+	if p.OwnerID != ownerID {
+		return project.ErrProjectNotFound
+	}
+	return nil
+}
+
+// checkName checks that the name of a template is not used by another
+// template of the same project.
+func (s *templateService) checkName(ctx context.Context, template *Template) error {
+	if template.Name == "" {
+		return ErrValidationFailed
+	}
+	existing, err := s.repo.FindTemplateByName(ctx, template.ProjectID, template.Name)
+	if err != nil && !errors.Is(err, ErrTemplateNotFound) {
+		return err
+	}
+	if existing != nil && existing.ID != template.ID {
+		return ErrTemplateNameTaken
+	}
+	return nil
+}
+
+// layout converts requested rows into the rows of a template. Each row spans
+// at most MaxColumns, each field is placed once and belongs to the owner, and
+// defaults are valid values of their field.
+func (s *templateService) layout(ctx context.Context, ownerID string, reqRows []v1.TemplateRow) ([]Row, error) {
+	rows := make([]Row, 0, len(reqRows))
+	placed := make(map[string]bool)
+	for i, reqRow := range reqRows {
+		if len(reqRow.Slots) == 0 {
+			return nil, fmt.Errorf("%w: row %d is empty", ErrInvalidLayout, i+1)
+		}
+		row := Row{Slots: make([]Slot, 0, len(reqRow.Slots))}
+		columns := 0
+		for _, reqSlot := range reqRow.Slots {
+			slot := Slot{
+				FieldID:  reqSlot.FieldID,
+				Width:    reqSlot.Width,
+				Required: reqSlot.Required,
+				Default:  reqSlot.Default,
+			}
+			if slot.Width == 0 {
+				slot.Width = 1
+			}
+			if slot.Width < 1 || slot.Width > MaxColumns {
+				return nil, fmt.Errorf("%w: slot widths are 1 to %d columns", ErrInvalidLayout, MaxColumns)
+			}
+			columns += slot.Width
+			if placed[slot.FieldID] {
+				return nil, fmt.Errorf("%w: field %s is placed twice", ErrInvalidLayout, slot.FieldID)
+			}
+			placed[slot.FieldID] = true
+
+			if err := s.checkSlot(ctx, ownerID, &slot); err != nil {
+				return nil, err
+			}
+			row.Slots = append(row.Slots, slot)
+		}
+		if columns > MaxColumns {
+			return nil, fmt.Errorf("%w: row %d spans %d columns, more than %d", ErrInvalidLayout, i+1, columns, MaxColumns)
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// checkRenames checks that renames map fields removed by an update to fields
+// added by it, one to one.
+func checkRenames(before, after []string, renames map[string]string) error {
+	wasPlaced := make(map[string]bool, len(before))
+	for _, id := range before {
+		wasPlaced[id] = true
+	}
+	isPlaced := make(map[string]bool, len(after))
+	for _, id := range after {
+		isPlaced[id] = true
+	}
+
+	replaced := make(map[string]bool, len(renames))
+	for from, to := range renames {
+		if !wasPlaced[from] || isPlaced[from] {
+			return fmt.Errorf("%w: field %s is not removed by this update", ErrInvalidRenames, from)
+		}
+		if wasPlaced[to] || !isPlaced[to] {
+			return fmt.Errorf("%w: field %s is not added by this update", ErrInvalidRenames, to)
+		}
+		if replaced[to] {
+			return fmt.Errorf("%w: field %s replaces more than one field", ErrInvalidRenames, to)
+		}
+		replaced[to] = true
+	}
+	return nil
+}
+
+// checkSlot checks that the field of a slot belongs to the owner and that the
+// default, if any, is a valid value of it.
+func (s *templateService) checkSlot(ctx context.Context, ownerID string, slot *Slot) error {
+	f, err := s.fields.FindFieldByID(ctx, slot.FieldID)
+	if errors.Is(err, field.ErrFieldNotFound) || err == nil && f.OwnerID != ownerID {
+		return fmt.Errorf("%w: field %s not found", ErrInvalidLayout, slot.FieldID)
+	}
+	if err != nil {
+		return err
+	}
+
+	if field.IsEmptyValue(slot.Default) {
+		slot.Default = nil
+		return nil
+	}
+	if err := f.ValidateValue(slot.Default); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidDefault, err)
+	}
+	return nil
+}
+
+// Name identifies templates in data exports.
+func (s *templateService) Name() string {
+	return "templates"
+}
+
+// ExportUserContent returns every template of the user and its versions.
+func (s *templateService) ExportUserContent(ctx context.Context, userID string) ([]account.ExportDocument, error) {
+	templates, err := s.repo.ListTemplatesByOwner(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	var docs []account.ExportDocument
+	for _, template := range templates {
+		markdown, err := s.markdown(ctx, template)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, account.ExportDocument{ID: template.ID, Data: template, Markdown: markdown})
+
+		versions, err := s.repo.ListVersions(ctx, template.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range versions {
+			docs = append(docs, account.ExportDocument{ID: fmt.Sprintf("%s.v%d", template.ID, v.Version), Data: v})
+		}
+	}
+	return docs, nil
+}
+
+// markdown renders a template for data exports: its name and description,
+// then the fields of each row.
+func (s *templateService) markdown(ctx context.Context, template *Template) (string, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", template.Name)
+	if template.Description != "" {
+		fmt.Fprintf(&b, "%s\n\n", template.Description)
+	}
+	fmt.Fprintf(&b, "- Version: %d\n", template.Version)
+	for i, row := range template.Rows {
+		fmt.Fprintf(&b, "\n## Row %d\n\n", i+1)
+		for _, slot := range row.Slots {
+			name, kind := slot.FieldID, "deleted field"
+			f, err := s.fields.FindFieldByID(ctx, slot.FieldID)
+			if err != nil && !errors.Is(err, field.ErrFieldNotFound) {
+				return "", err
+			}
+			if err == nil && f.OwnerID == template.OwnerID {
+				name, kind = f.Name, string(f.Kind)
+			}
+			fmt.Fprintf(&b, "- %s (%s), width %d", name, kind, slot.Width)
+			if slot.Required {
+				b.WriteString(", required")
+			}
+			if slot.Default != nil {
+				fmt.Fprintf(&b, ", default: %v", slot.Default)
+			}
+			b.WriteString("\n")
+		}
+	}
+	return b.String(), nil
+}
+
+// DeleteUserContent removes every template of the user and its versions.
+func (s *templateService) DeleteUserContent(ctx context.Context, userID string) error {
+	if err := s.repo.DeleteTemplatesByOwner(ctx, userID); err != nil {
+		return err
+	}
+	return s.repo.DeleteVersionsByOwner(ctx, userID)
+}
+
+// DeleteProjectContent removes every template of the project and its versions.
+func (s *templateService) DeleteProjectContent(ctx context.Context, projectID string) error {
+	templates, err := s.repo.ListTemplatesByProject(ctx, projectID)
+	if err != nil {
+		return err
+	}
+	for _, template := range templates {
+		if err := s.repo.DeleteVersionsByTemplate(ctx, template.ID); err != nil {
+			return err
+		}
+	}
+	return s.repo.DeleteTemplatesByProject(ctx, projectID)
+}
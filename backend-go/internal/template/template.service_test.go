@@ -0,0 +1,616 @@
+package template
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	fieldv1 "github.com/AldiandyaIrsyad/author-notes/api/v1/field"
+	v1 "github.com/AldiandyaIrsyad/author-notes/api/v1/template"
+	"github.com/AldiandyaIrsyad/author-notes/internal/field"
+	"github.com/AldiandyaIrsyad/author-notes/internal/project"
+)
+
+// MockTemplateRepository is a mock implementation of TemplateRepository
+type MockTemplateRepository struct {
+	mock.Mock
+}
+
+func (m *MockTemplateRepository) CreateTemplate(ctx context.Context, template *Template) error {
+	args := m.Called(ctx, template)
+	return args.Error(0)
+}
+
+func (m *MockTemplateRepository) UpdateTemplate(ctx context.Context, template *Template) error {
+	args := m.Called(ctx, template)
+	return args.Error(0)
+}
+
+func (m *MockTemplateRepository) FindTemplateByID(ctx context.Context, id string) (*Template, error) {
+	args := m.Called(ctx, id)
+	if template := args.Get(0); template != nil {
+		return template.(*Template), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockTemplateRepository) FindTemplateByName(ctx context.Context, projectID, name string) (*Template, error) {
+	args := m.Called(ctx, projectID, name)
+	if template := args.Get(0); template != nil {
+		return template.(*Template), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockTemplateRepository) ListTemplatesByOwner(ctx context.Context, ownerID string) ([]*Template, error) {
+	args := m.Called(ctx, ownerID)
+	if templates := args.Get(0); templates != nil {
+		return templates.([]*Template), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockTemplateRepository) ListTemplatesByProject(ctx context.Context, projectID string) ([]*Template, error) {
+	args := m.Called(ctx, projectID)
+	if templates := args.Get(0); templates != nil {
+		return templates.([]*Template), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockTemplateRepository) DeleteTemplate(ctx context.Context, ownerID, id string) error {
+	args := m.Called(ctx, ownerID, id)
+	return args.Error(0)
+}
+
+func (m *MockTemplateRepository) DeleteTemplatesByOwner(ctx context.Context, ownerID string) error {
+	args := m.Called(ctx, ownerID)
+	return args.Error(0)
+}
+
+func (m *MockTemplateRepository) DeleteTemplatesByProject(ctx context.Context, projectID string) error {
+	args := m.Called(ctx, projectID)
+	return args.Error(0)
+}
+
+func (m *MockTemplateRepository) CountTemplatesByField(ctx context.Context, ownerID, fieldID string) (int64, error) {
+	args := m.Called(ctx, ownerID, fieldID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockTemplateRepository) CreateVersion(ctx context.Context, version *Version) error {
+	args := m.Called(ctx, version)
+	return args.Error(0)
+}
+
+func (m *MockTemplateRepository) FindVersion(ctx context.Context, templateID string, version int) (*Version, error) {
+	args := m.Called(ctx, templateID, version)
+	if v := args.Get(0); v != nil {
+		return v.(*Version), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockTemplateRepository) ListVersions(ctx context.Context, templateID string) ([]*Version, error) {
+	args := m.Called(ctx, templateID)
+	if versions := args.Get(0); versions != nil {
+		return versions.([]*Version), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockTemplateRepository) DeleteVersionsByTemplate(ctx context.Context, templateID string) error {
+	args := m.Called(ctx, templateID)
+	return args.Error(0)
+}
+
+func (m *MockTemplateRepository) DeleteVersionsByOwner(ctx context.Context, ownerID string) error {
+	args := m.Called(ctx, ownerID)
+	return args.Error(0)
+}
+
+// stubFieldStore serves a fixed set of fields.
+type stubFieldStore map[string]*field.Field
+
+func (s stubFieldStore) FindFieldByID(_ context.Context, id string) (*field.Field, error) {
+	if f, ok := s[id]; ok {
+		return f, nil
+	}
+	return nil, field.ErrFieldNotFound
+}
+
+func (s stubFieldStore) FindFieldByName(_ context.Context, ownerID, name string) (*field.Field, error) {
+	for _, f := range s {
+		if f.OwnerID == ownerID && f.Name == name {
+			return f, nil
+		}
+	}
+	return nil, field.ErrFieldNotFound
+}
+
+func (s stubFieldStore) CreateField(_ context.Context, f *field.Field) error {
+	f.ID = "new " + f.Name
+	s[f.ID] = f
+	return nil
+}
+
+// stubProjectStore serves a fixed set of projects.
+type stubProjectStore map[string]*project.Project
+
+func (s stubProjectStore) FindProjectByID(_ context.Context, id string) (*project.Project, error) {
+	if p, ok := s[id]; ok {
+		return p, nil
+	}
+	return nil, project.ErrProjectNotFound
+}
+
+var testProjects = stubProjectStore{
+	"novel":  {ID: "novel", OwnerID: "user123", Title: "Novel"},
+	"theirs": {ID: "theirs", OwnerID: "someone", Title: "Theirs"},
+}
+
+// stubNoteStore serves the number of notes filled in from each template.
+type stubNoteStore map[string]int64
+
+func (s stubNoteStore) CountNotesByTemplate(_ context.Context, _, templateID string) (int64, error) {
+	return s[templateID], nil
+}
+
+var testNotes = stubNoteStore{"used": 2}
+
+var testFields = stubFieldStore{
+	"name":  {ID: "name", OwnerID: "user123", Name: "Name", Kind: field.KindShortText},
+	"age":   {ID: "age", OwnerID: "user123", Name: "Age", Kind: field.KindNumber, Options: field.Options{Integer: true}},
+	"eyes":  {ID: "eyes", OwnerID: "user123", Name: "Eyes", Kind: field.KindSingleSelect, Options: field.Options{Choices: []string{"blue", "green"}}},
+	"bio":   {ID: "bio", OwnerID: "user123", Name: "Bio", Kind: field.KindLongText},
+	"other": {ID: "other", OwnerID: "someone", Name: "Secret", Kind: field.KindBoolean},
+}
+
+func slots(ids ...string) v1.TemplateRow {
+	row := v1.TemplateRow{}
+	for _, id := range ids {
+		row.Slots = append(row.Slots, v1.TemplateSlot{FieldID: id})
+	}
+	return row
+}
+
+func TestTemplateService_CreateTemplate(t *testing.T) {
+	mockRepo := new(MockTemplateRepository)
+	service := NewTemplateService(mockRepo, testFields, testProjects, testNotes)
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		req := v1.CreateTemplateRequest{
+			Name: "Character",
+			Rows: []v1.TemplateRow{
+				slots("name", "age", "eyes"),
+				{Slots: []v1.TemplateSlot{{FieldID: "bio", Width: 3, Required: true, Default: "  "}}},
+			},
+		}
+		req.Rows[0].Slots[2].Default = "blue"
+		mockRepo.On("FindTemplateByName", ctx, "novel", "Character").Return(nil, ErrTemplateNotFound).Once()
+		mockRepo.On("CreateTemplate", ctx, mock.AnythingOfType("*template.Template")).Return(nil).Once()
+		mockRepo.On("CreateVersion", ctx, mock.MatchedBy(func(v *Version) bool {
+			return v.Version == 1 && v.Name == "Character" && len(v.Rows) == 2
+		})).Return(nil).Once()
+
+		template, err := service.CreateTemplate(ctx, "user123", "novel", req)
+
+		require.NoError(t, err)
+		assert.Equal(t, "novel", template.ProjectID)
+		require.Len(t, template.Rows, 2)
+		assert.Equal(t, 1, template.Version)
+		assert.Equal(t, 1, template.Rows[0].Slots[0].Width)
+		assert.Equal(t, "blue", template.Rows[0].Slots[2].Default)
+		assert.Nil(t, template.Rows[1].Slots[0].Default)
+		assert.True(t, template.Rows[1].Slots[0].Required)
+		assert.Equal(t, []string{"name", "age", "eyes", "bio"}, template.FieldIDs())
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Too Many Slots In Row", func(t *testing.T) {
+		req := v1.CreateTemplateRequest{Name: "Character", Rows: []v1.TemplateRow{slots("name", "age", "eyes", "bio")}}
+
+		_, err := service.CreateTemplate(ctx, "user123", "novel", req)
+
+		assert.ErrorIs(t, err, ErrValidationFailed)
+	})
+
+	t.Run("Row Wider Than Three Columns", func(t *testing.T) {
+		row := slots("name", "bio")
+		row.Slots[1].Width = 3
+		req := v1.CreateTemplateRequest{Name: "Character", Rows: []v1.TemplateRow{row}}
+
+		_, err := service.CreateTemplate(ctx, "user123", "novel", req)
+
+		assert.ErrorIs(t, err, ErrInvalidLayout)
+	})
+
+	t.Run("Field Placed Twice", func(t *testing.T) {
+		req := v1.CreateTemplateRequest{Name: "Character", Rows: []v1.TemplateRow{slots("name"), slots("age", "name")}}
+
+		_, err := service.CreateTemplate(ctx, "user123", "novel", req)
+
+		assert.ErrorIs(t, err, ErrInvalidLayout)
+	})
+
+	t.Run("Field Of Other Owner", func(t *testing.T) {
+		req := v1.CreateTemplateRequest{Name: "Character", Rows: []v1.TemplateRow{slots("other")}}
+
+		_, err := service.CreateTemplate(ctx, "user123", "novel", req)
+
+		assert.ErrorIs(t, err, ErrInvalidLayout)
+	})
+
+	t.Run("Invalid Default", func(t *testing.T) {
+		row := slots("age")
+		row.Slots[0].Default = 1.5
+		req := v1.CreateTemplateRequest{Name: "Character", Rows: []v1.TemplateRow{row}}
+
+		_, err := service.CreateTemplate(ctx, "user123", "novel", req)
+
+		assert.ErrorIs(t, err, ErrInvalidDefault)
+		assert.ErrorIs(t, err, field.ErrInvalidValue)
+	})
+
+	t.Run("Project Of Other Owner", func(t *testing.T) {
+		_, err := service.CreateTemplate(ctx, "user123", "theirs", v1.CreateTemplateRequest{Name: "Character"})
+
+		assert.ErrorIs(t, err, project.ErrProjectNotFound)
+	})
+
+	t.Run("Name Taken", func(t *testing.T) {
+		mockRepo.On("FindTemplateByName", ctx, "novel", "Character").Return(&Template{ID: "tpl1"}, nil).Once()
+
+		_, err := service.CreateTemplate(ctx, "user123", "novel", v1.CreateTemplateRequest{Name: "Character"})
+
+		assert.ErrorIs(t, err, ErrTemplateNameTaken)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestTemplateService_UpdateTemplate(t *testing.T) {
+	mockRepo := new(MockTemplateRepository)
+	service := NewTemplateService(mockRepo, testFields, testProjects, testNotes)
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		stored := &Template{ID: "tpl1", OwnerID: "user123", ProjectID: "novel", Name: "Character", Version: 1, Rows: []Row{{Slots: []Slot{{FieldID: "name", Width: 1}, {FieldID: "eyes", Width: 1}}}}}
+		mockRepo.On("FindTemplateByID", ctx, "tpl1").Return(stored, nil).Once()
+		mockRepo.On("FindTemplateByName", ctx, "novel", "Person").Return(nil, ErrTemplateNotFound).Once()
+		mockRepo.On("UpdateTemplate", ctx, stored).Return(nil).Once()
+		mockRepo.On("CreateVersion", ctx, mock.MatchedBy(func(v *Version) bool {
+			return v.TemplateID == "tpl1" && v.Version == 2 && v.Renames["eyes"] == "age"
+		})).Return(nil).Once()
+
+		req := v1.UpdateTemplateRequest{
+			Name:    "Person",
+			Rows:    []v1.TemplateRow{slots("name", "age")},
+			Renames: map[string]string{"eyes": "age"},
+		}
+		template, err := service.UpdateTemplate(ctx, "user123", "tpl1", req)
+
+		require.NoError(t, err)
+		assert.Equal(t, "Person", template.Name)
+		assert.Equal(t, 2, template.Version)
+		assert.Equal(t, []string{"name", "age"}, template.FieldIDs())
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Rename Of Field Still Placed", func(t *testing.T) {
+		stored := &Template{ID: "tpl1", OwnerID: "user123", Name: "Character", Version: 1, Rows: []Row{{Slots: []Slot{{FieldID: "name", Width: 1}}}}}
+		mockRepo.On("FindTemplateByID", ctx, "tpl1").Return(stored, nil).Once()
+
+		req := v1.UpdateTemplateRequest{
+			Name:    "Character",
+			Rows:    []v1.TemplateRow{slots("name", "age")},
+			Renames: map[string]string{"name": "age"},
+		}
+		_, err := service.UpdateTemplate(ctx, "user123", "tpl1", req)
+
+		assert.ErrorIs(t, err, ErrInvalidRenames)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Other Owner", func(t *testing.T) {
+		mockRepo.On("FindTemplateByID", ctx, "tpl1").Return(&Template{ID: "tpl1", OwnerID: "someone"}, nil).Once()
+
+		_, err := service.UpdateTemplate(ctx, "user123", "tpl1", v1.UpdateTemplateRequest{Name: "Person"})
+
+		assert.ErrorIs(t, err, ErrTemplateNotFound)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestTemplateService_Versions(t *testing.T) {
+	mockRepo := new(MockTemplateRepository)
+	service := NewTemplateService(mockRepo, testFields, testProjects, testNotes)
+	ctx := context.Background()
+
+	t.Run("Get Stored Version", func(t *testing.T) {
+		mockRepo.On("FindTemplateByID", ctx, "tpl1").Return(&Template{ID: "tpl1", OwnerID: "user123", Version: 3}, nil).Once()
+		mockRepo.On("FindVersion", ctx, "tpl1", 2).Return(&Version{TemplateID: "tpl1", Version: 2}, nil).Once()
+
+		v, err := service.GetVersion(ctx, "user123", "tpl1", 2)
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, v.Version)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Latest Version Of Unversioned Template", func(t *testing.T) {
+		mockRepo.On("FindTemplateByID", ctx, "tpl1").Return(&Template{ID: "tpl1", OwnerID: "user123", Name: "Character", Version: 1}, nil).Once()
+		mockRepo.On("FindVersion", ctx, "tpl1", 1).Return(nil, ErrVersionNotFound).Once()
+
+		v, err := service.GetVersion(ctx, "user123", "tpl1", 1)
+
+		require.NoError(t, err)
+		assert.Equal(t, "Character", v.Name)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Delete Removes Versions", func(t *testing.T) {
+		mockRepo.On("DeleteTemplate", ctx, "user123", "tpl1").Return(nil).Once()
+		mockRepo.On("DeleteVersionsByTemplate", ctx, "tpl1").Return(nil).Once()
+
+		require.NoError(t, service.DeleteTemplate(ctx, "user123", "tpl1"))
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Delete Template Used By Notes", func(t *testing.T) {
+		err := service.DeleteTemplate(ctx, "user123", "used")
+
+		assert.ErrorIs(t, err, ErrTemplateInUse)
+		mockRepo.AssertNotCalled(t, "DeleteTemplate", ctx, "user123", "used")
+	})
+}
+
+func TestTemplateService_UserContent(t *testing.T) {
+	mockRepo := new(MockTemplateRepository)
+	service := NewTemplateService(mockRepo, testFields, testProjects, testNotes)
+	ctx := context.Background()
+
+	mockRepo.On("ListTemplatesByOwner", ctx, "user123").Return([]*Template{{
+		ID: "tpl1", OwnerID: "user123", Name: "Character", Description: "People of the story.", Version: 2,
+		Rows: []Row{{Slots: []Slot{{FieldID: "name", Width: 2, Required: true}, {FieldID: "other", Width: 1, Default: true}}}},
+	}}, nil).Once()
+	mockRepo.On("ListVersions", ctx, "tpl1").Return([]*Version{{TemplateID: "tpl1", Version: 1}}, nil).Once()
+	mockRepo.On("DeleteTemplatesByOwner", ctx, "user123").Return(nil).Once()
+	mockRepo.On("DeleteVersionsByOwner", ctx, "user123").Return(nil).Once()
+
+	docs, err := service.ExportUserContent(ctx, "user123")
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+	assert.Equal(t, "tpl1", docs[0].ID)
+	assert.Equal(t, "tpl1.v1", docs[1].ID)
+	assert.Equal(t, "# Character\n\nPeople of the story.\n\n- Version: 2\n\n## Row 1\n\n"+
+		"- Name (short_text), width 2, required\n- other (deleted field), width 1, default: true\n", docs[0].Markdown)
+	assert.Empty(t, docs[1].Markdown)
+	assert.Equal(t, "templates", service.Name())
+
+	require.NoError(t, service.DeleteUserContent(ctx, "user123"))
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTemplateService_ProjectContent(t *testing.T) {
+	mockRepo := new(MockTemplateRepository)
+	service := NewTemplateService(mockRepo, testFields, testProjects, testNotes)
+	ctx := context.Background()
+
+	t.Run("List Of Other Owner", func(t *testing.T) {
+		_, err := service.ListTemplates(ctx, "user123", "theirs")
+
+		assert.ErrorIs(t, err, project.ErrProjectNotFound)
+	})
+
+	t.Run("Delete Removes Templates And Versions", func(t *testing.T) {
+		mockRepo.On("ListTemplatesByProject", ctx, "novel").Return([]*Template{{ID: "tpl1"}, {ID: "tpl2"}}, nil).Once()
+		mockRepo.On("DeleteVersionsByTemplate", ctx, "tpl1").Return(nil).Once()
+		mockRepo.On("DeleteVersionsByTemplate", ctx, "tpl2").Return(nil).Once()
+		mockRepo.On("DeleteTemplatesByProject", ctx, "novel").Return(nil).Once()
+
+		require.NoError(t, service.DeleteProjectContent(ctx, "novel"))
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestTemplateService_Starters(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Built-In Starters Are Valid", func(t *testing.T) {
+		starters, err := NewTemplateService(new(MockTemplateRepository), testFields, testProjects, testNotes).ListStarters(ctx)
+		require.NoError(t, err)
+
+		var keys []string
+		defs := make(map[string]DocumentField)
+		for _, starter := range starters {
+			keys = append(keys, starter.Key)
+			assert.NotEmpty(t, starter.Name, starter.Key)
+			for _, row := range starter.Rows {
+				columns := 0
+				for _, slot := range row.Slots {
+					columns += slot.Width
+					f := field.Field{Name: slot.Field.Name, Kind: slot.Field.Kind, Options: slot.Field.Options}
+					require.NoError(t, field.ValidateOptions(f.Kind, &f.Options), slot.Field.Name)
+					assert.NoError(t, f.ValidateValue(slot.Default), slot.Field.Name)
+					// Starters share fields by name, so equal names must mean equal fields.
+					if def, ok := defs[slot.Field.Name]; ok {
+						assert.Equal(t, def, slot.Field, slot.Field.Name)
+					}
+					defs[slot.Field.Name] = slot.Field
+				}
+				assert.LessOrEqual(t, columns, MaxColumns, starter.Key)
+			}
+		}
+		assert.Equal(t, []string{"chapter_outline", "character", "faction", "item", "location", "magic_system", "scene_card"}, keys)
+	})
+
+	t.Run("Import Reuses Fields Of The Same Kind", func(t *testing.T) {
+		mockRepo := new(MockTemplateRepository)
+		fields := stubFieldStore{
+			"desc":   {ID: "desc", OwnerID: "user123", Name: "Description", Kind: field.KindLongText},
+			"status": {ID: "status", OwnerID: "user123", Name: "Draft status", Kind: field.KindSingleSelect, Options: field.Options{Choices: []string{"todo", "done"}}},
+		}
+		service := NewTemplateService(mockRepo, fields, testProjects, testNotes)
+		mockRepo.On("FindTemplateByName", ctx, "novel", mock.Anything).Return(nil, ErrTemplateNotFound)
+		mockRepo.On("CreateTemplate", ctx, mock.AnythingOfType("*template.Template")).Return(nil)
+		mockRepo.On("CreateVersion", ctx, mock.AnythingOfType("*template.Version")).Return(nil)
+
+		report, err := service.ImportStarters(ctx, "user123", "novel", v1.ImportStartersRequest{Starters: []string{"location", "scene_card", "item"}})
+
+		require.NoError(t, err)
+		require.Len(t, report.Created, 3)
+		assert.Empty(t, report.Skipped)
+		location, sceneCard, item := report.Created[0], report.Created[1], report.Created[2]
+		assert.Equal(t, "Location", location.Name)
+		assert.Equal(t, "novel", location.ProjectID)
+		assert.Contains(t, location.FieldIDs(), "desc")
+		assert.Contains(t, item.FieldIDs(), "desc")
+		assert.Contains(t, sceneCard.FieldIDs(), "status")
+		// "idea" is not a choice of the existing status field, so the default is dropped.
+		assert.Nil(t, sceneCard.Rows[0].Slots[2].Default)
+		assert.Contains(t, sceneCard.FieldIDs(), "new Point of view")
+		assert.Len(t, fields, 2+5+5+4)
+	})
+
+	t.Run("Existing Template Is Skipped", func(t *testing.T) {
+		mockRepo := new(MockTemplateRepository)
+		service := NewTemplateService(mockRepo, testFields, testProjects, testNotes)
+		mockRepo.On("FindTemplateByName", ctx, "novel", "Character Sheet").Return(&Template{ID: "tpl1"}, nil).Once()
+
+		report, err := service.ImportStarters(ctx, "user123", "novel", v1.ImportStartersRequest{Starters: []string{"character"}})
+
+		require.NoError(t, err)
+		assert.Empty(t, report.Created)
+		assert.Equal(t, []string{"character"}, report.Skipped)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Field Of Another Kind Conflicts", func(t *testing.T) {
+		mockRepo := new(MockTemplateRepository)
+		fields := stubFieldStore{"goals": {ID: "goals", OwnerID: "user123", Name: "Goals", Kind: field.KindShortText}}
+		service := NewTemplateService(mockRepo, fields, testProjects, testNotes)
+		mockRepo.On("FindTemplateByName", ctx, "novel", mock.Anything).Return(nil, ErrTemplateNotFound)
+
+		_, err := service.ImportStarters(ctx, "user123", "novel", v1.ImportStartersRequest{Starters: []string{"character"}})
+
+		assert.ErrorIs(t, err, ErrFieldConflict)
+		assert.Len(t, fields, 1)
+		mockRepo.AssertNotCalled(t, "CreateTemplate", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Unknown Starter", func(t *testing.T) {
+		service := NewTemplateService(new(MockTemplateRepository), testFields, testProjects, testNotes)
+
+		_, err := service.ImportStarters(ctx, "user123", "novel", v1.ImportStartersRequest{Starters: []string{"dragon"}})
+
+		assert.ErrorIs(t, err, ErrValidationFailed)
+	})
+}
+
+func TestTemplateService_Documents(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Export Then Import Into Another Account", func(t *testing.T) {
+		mockRepo := new(MockTemplateRepository)
+		stored := &Template{ID: "tpl1", OwnerID: "user123", ProjectID: "novel", Name: "Character", Version: 2, Rows: []Row{
+			{Slots: []Slot{{FieldID: "name", Width: 2, Required: true}, {FieldID: "eyes", Width: 1, Default: "blue"}}},
+			{Slots: []Slot{{FieldID: "deleted", Width: 3}}},
+		}}
+		mockRepo.On("FindTemplateByID", ctx, "tpl1").Return(stored, nil).Once()
+
+		doc, err := NewTemplateService(mockRepo, testFields, testProjects, testNotes).ExportTemplate(ctx, "user123", "tpl1")
+
+		require.NoError(t, err)
+		assert.Equal(t, DocumentFormat, doc.Format)
+		assert.Equal(t, DocumentSchemaVersion, doc.SchemaVersion)
+		require.Len(t, doc.Rows, 1)
+		assert.Equal(t, "Eyes", doc.Rows[0].Slots[1].Field.Name)
+		assert.Equal(t, []string{"blue", "green"}, doc.Rows[0].Slots[1].Field.Options.Choices)
+
+		// The document travels as JSON to a friend, who already has a text field called Name.
+		data, err := json.Marshal(doc)
+		require.NoError(t, err)
+		var req v1.ImportTemplateRequest
+		require.NoError(t, json.Unmarshal(data, &req))
+		friendFields := stubFieldStore{"hers": {ID: "hers", OwnerID: "friend", Name: "Name", Kind: field.KindShortText}}
+		friendProjects := stubProjectStore{"saga": {ID: "saga", OwnerID: "friend"}}
+		mockRepo.On("FindTemplateByName", ctx, "saga", "Character").Return(nil, ErrTemplateNotFound).Once()
+		mockRepo.On("CreateTemplate", ctx, mock.AnythingOfType("*template.Template")).Return(nil).Once()
+		mockRepo.On("CreateVersion", ctx, mock.AnythingOfType("*template.Version")).Return(nil).Once()
+
+		template, err := NewTemplateService(mockRepo, friendFields, friendProjects, testNotes).ImportTemplate(ctx, "friend", "saga", req)
+
+		require.NoError(t, err)
+		assert.Equal(t, "saga", template.ProjectID)
+		assert.Equal(t, []string{"hers", "new Eyes"}, template.FieldIDs())
+		assert.Equal(t, "blue", template.Rows[0].Slots[1].Default)
+		assert.True(t, template.Rows[0].Slots[0].Required)
+		assert.Equal(t, field.KindSingleSelect, friendFields["new Eyes"].Kind)
+		mockRepo.AssertExpectations(t)
+	})
+
+	document := func() v1.ImportTemplateRequest {
+		return v1.ImportTemplateRequest{
+			Format:        DocumentFormat,
+			SchemaVersion: 1,
+			Name:          "Character",
+			Rows: []v1.ImportRow{{Slots: []v1.ImportSlot{
+				{Field: v1.ImportField{Name: "Age", Kind: "number"}},
+				{Field: v1.ImportField{Name: "Role", Kind: "single_select", Options: fieldv1.FieldOptions{Choices: []string{"hero"}}}},
+			}}},
+		}
+	}
+
+	t.Run("Newer Schema Version", func(t *testing.T) {
+		req := document()
+		req.SchemaVersion = DocumentSchemaVersion + 1
+
+		_, err := NewTemplateService(new(MockTemplateRepository), testFields, testProjects, testNotes).ImportTemplate(ctx, "user123", "novel", req)
+
+		assert.ErrorIs(t, err, ErrIncompatibleDocument)
+	})
+
+	t.Run("Other Format", func(t *testing.T) {
+		req := document()
+		req.Format = "someone-else/template"
+
+		_, err := NewTemplateService(new(MockTemplateRepository), testFields, testProjects, testNotes).ImportTemplate(ctx, "user123", "novel", req)
+
+		assert.ErrorIs(t, err, ErrIncompatibleDocument)
+	})
+
+	t.Run("Invalid Options Create Nothing", func(t *testing.T) {
+		req := document()
+		req.Rows[0].Slots[0].Field.Options.Choices = []string{"one"}
+		fields := stubFieldStore{}
+
+		_, err := NewTemplateService(new(MockTemplateRepository), fields, testProjects, testNotes).ImportTemplate(ctx, "user123", "novel", req)
+
+		assert.ErrorIs(t, err, ErrInvalidLayout)
+		assert.ErrorIs(t, err, field.ErrInvalidOptions)
+		assert.Empty(t, fields)
+	})
+
+	t.Run("Field Of Another Kind Creates Nothing", func(t *testing.T) {
+		mockRepo := new(MockTemplateRepository)
+		fields := stubFieldStore{"age": {ID: "age", OwnerID: "user123", Name: "Age", Kind: field.KindShortText}}
+		mockRepo.On("FindTemplateByName", ctx, "novel", "Character").Return(nil, ErrTemplateNotFound).Once()
+
+		_, err := NewTemplateService(mockRepo, fields, testProjects, testNotes).ImportTemplate(ctx, "user123", "novel", document())
+
+		assert.ErrorIs(t, err, ErrFieldConflict)
+		assert.Len(t, fields, 1)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Project Of Other Owner", func(t *testing.T) {
+		_, err := NewTemplateService(new(MockTemplateRepository), testFields, testProjects, testNotes).ImportTemplate(ctx, "user123", "theirs", document())
+
+		assert.ErrorIs(t, err, project.ErrProjectNotFound)
+	})
+}
@@ -0,0 +1,118 @@
+package template
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	v1 "github.com/AldiandyaIrsyad/author-notes/api/v1/template"
+)
+
+// starterFiles holds the built-in starter templates, one JSON file each.
+//
+//go:embed starters/*.json
+var starterFiles embed.FS
+
+// loadStarters parses the embedded starter templates once, sorted by key.
+var loadStarters = sync.OnceValues(func() ([]*Starter, error) {
+	entries, err := starterFiles.ReadDir("starters")
+	if err != nil {
+		return nil, err
+	}
+	starters := make([]*Starter, 0, len(entries))
+	for _, entry := range entries {
+		data, err := starterFiles.ReadFile(path.Join("starters", entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		var starter Starter
+		if err := json.Unmarshal(data, &starter); err != nil {
+			return nil, fmt.Errorf("starter template %s: %w", entry.Name(), err)
+		}
+		starter.Key = strings.TrimSuffix(entry.Name(), ".json")
+		starters = append(starters, &starter)
+	}
+	slices.SortFunc(starters, func(a, b *Starter) int { return strings.Compare(a.Key, b.Key) })
+	return starters, nil
+})
+
+// ListStarters returns the built-in starter templates.
+func (s *templateService) ListStarters(ctx context.Context) ([]*Starter, error) {
+	return loadStarters()
+}
+
+// ImportStarters creates the requested starter templates, or all of them, in
+// a project of the owner. Starters whose name is already used in the project
+// are skipped, so importing twice creates nothing new.
+func (s *templateService) ImportStarters(ctx context.Context, ownerID, projectID string, req v1.ImportStartersRequest) (*ImportReport, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, ErrValidationFailed
+	}
+
+	if err := s.checkProject(ctx, ownerID, projectID); err != nil {
+		return nil, err
+	}
+	starters, err := loadStarters()
+	if err != nil {
+		return nil, err
+	}
+	if len(req.Starters) > 0 {
+		selected := make([]*Starter, 0, len(req.Starters))
+		for _, key := range req.Starters {
+			i := slices.IndexFunc(starters, func(starter *Starter) bool { return starter.Key == key })
+			if i < 0 {
+				return nil, fmt.Errorf("%w: unknown starter template %s", ErrValidationFailed, key)
+			}
+			selected = append(selected, starters[i])
+		}
+		starters = selected
+	}
+
+	report := &ImportReport{Created: []*Template{}, Skipped: []string{}}
+	var pending []*Starter
+	var definitions []DocumentField
+	for _, starter := range starters {
+		existing, err := s.repo.FindTemplateByName(ctx, projectID, starter.Name)
+		if err != nil && !errors.Is(err, ErrTemplateNotFound) {
+			return nil, err
+		}
+		if existing != nil {
+			report.Skipped = append(report.Skipped, starter.Key)
+			continue
+		}
+		pending = append(pending, starter)
+		definitions = append(definitions, FieldDefinitions(starter.Rows)...)
+	}
+
+	fields, err := s.resolveFields(ctx, ownerID, definitions)
+	if err != nil {
+		return nil, err
+	}
+	for _, starter := range pending {
+		now := time.Now().Unix()
+		template := &Template{
+			OwnerID:     ownerID,
+			ProjectID:   projectID,
+			Name:        starter.Name,
+			Description: starter.Description,
+			Version:     1,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+		if err := s.fromDocument(ctx, template, starter.Rows, fields); err != nil {
+			return nil, err
+		}
+		if err := s.create(ctx, template); err != nil {
+			return nil, err
+		}
+		report.Created = append(report.Created, template)
+	}
+	return report, nil
+}
@@ -0,0 +1,960 @@
+// Code generated by cmd/openapi from the OpenAPI document of the API; DO NOT EDIT.
+// Regenerate with `go generate ./api` in backend-go.
+
+export interface AuditEvent {
+  action: string;
+  /** user who performed the action, empty if unknown */
+  actor_id?: string;
+  created_at: number;
+  id: string;
+  ip: string;
+  outcome: string;
+  reason?: string;
+  /** correlates the event with the request logs */
+  request_id?: string;
+  /** user the action was performed on, if not the actor */
+  target_id?: string;
+  user_agent: string;
+  /** as submitted, for failed logins of unknown users */
+  username?: string;
+}
+
+export interface AuditPage {
+  events: AuditEvent[];
+  next_cursor?: string;
+}
+
+export interface Backlink {
+  field_id: string;
+  note_id: string;
+  template_id: string;
+  title: string;
+}
+
+export interface ChangePasswordRequest {
+  current_password: string;
+  new_password: string;
+}
+
+export interface Conflict {
+  field_id: string;
+  note_id: string;
+  problem: string;
+}
+
+export interface CreateFieldRequest {
+  description?: string;
+  kind: "short_text" | "long_text" | "number" | "date" | "boolean" | "single_select" | "multi_select" | "url" | "reference";
+  name: string;
+  options: FieldOptions;
+}
+
+export interface CreateInviteRequest {
+  /** defaults to 7 days */
+  expires_in_days?: number;
+  /** defaults to a single use */
+  max_uses?: number;
+}
+
+export interface CreateInviteResponse {
+  code: string;
+  created_at: number;
+  expires_at: number;
+  id: string;
+  max_uses: number;
+}
+
+export interface CreateNoteRequest {
+  template_id: string;
+  title: string;
+  values?: Record<string, unknown>;
+}
+
+export interface CreatePersonalTokenRequest {
+  /** omit for a token that never expires */
+  expires_in_days?: number;
+  name: string;
+  scopes: string[];
+}
+
+export interface CreatePersonalTokenResponse {
+  created_at: number;
+  expires_at?: number;
+  id: string;
+  name: string;
+  scopes: string[];
+  token: string;
+}
+
+export interface CreateProjectRequest {
+  cover_image_url?: string;
+  genre?: string;
+  /** planning when omitted */
+  status?: "planning" | "drafting" | "revising" | "completed" | "on_hold";
+  synopsis?: string;
+  target_word_count?: number;
+  title: string;
+}
+
+export interface CreateTemplateRequest {
+  description?: string;
+  name: string;
+  rows: TemplateRow[];
+}
+
+export interface DeleteAccountResponse {
+  /** PurgeAt is the Unix time after which the account and all its content are removed. Logging in before then cancels the deletion. */
+  purge_at: number;
+}
+
+export interface Document {
+  description?: string;
+  format: string;
+  name: string;
+  rows: DocumentRow[];
+  schema_version: number;
+}
+
+export interface DocumentField {
+  description?: string;
+  kind: "short_text" | "long_text" | "number" | "date" | "boolean" | "single_select" | "multi_select" | "url" | "reference";
+  name: string;
+  options: Options;
+}
+
+export interface DocumentRow {
+  slots: DocumentSlot[];
+}
+
+export interface DocumentSlot {
+  default?: unknown;
+  field: DocumentField;
+  required?: boolean;
+  width: number;
+}
+
+export interface ExportJobResponse {
+  completed_at?: number;
+  created_at: number;
+  download_url?: string;
+  error?: string;
+  expires_at?: number;
+  id: string;
+  size?: number;
+  status: string;
+}
+
+export interface Field {
+  created_at: number;
+  description?: string;
+  id: string;
+  kind: "short_text" | "long_text" | "number" | "date" | "boolean" | "single_select" | "multi_select" | "url" | "reference";
+  name: string;
+  options: Options;
+  owner_id: string;
+  updated_at: number;
+}
+
+export interface FieldOptions {
+  /** Choices are the values of a single or multi select field. */
+  choices?: string[];
+  integer?: boolean;
+  max?: number;
+  max_date?: string;
+  max_length?: number;
+  max_selected?: number;
+  /** Min and Max bound number values; Integer rejects fractions. */
+  min?: number;
+  /** MinDate and MaxDate bound date values, as YYYY-MM-DD. */
+  min_date?: string;
+  /** MinLength and MaxLength bound text values, in characters. */
+  min_length?: number;
+  /** MinSelected and MaxSelected bound how many choices a multi select value has. */
+  min_selected?: number;
+  /** Pattern is a regular expression short text values must match in full. */
+  pattern?: string;
+  /** Required rejects empty values. Applies to every kind. */
+  required?: boolean;
+  /** Schemes restricts URL values; http and https when empty. */
+  schemes?: string[];
+  /** TargetTemplateID restricts reference values to notes of this template. */
+  target_template_id?: string;
+}
+
+export interface Highlight {
+  field_id?: string;
+  snippet: string;
+}
+
+export interface Hit {
+  highlights: Highlight[];
+  note_id: string;
+  project_id: string;
+  score: number;
+  template_id: string;
+  title: string;
+}
+
+export interface Identity {
+  linked_at: number;
+  provider: string;
+  subject: string;
+}
+
+export interface IdentityProvidersResponse {
+  providers: string[];
+}
+
+export interface ImportField {
+  description?: string;
+  kind: "short_text" | "long_text" | "number" | "date" | "boolean" | "single_select" | "multi_select" | "url" | "reference";
+  name: string;
+  options: FieldOptions;
+}
+
+export interface ImportReport {
+  created: Template[];
+  /** keys of starters whose name is already used in the project */
+  skipped: string[];
+}
+
+export interface ImportRow {
+  slots: ImportSlot[];
+}
+
+export interface ImportSlot {
+  default?: unknown;
+  field: ImportField;
+  required?: boolean;
+  width?: number;
+}
+
+export interface ImportStartersRequest {
+  /** keys of the starters, all when empty */
+  starters?: string[];
+}
+
+export interface ImportTemplateRequest {
+  description?: string;
+  format: string;
+  name: string;
+  rows: ImportRow[];
+  schema_version: number;
+}
+
+export interface InviteCode {
+  created_at: number;
+  created_by: string;
+  expires_at: number;
+  id: string;
+  max_uses: number;
+  /** leading characters of the code, to recognise it */
+  prefix: string;
+  uses: number;
+}
+
+export interface Link {
+  dangling?: boolean;
+  field_id: string;
+  note_id: string;
+}
+
+export interface LoginRequest {
+  /** DeviceName labels the session; derived from the user agent when empty. */
+  device_name?: string;
+  password: string;
+  username: string;
+}
+
+export interface LoginResponse {
+  token: string;
+}
+
+export interface Note {
+  /** values moved out by upgrades, by field ID */
+  archived?: Record<string, unknown>;
+  created_at: number;
+  id: string;
+  /** reference values and wiki links, kept for backlinks */
+  links?: Link[];
+  owner_id: string;
+  project_id: string;
+  template_id: string;
+  template_version: number;
+  title: string;
+  updated_at: number;
+  /** by field ID, without empty values */
+  values: Record<string, unknown>;
+  /** of long text values, by field ID */
+  word_counts?: Record<string, number>;
+}
+
+export interface NotePage {
+  next_cursor?: string;
+  notes: Note[];
+}
+
+export interface OIDCCallbackRequest {
+  code: string;
+  state: string;
+}
+
+export interface OIDCStartResponse {
+  authorization_url: string;
+}
+
+export interface Options {
+  choices?: string[];
+  integer?: boolean;
+  max?: number;
+  /** YYYY-MM-DD */
+  max_date?: string;
+  max_length?: number;
+  max_selected?: number;
+  min?: number;
+  /** YYYY-MM-DD */
+  min_date?: string;
+  min_length?: number;
+  min_selected?: number;
+  /** must match the whole value */
+  pattern?: string;
+  required?: boolean;
+  /** http and https when empty */
+  schemes?: string[];
+  /** TargetTemplateID restricts reference values to notes of this template. */
+  target_template_id?: string;
+}
+
+export interface PersonalAccessToken {
+  created_at: number;
+  /** zero means never */
+  expires_at?: number;
+  id: string;
+  last_used_at?: number;
+  name: string;
+  /** leading characters of the secret, to recognise it */
+  prefix: string;
+  scopes: string[];
+}
+
+export interface Project {
+  cover_image_url?: string;
+  created_at: number;
+  genre?: string;
+  id: string;
+  owner_id: string;
+  status: "planning" | "drafting" | "revising" | "completed" | "on_hold";
+  synopsis?: string;
+  target_word_count?: number;
+  title: string;
+  updated_at: number;
+}
+
+export interface RegisterRequest {
+  email: string;
+  /** InviteCode is required while registration is invite-only. */
+  invite_code?: string;
+  /** strength is checked by the password policy */
+  password: string;
+  username: string;
+}
+
+export interface ReindexReport {
+  indexed: number;
+}
+
+export interface RenderedNote {
+  /** by field ID */
+  html: Record<string, string>;
+  note_id: string;
+  /** by field ID */
+  word_counts: Record<string, number>;
+}
+
+export interface Result {
+  hits: Hit[];
+  next_offset?: number;
+  total: number;
+}
+
+export interface Row {
+  slots: Slot[];
+}
+
+export interface Session {
+  created_at: number;
+  /** whether the listing request was made with this session */
+  current: boolean;
+  device_label: string;
+  expires_at: number;
+  id: string;
+  ip: string;
+  last_seen_at: number;
+  user_agent: string;
+}
+
+export interface Slot {
+  default?: unknown;
+  field_id: string;
+  required?: boolean;
+  /** columns spanned, 1 to 3 */
+  width: number;
+}
+
+export interface Starter {
+  description: string;
+  /** name of its data file, e.g. "scene_card" */
+  key: string;
+  name: string;
+  rows: DocumentRow[];
+}
+
+export interface Template {
+  created_at: number;
+  description?: string;
+  id: string;
+  name: string;
+  owner_id: string;
+  project_id: string;
+  rows: Row[];
+  updated_at: number;
+  /** incremented by every update, starting at 1 */
+  version: number;
+}
+
+export interface TemplateRow {
+  slots: TemplateSlot[];
+}
+
+export interface TemplateSlot {
+  /** Default is the initial value of the field in new notes. It must be a valid value of the field. */
+  default?: unknown;
+  field_id: string;
+  /** Required makes the field required in notes of this template, even if the field definition itself is optional. */
+  required?: boolean;
+  /** Width is the number of columns the slot spans, 1 when omitted. The widths of a row add up to at most three. */
+  width?: number;
+}
+
+export interface UpdateFieldRequest {
+  description?: string;
+  name: string;
+  options: FieldOptions;
+}
+
+export interface UpdateNoteRequest {
+  title: string;
+  values?: Record<string, unknown>;
+}
+
+export interface UpdateProjectRequest {
+  cover_image_url?: string;
+  genre?: string;
+  status: "planning" | "drafting" | "revising" | "completed" | "on_hold";
+  synopsis?: string;
+  target_word_count?: number;
+  title: string;
+}
+
+export interface UpdateTemplateRequest {
+  description?: string;
+  name: string;
+  /** Renames maps the ID of a field removed by this version to the ID of the field added in its place. Upgrading notes moves their values accordingly. */
+  renames?: Record<string, string>;
+  rows: TemplateRow[];
+}
+
+export interface UpgradeNotesRequest {
+  dry_run?: boolean;
+  force?: boolean;
+  /** all outdated notes when empty */
+  note_ids?: string[];
+  template_id: string;
+}
+
+export interface UpgradeReport {
+  conflicts: Conflict[];
+  dry_run: boolean;
+  template_version: number;
+  /** IDs of the notes moved to TemplateVersion */
+  upgraded: string[];
+}
+
+export interface User {
+  created_at: number;
+  /** set when the owner requested deletion */
+  deleted_at?: number;
+  disabled: boolean;
+  email: string;
+  id: string;
+  /** Identities are the external OpenID Connect accounts linked to this user. */
+  identities?: Identity[];
+  /** when the account and its content are removed for good */
+  purge_at?: number;
+  roles: string[];
+  updated_at: number;
+  username: string;
+  /** email address confirmed */
+  verified: boolean;
+}
+
+export interface UserPage {
+  next_cursor?: string;
+  users: User[];
+}
+
+export interface Version {
+  created_at: number;
+  description?: string;
+  name: string;
+  owner_id: string;
+  /** Renames maps fields removed by this version to the fields that replace them. */
+  renames?: Record<string, string>;
+  rows: Row[];
+  template_id: string;
+  version: number;
+}
+
+/** Body of an error response; most carry a message in error. */
+export type ErrorBody = { error?: string } & Record<string, unknown>;
+
+/** ApiError is thrown for every response with a non-2xx status. */
+export class ApiError extends Error {
+  readonly status: number;
+  readonly body: ErrorBody;
+
+  constructor(status: number, body: ErrorBody) {
+    super(body.error ?? `Request failed with status ${status}`);
+    this.name = "ApiError";
+    this.status = status;
+    this.body = body;
+  }
+}
+
+export interface ClientOptions {
+  /** Origin of the API, such as "http://localhost:8080". */
+  baseUrl: string;
+  /** Returns the login or personal access token sent to protected operations. */
+  token?: () => string | null | undefined;
+  fetch?: typeof fetch;
+}
+
+type Query = Record<string, string | number | boolean | undefined>;
+
+interface RequestOptions {
+  query?: Query;
+  body?: unknown;
+  auth?: boolean;
+  blob?: boolean;
+}
+
+function requester(options: ClientOptions) {
+  const doFetch = options.fetch ?? fetch;
+  return async <T>(method: string, path: string, init: RequestOptions = {}): Promise<T> => {
+    const url = new URL(path, options.baseUrl);
+    for (const [key, value] of Object.entries(init.query ?? {})) {
+      if (value !== undefined && value !== "") {
+        url.searchParams.set(key, String(value));
+      }
+    }
+
+    const headers: Record<string, string> = { Accept: "application/json" };
+    if (init.body !== undefined) {
+      headers["Content-Type"] = "application/json";
+    }
+    const token = init.auth ? options.token?.() : undefined;
+    if (token) {
+      headers.Authorization = `Bearer ${token}`;
+    }
+
+    const response = await doFetch(url, {
+      method,
+      headers,
+      body: init.body === undefined ? undefined : JSON.stringify(init.body),
+    });
+    if (!response.ok) {
+      const body = await response.json().catch(() => ({ error: response.statusText }));
+      throw new ApiError(response.status, body as ErrorBody);
+    }
+    if (init.blob) {
+      return (await response.blob()) as T;
+    }
+    if (response.status === 204) {
+      return undefined as T;
+    }
+    return (await response.json()) as T;
+  };
+}
+
+export function createClient(options: ClientOptions) {
+  const request = requester(options);
+  return {
+    /**
+     * List security events
+     *
+     * Returns a page of authentication events of all users, newest first. Requires the audit:read permission.
+     */
+    listAuditEvents: (query?: { user_id?: string; action?: string; outcome?: "success" | "failure"; cursor?: string; limit?: number }) =>
+      request<AuditPage>("GET", "/v1/admin/audit", { query, auth: true }),
+    /**
+     * List invite codes
+     *
+     * Returns all invite codes, newest first. The codes themselves are not included. Requires the users:manage permission.
+     */
+    listInvites: () =>
+      request<InviteCode[]>("GET", "/v1/admin/invites", { auth: true }),
+    /**
+     * Create an invite code
+     *
+     * Creates an expiring, single or multi-use invite code. The code is only returned once. Requires the users:manage permission.
+     */
+    createInvite: (body: CreateInviteRequest) =>
+      request<CreateInviteResponse>("POST", "/v1/admin/invites", { body, auth: true }),
+    /**
+     * Revoke an invite code
+     *
+     * Deletes an invite code so that it can no longer be used. Requires the users:manage permission.
+     */
+    revokeInvite: (id: string) =>
+      request<void>("DELETE", `/v1/admin/invites/${encodeURIComponent(id)}`, { auth: true }),
+    /**
+     * List users
+     *
+     * Returns a page of user accounts, newest first. Requires the users:manage permission.
+     */
+    listUsers: (query?: { q?: string; role?: "author" | "admin"; verified?: boolean; created_after?: number; created_before?: number; cursor?: string; limit?: number }) =>
+      request<UserPage>("GET", "/v1/admin/users", { query, auth: true }),
+    /**
+     * Disable a user
+     *
+     * Prevents the user from logging in. Requires the users:manage permission.
+     */
+    disableUser: (id: string) =>
+      request<User>("POST", `/v1/admin/users/${encodeURIComponent(id)}/disable`, { auth: true }),
+    /**
+     * Re-enable a user
+     *
+     * Allows a disabled user to log in again. Requires the users:manage permission.
+     */
+    enableUser: (id: string) =>
+      request<User>("POST", `/v1/admin/users/${encodeURIComponent(id)}/enable`, { auth: true }),
+    /**
+     * Log in a user
+     *
+     * Authenticates a user and returns a JWT token.
+     */
+    login: (body: LoginRequest) =>
+      request<LoginResponse>("POST", "/v1/auth/login", { body }),
+    /**
+     * List identity providers
+     *
+     * Returns the names of the OpenID Connect providers users can sign in with.
+     */
+    listIdentityProviders: () =>
+      request<IdentityProvidersResponse>("GET", "/v1/auth/oidc"),
+    /**
+     * Complete an external sign in
+     *
+     * Exchanges the code for the provider's ID token, links or creates the user and returns a JWT token.
+     */
+    completeOIDCLogin: (provider: string, body: OIDCCallbackRequest) =>
+      request<LoginResponse>("POST", `/v1/auth/oidc/${encodeURIComponent(provider)}/callback`, { body }),
+    /**
+     * Start an external sign in
+     *
+     * Returns the provider URL the browser must be sent to. The provider redirects back to the frontend with state and code.
+     */
+    startOIDCLogin: (provider: string) =>
+      request<OIDCStartResponse>("POST", `/v1/auth/oidc/${encodeURIComponent(provider)}/start`),
+    /**
+     * Register a new user
+     *
+     * Creates a new user account, subject to the registration mode and allowed email domains.
+     */
+    register: (body: RegisterRequest) =>
+      request<User>("POST", "/v1/auth/register", { body }),
+    /**
+     * List my fields
+     *
+     * Returns every field definition of the caller, sorted by name.
+     */
+    listFields: () =>
+      request<Field[]>("GET", "/v1/fields", { auth: true }),
+    /**
+     * Create a field
+     *
+     * Creates a field definition. Only the options that apply to the kind may be set: min_length, max_length and pattern for text; min, max and integer for numbers; min_date and max_date for dates; choices, min_selected and max_selected for selects; schemes for URLs.
+     */
+    createField: (body: CreateFieldRequest) =>
+      request<Field>("POST", "/v1/fields", { body, auth: true }),
+    /**
+     * Delete a field
+     *
+     * Deletes a field definition. A field that templates still place cannot be deleted.
+     */
+    deleteField: (id: string) =>
+      request<void>("DELETE", `/v1/fields/${encodeURIComponent(id)}`, { auth: true }),
+    /** Get a field */
+    getField: (id: string) =>
+      request<Field>("GET", `/v1/fields/${encodeURIComponent(id)}`, { auth: true }),
+    /**
+     * Update a field
+     *
+     * Replaces the name, description and options of a field. The kind of a field cannot be changed.
+     */
+    updateField: (id: string, body: UpdateFieldRequest) =>
+      request<Field>("PUT", `/v1/fields/${encodeURIComponent(id)}`, { body, auth: true }),
+    /**
+     * Delete my account
+     *
+     * Schedules the account and all content it owns for deletion after a grace period. Logging in again before then cancels the deletion.
+     */
+    deleteAccount: () =>
+      request<DeleteAccountResponse>("DELETE", "/v1/me", { auth: true }),
+    /**
+     * Get my profile
+     *
+     * Returns the account of the authenticated user.
+     */
+    getProfile: () =>
+      request<User>("GET", "/v1/me", { auth: true }),
+    /**
+     * List my security events
+     *
+     * Returns a page of authentication events where the caller is the actor or the target, newest first.
+     */
+    listMyAuditEvents: (query?: { action?: string; outcome?: "success" | "failure"; cursor?: string; limit?: number }) =>
+      request<AuditPage>("GET", "/v1/me/audit", { query, auth: true }),
+    /**
+     * Get my data export
+     *
+     * Returns the current export of the caller's profile and content, starting one if none is pending or downloadable. Poll until the status is completed, then follow download_url.
+     */
+    getExport: () =>
+      request<ExportJobResponse>("GET", "/v1/me/export", { auth: true }),
+    /**
+     * Start a new data export
+     *
+     * Starts a new export of the caller's profile and content unless one is already in progress.
+     */
+    startExport: () =>
+      request<ExportJobResponse>("POST", "/v1/me/export", { auth: true }),
+    /**
+     * Download a data export
+     *
+     * Streams the ZIP archive of a completed export.
+     */
+    downloadExport: (id: string) =>
+      request<Blob>("GET", `/v1/me/export/${encodeURIComponent(id)}/download`, { auth: true, blob: true }),
+    /**
+     * Change my password
+     *
+     * Replaces the caller's password and logs out all other sessions.
+     */
+    changePassword: (body: ChangePasswordRequest) =>
+      request<void>("POST", "/v1/me/password", { body, auth: true }),
+    /**
+     * List my sessions
+     *
+     * Returns the devices the caller is logged in on. The session making the request is marked as current.
+     */
+    listSessions: () =>
+      request<Session[]>("GET", "/v1/me/sessions", { auth: true }),
+    /**
+     * Revoke a session
+     *
+     * Logs the caller out on one device; tokens of the session stop working immediately.
+     */
+    revokeSession: (id: string) =>
+      request<void>("DELETE", `/v1/me/sessions/${encodeURIComponent(id)}`, { auth: true }),
+    /**
+     * List my personal access tokens
+     *
+     * Returns the caller's personal access tokens without their secrets.
+     */
+    listPersonalTokens: () =>
+      request<PersonalAccessToken[]>("GET", "/v1/me/tokens", { auth: true }),
+    /**
+     * Create a personal access token
+     *
+     * Creates a scoped token for scripts and integrations. The secret is only returned once.
+     */
+    createPersonalToken: (body: CreatePersonalTokenRequest) =>
+      request<CreatePersonalTokenResponse>("POST", "/v1/me/tokens", { body, auth: true }),
+    /**
+     * Revoke a personal access token
+     *
+     * Deletes one of the caller's personal access tokens; it stops working immediately.
+     */
+    revokePersonalToken: (id: string) =>
+      request<void>("DELETE", `/v1/me/tokens/${encodeURIComponent(id)}`, { auth: true }),
+    /**
+     * Upgrade notes to the latest template version
+     *
+     * Moves the outdated notes of a template, or the listed ones, to its latest version. Values follow the field renames recorded by the versions in between, and values of fields the latest version no longer places move to the archived section of the note. Notes with values that do not fit the latest version are reported as conflicts and left alone, unless force is set: then the offending values are archived too. With dry_run, nothing is saved.
+     */
+    upgradeNotes: (body: UpgradeNotesRequest) =>
+      request<UpgradeReport>("POST", "/v1/notes/upgrade", { body, auth: true }),
+    /**
+     * Delete a note
+     *
+     * Deletes a note. References to it from other notes are kept but marked as dangling.
+     */
+    deleteNote: (id: string) =>
+      request<void>("DELETE", `/v1/notes/${encodeURIComponent(id)}`, { auth: true }),
+    /** Get a note */
+    getNote: (id: string) =>
+      request<Note>("GET", `/v1/notes/${encodeURIComponent(id)}`, { auth: true }),
+    /**
+     * Update a note
+     *
+     * Replaces the title and values of a note. Values are checked against the template version the note is pinned to; upgrade the note to use a newer version. Reference values must be IDs of notes of the same project; dangling references to deleted notes may be kept as they are.
+     */
+    updateNote: (id: string, body: UpdateNoteRequest) =>
+      request<Note>("PUT", `/v1/notes/${encodeURIComponent(id)}`, { body, auth: true }),
+    /**
+     * List the backlinks of a note
+     *
+     * Returns the notes that refer to a note through a reference field or a wiki link in a long text field, sorted by title, once per referring field.
+     */
+    listBacklinks: (id: string) =>
+      request<Backlink[]>("GET", `/v1/notes/${encodeURIComponent(id)}/backlinks`, { auth: true }),
+    /**
+     * Render the long text values of a note
+     *
+     * Renders the long text values of a note, stored as CommonMark Markdown, to sanitized HTML, along with their word counts. Raw HTML is left out. [[Title]] and [[Title|label]] wiki links become links to the note of the project with that title, or plain text marked as missing.
+     */
+    renderNote: (id: string) =>
+      request<RenderedNote>("GET", `/v1/notes/${encodeURIComponent(id)}/render`, { auth: true }),
+    /**
+     * List my projects
+     *
+     * Returns every writing project of the caller, sorted by title.
+     */
+    listProjects: () =>
+      request<Project[]>("GET", "/v1/projects", { auth: true }),
+    /**
+     * Create a project
+     *
+     * Creates a writing project. Templates and notes are created inside a project. The status defaults to planning.
+     */
+    createProject: (body: CreateProjectRequest) =>
+      request<Project>("POST", "/v1/projects", { body, auth: true }),
+    /**
+     * Delete a project
+     *
+     * Deletes a project together with every template and note inside it.
+     */
+    deleteProject: (id: string) =>
+      request<void>("DELETE", `/v1/projects/${encodeURIComponent(id)}`, { auth: true }),
+    /** Get a project */
+    getProject: (id: string) =>
+      request<Project>("GET", `/v1/projects/${encodeURIComponent(id)}`, { auth: true }),
+    /**
+     * Update a project
+     *
+     * Replaces the title, genre, synopsis, target word count, cover image and status of a project.
+     */
+    updateProject: (id: string, body: UpdateProjectRequest) =>
+      request<Project>("PUT", `/v1/projects/${encodeURIComponent(id)}`, { body, auth: true }),
+    /**
+     * List the notes of a project
+     *
+     * Returns one page of the notes of a project of the caller, newest first, optionally filtered by title, template or the value of a field.
+     */
+    listNotes: (id: string, query?: { q?: string; template_id?: string; field_id?: string; value?: string; cursor?: string; limit?: number }) =>
+      request<NotePage>("GET", `/v1/projects/${encodeURIComponent(id)}/notes`, { query, auth: true }),
+    /**
+     * Create a note
+     *
+     * Fills in a template of a project of the caller. Values are keyed by field ID and must be valid values of their field; fields left out start with the default of their slot. Reference values must be IDs of notes of the same project, of the target template of the field if it has one.
+     */
+    createNote: (id: string, body: CreateNoteRequest) =>
+      request<Note>("POST", `/v1/projects/${encodeURIComponent(id)}/notes`, { body, auth: true }),
+    /**
+     * List the templates of a project
+     *
+     * Returns every template of a project of the caller, sorted by name.
+     */
+    listTemplates: (id: string) =>
+      request<Template[]>("GET", `/v1/projects/${encodeURIComponent(id)}/templates`, { auth: true }),
+    /**
+     * Create a template
+     *
+     * Creates a template in a project of the caller from rows of the caller's fields. Template names are unique within a project. Each row holds one to three slots whose widths add up to at most three columns; each field is placed once and its default must be a valid value of it.
+     */
+    createTemplate: (id: string, body: CreateTemplateRequest) =>
+      request<Template>("POST", `/v1/projects/${encodeURIComponent(id)}/templates`, { body, auth: true }),
+    /**
+     * Import starter templates
+     *
+     * Creates the listed starter templates, or all of them when none are listed, in a project of the caller. Fields of the caller with the names a starter uses are reused; missing ones are created. Starters whose name is already used in the project are skipped. The body may be omitted.
+     */
+    importStarters: (id: string, body: ImportStartersRequest) =>
+      request<ImportReport>("POST", `/v1/projects/${encodeURIComponent(id)}/templates/import-starter`, { body, auth: true }),
+    /**
+     * Search notes
+     *
+     * Searches the titles and text values of the notes of the caller. Results contain every word and "quoted phrase" of the query, ignoring case and punctuation, best first. Highlights hold the matching title and values, HTML-escaped, with the matches wrapped in <mark>.
+     */
+    search: (query: { q: string; project_id?: string; template_id?: string; offset?: number; limit?: number }) =>
+      request<Result>("GET", "/v1/search", { query, auth: true }),
+    /**
+     * Rebuild the search index
+     *
+     * Rebuilds the search index of the notes of the caller, such as after switching indexes or for notes written before search was available.
+     */
+    reindex: () =>
+      request<ReindexReport>("POST", "/v1/search/reindex", { auth: true }),
+    /**
+     * Import a template
+     *
+     * Creates a template in a project of the caller from an exported template document. Fields of the caller with the names the document uses are reused and must be of the same kind; missing ones are created. Documents of another format or of a newer schema version are rejected.
+     */
+    importTemplate: (body: ImportTemplateRequest, query: { project_id: string }) =>
+      request<Template>("POST", "/v1/templates/import", { body, query, auth: true }),
+    /**
+     * List starter templates
+     *
+     * Returns the built-in starter templates that can be imported into a project, sorted by key. Their slots define fields by name instead of referring to fields of the caller.
+     */
+    listStarters: () =>
+      request<Starter[]>("GET", "/v1/templates/starters", { auth: true }),
+    /**
+     * Delete a template
+     *
+     * Deletes a template and all of its versions. A template that notes are still filled in from cannot be deleted.
+     */
+    deleteTemplate: (id: string) =>
+      request<void>("DELETE", `/v1/templates/${encodeURIComponent(id)}`, { auth: true }),
+    /** Get a template */
+    getTemplate: (id: string) =>
+      request<Template>("GET", `/v1/templates/${encodeURIComponent(id)}`, { auth: true }),
+    /**
+     * Update a template
+     *
+     * Replaces the name, description and layout of a template and creates its next version. Existing notes stay on their version until they are upgraded; renames tell the upgrade which added field takes over the values of a removed one.
+     */
+    updateTemplate: (id: string, body: UpdateTemplateRequest) =>
+      request<Template>("PUT", `/v1/templates/${encodeURIComponent(id)}`, { body, auth: true }),
+    /**
+     * Export a template
+     *
+     * Returns a template of the caller in the portable interchange format, with the definitions of its fields instead of their IDs, so that other users can import it.
+     */
+    exportTemplate: (id: string) =>
+      request<Document>("GET", `/v1/templates/${encodeURIComponent(id)}/export`, { auth: true }),
+    /**
+     * List template versions
+     *
+     * Returns every version of a template, oldest first. Versions are immutable.
+     */
+    listVersions: (id: string) =>
+      request<Version[]>("GET", `/v1/templates/${encodeURIComponent(id)}/versions`, { auth: true }),
+    /** Get a template version */
+    getVersion: (id: string, version: number) =>
+      request<Version>("GET", `/v1/templates/${encodeURIComponent(id)}/versions/${encodeURIComponent(version)}`, { auth: true }),
+  };
+}
+
+export type Client = ReturnType<typeof createClient>;
@@ -1,29 +1,23 @@
-import axios from 'axios';
-
-const API_URL = 'http://localhost:8080/v1/auth'; //TODO: move to env file
-
-
-interface LoginPayload {
-  username: string; 
-  password: string;
-}
-
-interface RegisterPayload {
-  username: string; 
-  email: string;
-  password: string;
-}
-
-
-interface LoginResponse {
-    token: string;
-}
+import {
+  createClient,
+  type LoginRequest,
+  type LoginResponse,
+  type RegisterRequest,
+  type User,
+} from './api.gen';
+
+const API_URL = 'http://localhost:8080'; //TODO: move to env file
+
+// Client for the whole API; request and response types are generated from the backend DTOs.
+export const api = createClient({
+  baseUrl: API_URL,
+  token: () => localStorage.getItem('authToken'),
+});
 
 export const authService = {
-  async login(payload: LoginPayload): Promise<LoginResponse> {
+  async login(payload: LoginRequest): Promise<LoginResponse> {
     try {
-      const response = await axios.post<LoginResponse>(`${API_URL}/login`, payload);
-      return response.data;
+      return await api.login(payload);
     } catch (error) {
       // Handle or re-throw error for the component to catch
       console.error('Login failed:', error);
@@ -31,10 +25,9 @@ export const authService = {
     }
   },
 
-  async register(payload: RegisterPayload): Promise<any> { // Adjust 'any' to a specific response type if known
+  async register(payload: RegisterRequest): Promise<User> {
     try {
-      const response = await axios.post(`${API_URL}/register`, payload);
-      return response.data;
+      return await api.register(payload);
     } catch (error) {
       console.error('Registration failed:', error);
       throw error;